@@ -13,8 +13,10 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/joho/godotenv"
 
+	"stock-tracker/internal/domain/entities"
 	"stock-tracker/internal/domain/usecases"
 	"stock-tracker/internal/infrastructure/auth"
+	"stock-tracker/internal/infrastructure/clients"
 	"stock-tracker/internal/infrastructure/config"
 	"stock-tracker/internal/infrastructure/database"
 	infraMiddleware "stock-tracker/internal/infrastructure/middleware"
@@ -52,6 +54,26 @@ func main() {
 	userRepo := database.NewUserRepository(dbPool.GetPool(), log)
 	sessionRepo := database.NewSessionRepository(dbPool.GetPool())
 	subscriptionRepo := database.NewSubscriptionRepository(dbPool.GetPool(), log)
+	ingestionLogRepo := database.NewIngestionLogRepository(dbPool.GetPool(), log)
+	deadLetterRepo := database.NewDeadLetterRepository(dbPool.GetPool(), log)
+	normalizationRepo := database.NewNormalizationRepository(dbPool.GetPool(), log)
+	qualitySummaryRepo := database.NewQualitySummaryRepository(dbPool.GetPool(), log)
+	outboxRepo := database.NewOutboxRepository(dbPool.GetPool(), log)
+	stockChangeRepo := database.NewStockChangeRepository(dbPool.GetPool(), log)
+
+	// Load the exchange rates targets are converted with
+	fxRates, err := clients.LoadFXRates(cfg.FXRatesFiles)
+	if err != nil {
+		log.Error("Failed to load exchange rates", "error", err)
+		panic(err)
+	}
+
+	// Load the data-quality rules re-submitted dead letters are checked against, the same as ingestion
+	qualityRules, err := clients.LoadQualityRules(cfg.QualityRules)
+	if err != nil {
+		log.Error("Invalid data-quality rules", "error", err)
+		panic(err)
+	}
 
 	// Initialize JWT service
 	jwtSecret := os.Getenv("JWT_SECRET")
@@ -62,20 +84,53 @@ func main() {
 	jwtService := auth.NewJWTService(jwtSecret)
 
 	// Initialize use cases
-	stockQueryUC := usecases.NewStockQueryUseCase(stockRepo, brokerRepo, log)
+	stockQueryUC := usecases.NewStockQueryUseCase(stockRepo, brokerRepo, fxRates, log)
+	stockEditUC := usecases.NewStockEditingUseCase(stockRepo, brokerRepo, normalizationRepo, stockChangeRepo, log)
 	userUC := usecases.NewUserUseCase(userRepo, subscriptionRepo, sessionRepo, jwtService, log)
-	// subscriptionUC := usecases.NewSubscriptionUseCase(subscriptionRepo, userRepo, log) // TODO: Use when subscription handler is implemented
+	ingestionRunUC := usecases.NewIngestionRunQueryUseCase(ingestionLogRepo, qualitySummaryRepo, log)
+	deadLetterUC := usecases.NewDeadLetterReviewUseCase(deadLetterRepo, stockRepo, brokerRepo, normalizationRepo, qualityRules, log)
+	normalizationUC := usecases.NewNormalizationRulesUseCase(normalizationRepo, log)
+	brokerUC := usecases.NewBrokerAdminUseCase(brokerRepo, log)
+	subscriptionUC := usecases.NewSubscriptionUseCase(subscriptionRepo, userRepo, log)
 
 	// Initialize middleware
 	authMiddleware := infraMiddleware.NewAuthMiddleware(jwtService, log)
 	rateLimiter := infraMiddleware.NewRateLimiter(log)
 
+	// Deliver the domain events written to the outbox, by this server and by the ingestor
+	eventDispatcher := usecases.NewEventDispatcher(outboxRepo, cfg.OutboxMaxAttempts, log)
+	eventDispatcher.Subscribe("event-log", func(ctx context.Context, event *entities.DomainEvent) error {
+		log.Info("Domain event", "type", event.Type, "id", event.ID, "aggregateID", event.AggregateID)
+		return nil
+	})
+	// A user keeps the rate limiter of their old tier until it is dropped, even with a token for the new one
+	eventDispatcher.Subscribe("rate-limit-tier", func(ctx context.Context, event *entities.DomainEvent) error {
+		var payload entities.SubscriptionPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		rateLimiter.Forget(payload.UserID.String())
+		return nil
+	}, entities.EventSubscriptionActivated)
+	dispatcherCtx, stopDispatcher := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		eventDispatcher.Run(dispatcherCtx, cfg.OutboxPollInterval)
+	}()
+
 	// Initialize handlers
 	stockHandler := handlers.NewStockHandler(stockQueryUC, log)
+	stockEditHandler := handlers.NewStockEditHandler(stockEditUC, log)
 	authHandler := handlers.NewAuthHandler(userUC, log)
+	ingestionHandler := handlers.NewIngestionHandler(ingestionRunUC, log)
+	deadLetterHandler := handlers.NewDeadLetterHandler(deadLetterUC, log)
+	normalizationHandler := handlers.NewNormalizationHandler(normalizationUC, log)
+	brokerHandler := handlers.NewBrokerHandler(brokerUC, log)
+	subscriptionHandler := handlers.NewSubscriptionHandler(*subscriptionUC, log)
 
 	// Initialize router
-	r := setupRouter(stockHandler, authHandler, authMiddleware, rateLimiter, log, dbPool)
+	r := setupRouter(stockHandler, stockEditHandler, authHandler, ingestionHandler, deadLetterHandler, normalizationHandler, brokerHandler, subscriptionHandler, authMiddleware, rateLimiter, log, dbPool)
 
 	// Configure server
 	server := &http.Server{
@@ -109,6 +164,9 @@ func main() {
 		log.Error("Failed to shutdown server", "error", err)
 	}
 
+	stopDispatcher()
+	<-dispatcherDone
+
 	log.Info("Server stopped")
 }
 
@@ -133,7 +191,13 @@ func corsMiddleware(next http.Handler) http.Handler {
 
 func setupRouter(
 	stockHandler *handlers.StockHandler,
+	stockEditHandler *handlers.StockEditHandler,
 	authHandler *handlers.AuthHandler,
+	ingestionHandler *handlers.IngestionHandler,
+	deadLetterHandler *handlers.DeadLetterHandler,
+	normalizationHandler *handlers.NormalizationHandler,
+	brokerHandler *handlers.BrokerHandler,
+	subscriptionHandler *handlers.SubscriptionHandler,
 	authMiddleware *infraMiddleware.AuthMiddleware,
 	rateLimiter *infraMiddleware.RateLimiter,
 	log logger.Logger,
@@ -177,24 +241,35 @@ func setupRouter(
 				r.Post("/refresh", authHandler.RefreshToken)
 			})
 
-			// Stock routes with optional authentication
+			// Stock routes with optional authentication. Stock events are addressed by ID under /stocks/events
+			// and the events of a ticker under /tickers, so no path segment has two meanings.
 			r.Route("/stocks", func(r chi.Router) {
-				r.Use(authMiddleware.OptionalAuth) // Guest users can access with limitations
-				r.Use(rateLimiter.RateLimit)       // Tier-based rate limiting
-				r.Get("/", stockHandler.GetStocks)
-				r.Get("/{id}", stockHandler.GetStockByID)
-				r.Get("/{ticker}", stockHandler.GetStockByTicker)
-				r.Get("/stats", stockHandler.GetStats)
+				r.Group(func(r chi.Router) {
+					r.Use(authMiddleware.OptionalAuth) // Guest users can access with limitations
+					r.Use(rateLimiter.RateLimit)       // Tier-based rate limiting
+					r.Get("/", stockHandler.GetStocks)
+					r.Get("/stats", stockHandler.GetStats)
+					r.Get("/events/{id}", stockHandler.GetStockByID)
+				})
 
-				// Protected routes for authenticated users
+				// Admin writes to stock events, each recorded with the admin who made it
 				r.Group(func(r chi.Router) {
-					r.Use(authMiddleware.RequireAuth)
-					r.Post("/", stockHandler.CreateStock)
-					r.Put("/{id}", stockHandler.UpdateStock)
-					r.Delete("/{id}", stockHandler.DeleteStock)
+					r.Use(authMiddleware.RequireAdmin)
+					r.Use(rateLimiter.RateLimit)
+					r.Post("/events", stockEditHandler.CreateStock)
+					r.Put("/events/{id}", stockEditHandler.UpdateStock)
+					r.Delete("/events/{id}", stockEditHandler.DeleteStock)
+					r.Get("/events/{id}/changes", stockEditHandler.ListStockChanges)
 				})
 			})
 
+			r.Route("/tickers", func(r chi.Router) {
+				r.Use(authMiddleware.OptionalAuth)
+				r.Use(rateLimiter.RateLimit)
+				r.Get("/{ticker}", stockHandler.GetStockByTicker)
+				r.Get("/{ticker}/consensus", stockHandler.GetTickerConsensus)
+			})
+
 			// Protected user routes
 			r.Route("/user", func(r chi.Router) {
 				r.Use(authMiddleware.RequireAuth)
@@ -206,7 +281,8 @@ func setupRouter(
 			r.Route("/subscriptions", func(r chi.Router) {
 				r.Use(authMiddleware.RequireAuth)
 				r.Use(rateLimiter.RateLimit)
-				// TODO: Add subscription endpoints when handler is implemented
+				r.Post("/", subscriptionHandler.CreateSubscription)
+				r.Post("/{id}/pay", subscriptionHandler.SimulatePayment)
 			})
 
 			// Premium features (AI chat, advanced analytics)
@@ -215,6 +291,53 @@ func setupRouter(
 				r.Use(rateLimiter.RateLimit)
 				// TODO: Add premium endpoints
 			})
+
+			// Admin routes for operating the platform
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(authMiddleware.RequireAdmin)
+				r.Use(rateLimiter.RateLimit)
+
+				// Ingestion run history, used to monitor the ingestor cron
+				r.Route("/ingestion/runs", func(r chi.Router) {
+					r.Get("/", ingestionHandler.ListRuns)
+					r.Get("/last-successful", ingestionHandler.GetLastSuccessfulRun)
+					r.Get("/{batchID}", ingestionHandler.GetRun)
+					r.Get("/{batchID}/quality", ingestionHandler.GetQualitySummary)
+				})
+
+				// Data-quality summaries of ingestion runs
+				r.Get("/ingestion/quality", ingestionHandler.ListQualitySummaries)
+
+				// Upstream records quarantined during ingestion
+				r.Route("/dead-letters", func(r chi.Router) {
+					r.Get("/", deadLetterHandler.ListDeadLetters)
+					r.Get("/{id}", deadLetterHandler.GetDeadLetter)
+					r.Put("/{id}", deadLetterHandler.FixDeadLetter)
+					r.Post("/{id}/resubmit", deadLetterHandler.ResubmitDeadLetter)
+					r.Post("/{id}/discard", deadLetterHandler.DiscardDeadLetter)
+				})
+
+				// Rules mapping broker ratings and actions onto canonical values
+				r.Route("/normalization", func(r chi.Router) {
+					r.Get("/vocabulary", normalizationHandler.GetVocabulary)
+					r.Get("/labels", normalizationHandler.ListLabels)
+					r.Post("/reapply", normalizationHandler.Reapply)
+					r.Get("/rules", normalizationHandler.ListRules)
+					r.Post("/rules", normalizationHandler.CreateRule)
+					r.Put("/rules/{id}", normalizationHandler.UpdateRule)
+					r.Delete("/rules/{id}", normalizationHandler.DeleteRule)
+				})
+
+				// Broker aliases and merges
+				r.Route("/brokers", func(r chi.Router) {
+					r.Get("/duplicates", brokerHandler.SuggestDuplicates)
+					r.Get("/aliases", brokerHandler.ListAliases)
+					r.Post("/aliases", brokerHandler.CreateAlias)
+					r.Delete("/aliases/{id}", brokerHandler.DeleteAlias)
+					r.Get("/merges", brokerHandler.ListMerges)
+					r.Post("/merges", brokerHandler.MergeBrokers)
+				})
+			})
 		})
 	})
 
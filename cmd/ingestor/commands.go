@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/internal/infrastructure/clients"
+	"stock-tracker/pkg/logger"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ingestor runs the commands against one ingestion use case per configured source
+type ingestor struct {
+	sources  []clients.StockSource
+	useCases []*usecases.StockIngestionUseCase
+	runs     usecases.IngestionRunUseCase
+	prices   *usecases.PriceEnrichmentUseCase
+	logger   logger.Logger
+	out      io.Writer
+}
+
+// interruptible returns a context cancelled on SIGINT or SIGTERM, so an interrupted run still records its outcome
+func interruptible() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+}
+
+// runOnce ingests from every source in turn. Sources run one after another so their
+// de-duplication sees each other's events, and one failing source does not stop the rest.
+func (in *ingestor) runOnce() int {
+	ctx, stop := interruptible()
+	defer stop()
+
+	return in.eachSource(func(useCase *usecases.StockIngestionUseCase) (*entities.IngestionLog, error) {
+		return useCase.IngestRun(ctx)
+	})
+}
+
+// backfill reloads the events dated within [from, to] from every source in turn
+func (in *ingestor) backfill(from, to time.Time) int {
+	ctx, stop := interruptible()
+	defer stop()
+
+	return in.eachSource(func(useCase *usecases.StockIngestionUseCase) (*entities.IngestionLog, error) {
+		return useCase.Backfill(ctx, from, to)
+	})
+}
+
+// reprocess re-derives the stored stocks of every source from their raw payloads
+func (in *ingestor) reprocess(filter repositories.PayloadFilter) int {
+	ctx, stop := interruptible()
+	defer stop()
+
+	return in.eachSource(func(useCase *usecases.StockIngestionUseCase) (*entities.IngestionLog, error) {
+		return useCase.Reprocess(ctx, filter)
+	})
+}
+
+// eachSource performs a recorded run per source, prints its outcome and returns the exit code of the worst one
+func (in *ingestor) eachSource(perform func(*usecases.StockIngestionUseCase) (*entities.IngestionLog, error)) int {
+	code := exitOK
+
+	for i, useCase := range in.useCases {
+		name := in.sources[i].Name()
+
+		run, err := perform(useCase)
+		if run != nil {
+			fmt.Fprintf(in.out, "%s: %s, %d records (%d inserted, %d updated, %d duplicates, %d failed)\n",
+				name, run.Status, run.TotalRecords, run.InsertedRecords, run.UpdatedRecords, run.DuplicateRecords, run.FailedRecords)
+		}
+
+		switch {
+		case errors.Is(err, repositories.ErrLeaseHeld):
+			in.logger.Info("Skipped ingestion, another instance holds the lease", "source", name)
+			if code == exitOK {
+				code = exitLocked
+			}
+		case err != nil:
+			in.logger.Error("Ingestion failed", "source", name, "error", err)
+			code = exitFailed
+		case run.FailedRecords > 0 && code == exitOK:
+			code = exitRejected
+		}
+	}
+
+	return code
+}
+
+// enrichPrices fills in the closing price of the stocks selected by filter
+func (in *ingestor) enrichPrices(filter repositories.PricingFilter) int {
+	if in.prices == nil {
+		fmt.Fprintln(os.Stderr, "price enrichment is disabled, set PRICE_PROVIDER to csv or http")
+		return exitUsage
+	}
+
+	ctx, stop := interruptible()
+	defer stop()
+
+	report, err := in.prices.Enrich(ctx, filter)
+	if report != nil {
+		fmt.Fprintf(in.out, "prices: %d tickers, %d stocks (%d priced, %d missing, %d pending)\n",
+			report.Tickers, report.Stocks, report.Priced, report.Missing, report.Pending)
+		if len(report.FailedTickers) > 0 {
+			fmt.Fprintf(in.out, "  failed tickers: %s\n", strings.Join(report.FailedTickers, ", "))
+		}
+	}
+
+	switch {
+	case err != nil:
+		in.logger.Error("Price enrichment failed", "error", err)
+		return exitFailed
+	case len(report.FailedTickers) > 0:
+		return exitRejected
+	default:
+		return exitOK
+	}
+}
+
+// scheduledRun ingests from every source, then prices the new stocks when enrichment is enabled.
+// A replica that found another one ingesting leaves the pricing to it as well.
+func (in *ingestor) scheduledRun() {
+	if in.runOnce() == exitLocked || in.prices == nil {
+		return
+	}
+	in.enrichPrices(repositories.PricingFilter{})
+}
+
+// dryRun prints what the next run of every source would change
+func (in *ingestor) dryRun() int {
+	ctx, stop := interruptible()
+	defer stop()
+
+	code := exitOK
+	for i, useCase := range in.useCases {
+		report, err := useCase.DryRun(ctx)
+		if err != nil {
+			in.logger.Error("Dry run failed", "source", in.sources[i].Name(), "error", err)
+			code = exitFailed
+			continue
+		}
+		in.printReport(report)
+		if len(report.Invalid) > 0 && code == exitOK {
+			code = exitRejected
+		}
+	}
+
+	return code
+}
+
+func (in *ingestor) printReport(report *usecases.DryRunReport) {
+	fmt.Fprintf(in.out, "%s: %d pages, %d new, %d changed, %d unchanged, %d invalid\n",
+		report.Source, report.Pages, report.New, len(report.Changed), report.Unchanged, len(report.Invalid))
+
+	if len(report.NewBrokers) > 0 {
+		fmt.Fprintf(in.out, "  new brokers: %s\n", strings.Join(report.NewBrokers, ", "))
+	}
+	if quality := report.Quality; quality != nil && quality.Passed < quality.Checked {
+		fmt.Fprintf(in.out, "  quality: %d checked, %d warned, %d quarantined, %d rejected (%s)\n", quality.Checked,
+			quality.Warned, quality.Quarantined, quality.Rejected, strings.Join(quality.BrokenRules(), ", "))
+	}
+
+	w := tabwriter.NewWriter(in.out, 0, 0, 2, ' ', 0)
+	for _, diff := range report.Changed {
+		fmt.Fprintf(w, "  changed\t%s\t%s\t%s\n", diff.Ticker, diff.EventTime.Format(time.RFC3339), strings.Join(diff.Fields, ", "))
+	}
+	for _, invalid := range report.Invalid {
+		fmt.Fprintf(w, "  invalid\t%s\t%s\n", invalid.Ticker, invalid.Reason)
+	}
+	w.Flush()
+}
+
+// status prints the most recent runs and the latest checkpoints of every source
+func (in *ingestor) status(recentRuns int) int {
+	ctx := context.Background()
+
+	runs, _, err := in.runs.ListRuns(ctx, recentRuns, 0)
+	if err != nil {
+		in.logger.Error("Failed to list ingestion runs", "error", err)
+		return exitFailed
+	}
+
+	w := tabwriter.NewWriter(in.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BATCH ID\tSTATUS\tSTARTED\tDURATION\tTOTAL\tINSERTED\tUPDATED\tDUPLICATES\tFAILED")
+	for _, run := range runs {
+		duration := "-"
+		if run.CompletedAt != nil {
+			duration = run.CompletedAt.Sub(run.CreatedAt).Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\n", run.BatchID, run.Status, run.CreatedAt.Format(time.RFC3339),
+			duration, run.TotalRecords, run.InsertedRecords, run.UpdatedRecords, run.DuplicateRecords, run.FailedRecords)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "SOURCE\tSTATUS\tPAGES\tRECORDS\tNEXT PAGE\tWATERMARK\tUPDATED")
+	code := exitOK
+	for i, useCase := range in.useCases {
+		checkpoints, err := useCase.Checkpoints(ctx)
+		if err != nil {
+			in.logger.Error("Failed to read checkpoints", "source", in.sources[i].Name(), "error", err)
+			code = exitFailed
+			continue
+		}
+		for _, checkpoint := range checkpoints {
+			nextPage, watermark := "-", "-"
+			if checkpoint.NextPage != "" {
+				nextPage = checkpoint.NextPage
+			}
+			if checkpoint.Watermark != nil {
+				watermark = checkpoint.Watermark.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\n", checkpoint.Source, checkpoint.Status, checkpoint.PagesFetched,
+				checkpoint.RecordsFetched, nextPage, watermark, checkpoint.UpdatedAt.Format(time.RFC3339))
+		}
+	}
+	w.Flush()
+
+	return code
+}
+
+// schema prints the latest schema profile of every source against its baseline, or accepts it as the new baseline
+func (in *ingestor) schema(accept bool) int {
+	ctx := context.Background()
+
+	code := exitOK
+	w := tabwriter.NewWriter(in.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tBATCH ID\tPROFILED\tRECORDS\tBASELINE\tDRIFT")
+	for i, useCase := range in.useCases {
+		name := in.sources[i].Name()
+
+		if accept {
+			profile, err := useCase.AcceptSchema(ctx)
+			if err != nil {
+				in.logger.Error("Failed to accept schema profile", "source", name, "error", err)
+				code = exitFailed
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t-\n", name, profile.BatchID, profile.CreatedAt.Format(time.RFC3339), profile.Records, "accepted")
+			continue
+		}
+
+		latest, baseline, err := useCase.SchemaProfiles(ctx)
+		if err != nil {
+			in.logger.Error("Failed to read schema profiles", "source", name, "error", err)
+			code = exitFailed
+			continue
+		}
+		if latest == nil {
+			fmt.Fprintf(w, "%s\t-\t-\t0\t-\t-\n", name)
+			continue
+		}
+
+		since := "none"
+		if baseline != nil {
+			since = baseline.CreatedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d\n", name, latest.BatchID, latest.CreatedAt.Format(time.RFC3339), latest.Records, since, len(latest.Drift))
+		for _, drift := range latest.Drift {
+			fmt.Fprintf(w, "  drift\t%s\n", drift)
+		}
+		if len(latest.Drift) > 0 && code == exitOK {
+			code = exitRejected
+		}
+	}
+	w.Flush()
+
+	return code
+}
+
+// serve ingests from every source immediately and then on schedule until SIGINT or SIGTERM
+func (in *ingestor) serve(schedule string) int {
+	ctx, stop := interruptible()
+	defer stop()
+
+	// Initialize the cron job (cron scheduler)
+	c := cron.New(cron.WithLogger(cron.VerbosePrintfLogger(log.New(os.Stdout, "cron: ", log.LstdFlags))))
+
+	_, err := c.AddFunc(schedule, in.scheduledRun)
+	if err != nil {
+		in.logger.Error("Failed to schedule ingestion job", "schedule", schedule, "error", err)
+		return exitUsage
+	}
+
+	// First run immediately
+	in.scheduledRun()
+
+	// Start the cron scheduler
+	c.Start()
+	in.logger.Info("Scheduled stock ingestion", "schedule", schedule)
+
+	// Wait for a signal to stop the application
+	<-ctx.Done()
+
+	// Stop the cron scheduler and let a run in progress finish
+	in.logger.Info("Stopping stock ingestion system")
+	<-c.Stop().Done()
+
+	in.logger.Info("Stock ingestion system stopped")
+	return exitOK
+}
@@ -1,24 +1,306 @@
 package main
 
 import (
-	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
+	"time"
+	// Embeds the time zone database, so MARKET_TIMEZONE resolves on images without one
+	_ "time/tzdata"
 
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
 	"stock-tracker/internal/domain/usecases"
 	"stock-tracker/internal/infrastructure/clients"
 	"stock-tracker/internal/infrastructure/config"
 	"stock-tracker/internal/infrastructure/database"
+	"stock-tracker/internal/infrastructure/memory"
 	"stock-tracker/pkg/logger"
 
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
-	"github.com/robfig/cron/v3"
 )
 
+// Exit codes, so external schedulers can tell a failed run from one that only rejected some records
+const (
+	exitOK       = 0
+	exitFailed   = 1
+	exitUsage    = 2
+	exitRejected = 3
+	exitLocked   = 4
+)
+
+// ingestionLease is the job lease every run and backfill holds, whatever its source, so replicas
+// never write stocks or create brokers concurrently
+const ingestionLease = "stock-ingestion"
+
+const usage = `Usage: ingestor <command> [flags]
+
+Commands:
+  run            ingest once from every source and exit
+  backfill       reload the events dated within -from and -to, then exit
+  dry-run        fetch and validate the next run without writing, and print what it would change
+  status         print recent runs and the checkpoint of every source
+  enrich-prices  fill in the closing price of stocks without one from the PRICE_PROVIDER;
+                 with -overwrite, re-price the stocks within -from and -to
+  reprocess      re-derive stored stocks from their raw upstream payloads, without fetching;
+                 with -batch or -tickers, only those stored by a run or of some tickers
+  schema         print the latest upstream schema profile of every source and its drift from
+                 the baseline; with -accept, make the latest profile the baseline
+  serve          ingest on the INGESTION_SCHEDULE cron expression until stopped (default),
+                 pricing the new stocks after each run when PRICE_PROVIDER is set
+
+Replicas sharing a database take turns: a run that finds another instance ingesting
+exits with code 4. With SCHEMA_DRIFT_MODE=fail, a run whose upstream records drift from
+the schema baseline stops before writing them. Each stock is checked against the
+QUALITY_RULES ("default", "off" or a JSON file of rules) before it is written.
+
+Run 'ingestor <command> -h' for the flags of a command.
+`
+
+// sourceFlags selects where the ingestor reads analyst events from
+type sourceFlags struct {
+	kind     string
+	path     string
+	format   string
+	columns  string
+	pageSize int
+}
+
+func registerSourceFlags(fs *flag.FlagSet) *sourceFlags {
+	flags := &sourceFlags{}
+	fs.StringVar(&flags.kind, "source", "api", "ingestion source: api or file")
+	fs.StringVar(&flags.path, "file", "", "CSV or NDJSON file to import, optionally gzip-compressed (with -source=file)")
+	fs.StringVar(&flags.format, "format", "", "file format: csv or ndjson (default: inferred from the extension)")
+	fs.StringVar(&flags.columns, "columns", "", "column mapping overrides as field=column pairs, e.g. ticker=Symbol,time=Date")
+	fs.IntVar(&flags.pageSize, "page-size", 500, "records per page when reading a file")
+	return flags
+}
+
+// buildSources creates the stock sources selected on the command line: one per configured API vendor, or the file
+func buildSources(flags *sourceFlags, cfg *config.Config, logger logger.Logger) ([]clients.StockSource, error) {
+	switch flags.kind {
+	case "api":
+		sources := make([]clients.StockSource, len(cfg.StockAPIVendors))
+		for i, vendor := range cfg.StockAPIVendors {
+			sources[i] = clients.NewVendorStockAPIClient(vendor.Name, vendor.URL, vendor.APIKey, logger)
+		}
+		return sources, nil
+	case "file":
+		if flags.path == "" {
+			return nil, fmt.Errorf("-file is required with -source=file")
+		}
+
+		format := clients.FileFormat(flags.format)
+		if format == "" {
+			var err error
+			if format, err = clients.DetectFileFormat(flags.path); err != nil {
+				return nil, err
+			}
+		}
+
+		mapping, err := clients.ParseColumnMapping(flags.columns)
+		if err != nil {
+			return nil, err
+		}
+
+		source, err := clients.NewFileSource(flags.path, format, mapping, flags.pageSize, logger)
+		if err != nil {
+			return nil, err
+		}
+		return []clients.StockSource{source}, nil
+	default:
+		return nil, fmt.Errorf("unknown source %q, expected api or file", flags.kind)
+	}
+}
+
+// buildSchemaDriftPolicy reads the SCHEMA_DRIFT_* settings; it returns false when drift detection is off
+func buildSchemaDriftPolicy(cfg *config.Config) (usecases.SchemaDriftPolicy, bool, error) {
+	policy := usecases.SchemaDriftPolicy{
+		MinRecords: cfg.SchemaDriftMinRecords,
+		Thresholds: entities.DriftThresholds{
+			UnknownKeyRate:   cfg.SchemaDriftUnknownKeyRate,
+			MissingRate:      cfg.SchemaDriftMissingRate,
+			ParseFailureRate: cfg.SchemaDriftParseFailureRate,
+		},
+	}
+
+	switch cfg.SchemaDriftMode {
+	case "off":
+		return policy, false, nil
+	case "warn":
+		return policy, true, nil
+	case "fail":
+		policy.Fail = true
+		return policy, true, nil
+	default:
+		return policy, false, fmt.Errorf("unknown schema drift mode %q, expected off, warn or fail", cfg.SchemaDriftMode)
+	}
+}
+
+// buildPriceProvider creates the provider selected by PRICE_PROVIDER, or nil when enrichment is disabled
+func buildPriceProvider(cfg *config.Config, logger logger.Logger) (clients.PriceProvider, error) {
+	switch cfg.PriceProvider {
+	case "":
+		return nil, nil
+	case "csv":
+		return clients.NewCSVPriceProvider(cfg.PriceCSVDir, logger)
+	case "http":
+		if cfg.PriceAPIURL == "" {
+			return nil, errors.New("PRICE_API_URL is required with PRICE_PROVIDER=http")
+		}
+		return clients.NewHTTPPriceProvider("http", cfg.PriceAPIURL, cfg.PriceAPIKey, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown price provider %q, expected csv or http", cfg.PriceProvider)
+	}
+}
+
+// parsePricingFilter reads the flags of enrich-prices. Either bound of the window may be left out.
+func parsePricingFilter(from, to, tickers string, overwrite bool) (repositories.PricingFilter, error) {
+	filter := repositories.PricingFilter{Overwrite: overwrite}
+
+	var err error
+	if from != "" {
+		if filter.From, err = parseBound(from, false); err != nil {
+			return filter, fmt.Errorf("invalid -from: %w", err)
+		}
+	}
+	if to != "" {
+		if filter.To, err = parseBound(to, true); err != nil {
+			return filter, fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, errors.New("-from must be before -to")
+	}
+
+	filter.Tickers = parseTickers(tickers)
+	return filter, nil
+}
+
+// parseTickers reads a comma-separated -tickers flag
+func parseTickers(tickers string) []string {
+	var parsed []string
+	for _, ticker := range strings.Split(tickers, ",") {
+		if ticker = strings.TrimSpace(ticker); ticker != "" {
+			parsed = append(parsed, strings.ToUpper(ticker))
+		}
+	}
+	return parsed
+}
+
+// parseWindow reads the -from and -to bounds of a backfill, each an RFC 3339 timestamp or a date.
+// A bare -to date covers the whole day.
+func parseWindow(from, to string) (time.Time, time.Time, error) {
+	if from == "" || to == "" {
+		return time.Time{}, time.Time{}, errors.New("backfill requires both -from and -to")
+	}
+
+	start, err := parseBound(from, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid -from: %w", err)
+	}
+	end, err := parseBound(to, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid -to: %w", err)
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.New("-from must be before -to")
+	}
+
+	return start, end, nil
+}
+
+func parseBound(value string, endOfDay bool) (time.Time, error) {
+	if bound, err := time.Parse(time.RFC3339, value); err == nil {
+		return bound, nil
+	}
+	if bound, err := time.Parse(time.DateOnly, value); err == nil {
+		if endOfDay {
+			bound = bound.Add(24*time.Hour - time.Nanosecond)
+		}
+		return bound, nil
+	}
+	return time.Time{}, errors.New("expected an RFC 3339 timestamp or a YYYY-MM-DD date")
+}
+
+// holderID identifies this process as a lease holder; a restarted process never reuses a predecessor's ID
+func holderID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "ingestor"
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.New().String()[:8])
+}
+
 func main() {
+	os.Exit(execute(os.Args[1:]))
+}
+
+// execute runs the command given on the command line and returns the process exit code
+func execute(args []string) int {
+	command := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags := registerSourceFlags(fs)
+
+	var from, to, tickers, batchID string
+	var recentRuns int
+	var overwrite, accept bool
+	switch command {
+	case "run", "dry-run", "serve":
+	case "backfill":
+		fs.StringVar(&from, "from", "", "start of the window to reload, as an RFC 3339 timestamp or a date")
+		fs.StringVar(&to, "to", "", "end of the window to reload, as an RFC 3339 timestamp or a date (inclusive)")
+	case "status":
+		fs.IntVar(&recentRuns, "runs", 10, "number of recent runs to print")
+	case "enrich-prices":
+		fs.StringVar(&from, "from", "", "only price events from this RFC 3339 timestamp or date")
+		fs.StringVar(&to, "to", "", "only price events until this RFC 3339 timestamp or date (inclusive)")
+		fs.StringVar(&tickers, "tickers", "", "comma-separated tickers to price (default: all)")
+		fs.BoolVar(&overwrite, "overwrite", false, "re-price events that already have a close")
+	case "reprocess":
+		fs.StringVar(&batchID, "batch", "", "only reprocess the payloads stored by this run")
+		fs.StringVar(&tickers, "tickers", "", "comma-separated tickers to reprocess (default: all)")
+	case "schema":
+		fs.BoolVar(&accept, "accept", false, "make the latest profile of every source its baseline")
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return exitOK
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		return exitUsage
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	var windowFrom, windowTo time.Time
+	var pricingFilter repositories.PricingFilter
+	switch command {
+	case "backfill":
+		var err error
+		if windowFrom, windowTo, err = parseWindow(from, to); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+	case "enrich-prices":
+		var err error
+		if pricingFilter, err = parsePricingFilter(from, to, tickers, overwrite); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+	}
+
 	// Load environment variables from .env file
 	if err := godotenv.Load(); err != nil {
 		fmt.Println("Warning: .env file not found, using system environment variables")
@@ -27,67 +309,122 @@ func main() {
 	//Load the configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return exitFailed
 	}
 
 	//Initialize the logger
 	logger := logger.NewSimpleLogger()
-	logger.Info("Starting stock ingestion system")
+	logger.Info("Starting stock ingestion system", "command", command)
 
 	//Initialize the database
 	db, err := database.NewConnection(cfg.DatabaseURL)
 	if err != nil {
 		logger.Error("Failed to initialize database", "error", err)
-		os.Exit(1)
+		return exitFailed
 	}
 
-	defer db.Close()
+	defer func() {
+		// Close the database connection
+		if err := db.Close(); err != nil {
+			logger.Error("Failed to close database connection", "error", err)
+		}
+	}()
 
 	// Initialize repositories
 	stockRepo := database.NewStockRepository(db.GetPool(), logger)
 	brokerRepo := database.NewBrokerRepository(db.GetPool())
+	ingestionLogRepo := database.NewIngestionLogRepository(db.GetPool(), logger)
+	checkpointRepo := database.NewIngestionCheckpointRepository(db.GetPool(), logger)
+	deadLetterRepo := database.NewDeadLetterRepository(db.GetPool(), logger)
+	normalizationRepo := database.NewNormalizationRepository(db.GetPool(), logger)
 
-	// Initialize external clients
-	stockAPIClient := clients.NewStockAPIClient(cfg.StockAPIURL, cfg.StockAPIKey, logger)
-
-	// Initialize the use case
-	stockIngestionUseCase := usecases.NewStockIngestionUseCase(stockRepo, brokerRepo, stockAPIClient, logger)
-	// Initialize the cron job (cron scheduler)
-	c := cron.New(cron.WithLogger(cron.VerbosePrintfLogger(log.New(os.Stdout, "cron: ", log.LstdFlags))))
-
-	// First run immediately
-	ctx := context.Background()
-	if err := stockIngestionUseCase.IngestStocks(ctx); err != nil {
-		logger.Error("Initial ingestion failed", "error", err)
+	// Without leader election, runs are still kept from overlapping within this process
+	var leaseRepo repositories.LeaseRepository = memory.NewLeaseRepository()
+	if cfg.LeaderElectionEnabled {
+		leaseRepo = database.NewLeaseRepository(db.GetPool(), logger)
 	}
+	elector := usecases.NewLeaderElector(leaseRepo, holderID(), cfg.LeaderLeaseTTL, logger)
 
-	// Add the cron job to schedule the ingestion every hour
-	_, err = c.AddFunc("0 * * * *", func() {
-		ctx := context.Background()
-		if err := stockIngestionUseCase.IngestStocks(ctx); err != nil {
-			logger.Error("Ingestion job failed", "error", err)
-		}
-	})
+	// Initialize the stock sources
+	sources, err := buildSources(flags, cfg, logger)
 	if err != nil {
-		log.Fatal("Failed to schedule ingestion job", "error", err)
+		logger.Error("Failed to initialize ingestion source", "error", err)
+		return exitUsage
 	}
 
-	// Start the cron scheduler
-	c.Start()
+	// Initialize close-price enrichment, when a provider is configured
+	priceProvider, err := buildPriceProvider(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to initialize price provider", "error", err)
+		return exitUsage
+	}
+	market, err := time.LoadLocation(cfg.MarketTimezone)
+	if err != nil {
+		logger.Error("Invalid market time zone", "timezone", cfg.MarketTimezone, "error", err)
+		return exitUsage
+	}
 
-	// Wait for a signal to stop the application
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Initialize upstream schema drift detection
+	driftPolicy, detectDrift, err := buildSchemaDriftPolicy(cfg)
+	if err != nil {
+		logger.Error("Invalid schema drift settings", "error", err)
+		return exitUsage
+	}
+	schemaProfileRepo := database.NewSchemaProfileRepository(db.GetPool(), logger)
 
-	// Stop the cron scheduler
-	logger.Info("Stopping stock ingestion system")
-	c.Stop()
+	// Initialize the data-quality rules checked on each ingested stock
+	qualityRules, err := clients.LoadQualityRules(cfg.QualityRules)
+	if err != nil {
+		logger.Error("Invalid data-quality rules", "error", err)
+		return exitUsage
+	}
+	qualitySummaryRepo := database.NewQualitySummaryRepository(db.GetPool(), logger)
 
-	// Close the database connection
-	if err := db.Close(); err != nil {
-		logger.Error("Failed to close database connection", "error", err)
+	// Initialize one use case per source; they share the priority used to de-duplicate events across sources
+	app := &ingestor{
+		sources:  sources,
+		useCases: make([]*usecases.StockIngestionUseCase, len(sources)),
+		runs:     usecases.NewIngestionRunQueryUseCase(ingestionLogRepo, qualitySummaryRepo, logger),
+		logger:   logger,
+		out:      os.Stdout,
+	}
+	if priceProvider != nil {
+		app.prices = usecases.NewPriceEnrichmentUseCase(stockRepo, priceProvider, market, logger)
+	}
+	for i, source := range sources {
+		app.useCases[i] = usecases.NewStockIngestionUseCase(stockRepo, brokerRepo, ingestionLogRepo, checkpointRepo, deadLetterRepo, source, logger)
+		app.useCases[i].SetSourcePriority(cfg.StockSourcePriority, cfg.StockDedupWindow)
+		app.useCases[i].SetLeaderElection(elector, ingestionLease)
+		app.useCases[i].SetNormalization(normalizationRepo)
+		if detectDrift {
+			app.useCases[i].SetSchemaDrift(schemaProfileRepo, driftPolicy)
+		}
+		if qualityRules != nil {
+			app.useCases[i].SetQualityRules(qualityRules, qualitySummaryRepo)
+		}
 	}
 
-	logger.Info("Stock ingestion system stopped")
+	switch command {
+	case "run":
+		return app.runOnce()
+	case "backfill":
+		return app.backfill(windowFrom, windowTo)
+	case "dry-run":
+		return app.dryRun()
+	case "status":
+		return app.status(recentRuns)
+	case "enrich-prices":
+		return app.enrichPrices(pricingFilter)
+	case "schema":
+		return app.schema(accept)
+	case "reprocess":
+		return app.reprocess(repositories.PayloadFilter{BatchID: batchID, Tickers: parseTickers(tickers)})
+	default:
+		// A file import is a one-off backfill; run it to completion and exit
+		if flags.kind == "file" {
+			return app.runOnce()
+		}
+		return app.serve(cfg.IngestionSchedule)
+	}
 }
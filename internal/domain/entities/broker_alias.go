@@ -0,0 +1,121 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// BrokerAlias is another name a broker publishes under. Events whose brokerage matches the alias are
+// attributed to the broker instead of creating a new one.
+type BrokerAlias struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	BrokerID  uuid.UUID `json:"broker_id" db:"broker_id"`
+	Alias     string    `json:"alias" db:"alias"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+func NewBrokerAlias(brokerID uuid.UUID, alias string) *BrokerAlias {
+	return &BrokerAlias{
+		ID:        uuid.New(),
+		BrokerID:  brokerID,
+		Alias:     strings.TrimSpace(alias),
+		CreatedAt: time.Now(),
+	}
+}
+
+// BrokerMerge is the audit record of a broker folded into another one. The merged broker is deleted,
+// so its name and credibility score are kept here.
+type BrokerMerge struct {
+	ID                     uuid.UUID `json:"id" db:"id"`
+	SurvivorID             uuid.UUID `json:"survivor_id" db:"survivor_id"`
+	SurvivorName           string    `json:"survivor_name" db:"survivor_name"`
+	MergedID               uuid.UUID `json:"merged_id" db:"merged_id"`
+	MergedName             string    `json:"merged_name" db:"merged_name"`
+	MergedCredibilityScore float64   `json:"merged_credibility_score" db:"merged_credibility_score"`
+	StocksMoved            int       `json:"stocks_moved" db:"stocks_moved"`
+	AliasesMoved           int       `json:"aliases_moved" db:"aliases_moved"`
+	Reason                 string    `json:"reason,omitempty" db:"reason"`
+	MergedAt               time.Time `json:"merged_at" db:"merged_at"`
+}
+
+func NewBrokerMerge(survivor, merged *Broker, reason string) *BrokerMerge {
+	return &BrokerMerge{
+		ID:                     uuid.New(),
+		SurvivorID:             survivor.ID,
+		SurvivorName:           survivor.Name,
+		MergedID:               merged.ID,
+		MergedName:             merged.Name,
+		MergedCredibilityScore: merged.CredibilityScore,
+		Reason:                 strings.TrimSpace(reason),
+		MergedAt:               time.Now(),
+	}
+}
+
+// brokerSuffixes are the corporate suffixes ignored at the end of a broker name
+var brokerSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "co": true, "corp": true, "corporation": true, "company": true,
+	"llc": true, "ltd": true, "limited": true, "plc": true, "lp": true, "llp": true, "ag": true, "sa": true,
+	"group": true, "holdings": true,
+}
+
+// BrokerKey puts a broker name in the form names are matched in: lower case, without punctuation and
+// trailing corporate suffixes, so "Goldman Sachs Group, Inc." and "goldman sachs" share a key. Periods and
+// apostrophes are dropped rather than separating words, so "J.P. Morgan" matches "JP Morgan".
+func BrokerKey(name string) string {
+	name = strings.NewReplacer(".", "", "'", "", "’", "").Replace(strings.ToLower(name))
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 && brokerSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// BrokerSimilarity scores how likely two broker names are the same broker, from 0 to 1. Names sharing
+// a key score 1; otherwise the score is the overlap of the letter pairs of both keys without spaces,
+// which stays high for "JP Morgan" against "JPMorgan Chase & Co." and low for unrelated brokers.
+func BrokerSimilarity(a, b string) float64 {
+	keyA, keyB := BrokerKey(a), BrokerKey(b)
+	if keyA == "" || keyB == "" {
+		return 0
+	}
+	if keyA == keyB {
+		return 1
+	}
+
+	pairsA := letterPairs(strings.ReplaceAll(keyA, " ", ""))
+	pairsB := letterPairs(strings.ReplaceAll(keyB, " ", ""))
+	if len(pairsA) == 0 || len(pairsB) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(pairsA))
+	for _, pair := range pairsA {
+		counts[pair]++
+	}
+	shared := 0
+	for _, pair := range pairsB {
+		if counts[pair] > 0 {
+			counts[pair]--
+			shared++
+		}
+	}
+
+	return 2 * float64(shared) / float64(len(pairsA)+len(pairsB))
+}
+
+func letterPairs(s string) []string {
+	runes := []rune(s)
+	if len(runes) < 2 {
+		return nil
+	}
+	pairs := make([]string, 0, len(runes)-1)
+	for i := 0; i < len(runes)-1; i++ {
+		pairs = append(pairs, string(runes[i:i+2]))
+	}
+	return pairs
+}
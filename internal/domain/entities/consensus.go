@@ -0,0 +1,222 @@
+package entities
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsensusCoverage is how recently a broker must have published on a ticker to count as covering it
+const ConsensusCoverage = 365 * 24 * time.Hour
+
+// ConsensusWindows are the periods, in days, rating changes are counted over
+var ConsensusWindows = []int{30, 90, 365}
+
+// TickerConsensus is the view of a ticker across the brokers covering it, built from the latest rating
+// and target of each. Targets are quoted in Currency; a target no rate could convert into it is left out.
+type TickerConsensus struct {
+	Ticker string `json:"ticker"`
+	// Rating is the tier nearest to Score, unknown when no covering broker has a normalized rating
+	Rating RatingTier `json:"rating"`
+	// Score is the mean score of the covering brokers' rating tiers, weighted by broker credibility
+	Score           *float64         `json:"score"`
+	CoveringBrokers int              `json:"covering_brokers"`
+	Ratings         []BrokerRating   `json:"ratings"`
+	Targets         *TargetStats     `json:"targets"`
+	Currency        string           `json:"currency"`
+	Activity        []RatingActivity `json:"activity"`
+	ComputedAt      time.Time        `json:"computed_at"`
+}
+
+// BrokerRating is the latest view one broker published on a ticker
+type BrokerRating struct {
+	BrokerID    uuid.UUID  `json:"broker_id"`
+	Brokerage   string     `json:"brokerage"`
+	Credibility float64    `json:"credibility"`
+	Rating      string     `json:"rating"`
+	RatingTier  RatingTier `json:"rating_tier"`
+	Target      *float64   `json:"target,omitempty"`
+	Currency    string     `json:"currency,omitempty"`
+	// UpdatedAt is the time of the broker's latest event on the ticker
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TargetStats summarizes the latest targets of the covering brokers. Dispersion is the standard deviation
+// relative to the mean, comparable across tickers of any price.
+type TargetStats struct {
+	Count      int     `json:"count"`
+	Mean       float64 `json:"mean"`
+	Median     float64 `json:"median"`
+	High       float64 `json:"high"`
+	Low        float64 `json:"low"`
+	StdDev     float64 `json:"std_dev"`
+	Dispersion float64 `json:"dispersion"`
+}
+
+// RatingActivity counts the upgrades and downgrades published on a ticker over the last Days days
+type RatingActivity struct {
+	Days       int `json:"days"`
+	Upgrades   int `json:"upgrades"`
+	Downgrades int `json:"downgrades"`
+}
+
+// NewTickerConsensus builds the consensus of a ticker from its events as of now. Credibility holds the
+// credibility score of each broker; a broker missing from it carries no weight, unless no covering broker
+// has any, in which case every broker counts the same.
+func NewTickerConsensus(ticker string, events []*Stock, credibility map[uuid.UUID]float64, currency string, now time.Time) *TickerConsensus {
+	consensus := &TickerConsensus{
+		Ticker:     strings.ToUpper(ticker),
+		Ratings:    []BrokerRating{},
+		Currency:   currency,
+		ComputedAt: now,
+	}
+
+	ordered := make([]*Stock, len(events))
+	copy(ordered, events)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].EventTime.After(ordered[j].EventTime) })
+
+	latest := make(map[string]*BrokerRating)
+	var keys []string
+	for _, event := range ordered {
+		if now.Sub(event.EventTime) > ConsensusCoverage {
+			break
+		}
+
+		key := strings.ToLower(event.Brokerage)
+		if event.BrokerID != uuid.Nil {
+			key = event.BrokerID.String()
+		}
+		rating, ok := latest[key]
+		if !ok {
+			rating = &BrokerRating{
+				BrokerID:    event.BrokerID,
+				Brokerage:   event.Brokerage,
+				Credibility: credibility[event.BrokerID],
+				UpdatedAt:   event.EventTime,
+			}
+			latest[key] = rating
+			keys = append(keys, key)
+		}
+
+		// An event may only move the target or restate the rating, so each is taken from the latest event carrying it
+		if rating.Rating == "" && event.RatingTo != "" {
+			_, tier := event.normalizedRatingTiers()
+			rating.Rating, rating.RatingTier = event.RatingTo, tier
+		}
+		if rating.Target == nil && event.TargetTo > 0 {
+			target := event.TargetTo
+			rating.Target, rating.Currency = &target, event.Currency
+		}
+	}
+
+	var targets []float64
+	var scores, weights []float64
+	for _, key := range keys {
+		rating := latest[key]
+		consensus.Ratings = append(consensus.Ratings, *rating)
+		if rating.Target != nil && rating.Currency == currency {
+			targets = append(targets, *rating.Target)
+		}
+		if rating.RatingTier.IsValid() {
+			scores = append(scores, rating.RatingTier.Score())
+			weights = append(weights, rating.Credibility)
+		}
+	}
+	consensus.CoveringBrokers = len(consensus.Ratings)
+
+	if score, ok := weightedMean(scores, weights); ok {
+		consensus.Score = &score
+		consensus.Rating = nearestRatingTier(score)
+	}
+	consensus.Targets = newTargetStats(targets)
+
+	for _, days := range ConsensusWindows {
+		activity := RatingActivity{Days: days}
+		since := now.AddDate(0, 0, -days)
+		for _, event := range ordered {
+			if event.EventTime.Before(since) {
+				break
+			}
+			switch event.normalizedActionType() {
+			case ActionTypeUpgrade:
+				activity.Upgrades++
+			case ActionTypeDowngrade:
+				activity.Downgrades++
+			}
+		}
+		consensus.Activity = append(consensus.Activity, activity)
+	}
+
+	return consensus
+}
+
+// weightedMean averages values by weights, or evenly when no weight is positive
+func weightedMean(values, weights []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	var sum, total float64
+	for i, value := range values {
+		if weights[i] > 0 {
+			sum += value * weights[i]
+			total += weights[i]
+		}
+	}
+	if total > 0 {
+		return sum / total, true
+	}
+
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values)), true
+}
+
+// nearestRatingTier maps a score back onto the tier scoring closest to it, the more favourable on a tie
+func nearestRatingTier(score float64) RatingTier {
+	nearest := RatingTiers[0]
+	for _, tier := range RatingTiers[1:] {
+		if math.Abs(tier.Score()-score) < math.Abs(nearest.Score()-score) {
+			nearest = tier
+		}
+	}
+	return nearest
+}
+
+// newTargetStats summarizes targets, or returns nil when there are none
+func newTargetStats(targets []float64) *TargetStats {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	sorted := make([]float64, len(targets))
+	copy(sorted, targets)
+	sort.Float64s(sorted)
+
+	stats := &TargetStats{Count: len(sorted), Low: sorted[0], High: sorted[len(sorted)-1]}
+	for _, target := range sorted {
+		stats.Mean += target
+	}
+	stats.Mean /= float64(len(sorted))
+
+	middle := len(sorted) / 2
+	stats.Median = sorted[middle]
+	if len(sorted)%2 == 0 {
+		stats.Median = (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	var squares float64
+	for _, target := range sorted {
+		squares += (target - stats.Mean) * (target - stats.Mean)
+	}
+	stats.StdDev = math.Sqrt(squares / float64(len(sorted)))
+	if stats.Mean > 0 {
+		stats.Dispersion = stats.StdDev / stats.Mean
+	}
+
+	return stats
+}
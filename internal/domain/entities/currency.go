@@ -0,0 +1,133 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultCurrency is assumed for targets that carry no currency, as upstream quoted every target in it
+const DefaultCurrency = "USD"
+
+// ErrNoFXRate is returned when a table has no rate between two currencies
+var ErrNoFXRate = errors.New("no exchange rate")
+
+// NormalizeCurrency returns the ISO 4217 form of a currency code, or an empty string if code is not one
+func NormalizeCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return ""
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return ""
+		}
+	}
+	return code
+}
+
+// FXTable holds exchange rates between currencies. A rate is the amount of the quote currency one unit
+// of the base currency buys. Conversions use a rate directly, inverted, or crossed through a currency
+// both sides have a rate with, so tables published against a single base currency cover every pair.
+type FXTable struct {
+	rates map[string]map[string]float64
+}
+
+func NewFXTable() *FXTable {
+	return &FXTable{rates: make(map[string]map[string]float64)}
+}
+
+// Set records the rate of base in quote, replacing any rate already recorded for the pair
+func (t *FXTable) Set(base, quote string, rate float64) error {
+	from, to := NormalizeCurrency(base), NormalizeCurrency(quote)
+	if from == "" || to == "" {
+		return fmt.Errorf("invalid currency pair %s/%s", base, quote)
+	}
+	if rate <= 0 {
+		return fmt.Errorf("invalid %s/%s rate %g", from, to, rate)
+	}
+
+	if t.rates[from] == nil {
+		t.rates[from] = make(map[string]float64)
+	}
+	t.rates[from][to] = rate
+	return nil
+}
+
+// Currencies returns the currencies the table has rates for, sorted
+func (t *FXTable) Currencies() []string {
+	seen := make(map[string]bool)
+	for base, quotes := range t.rates {
+		seen[base] = true
+		for quote := range quotes {
+			seen[quote] = true
+		}
+	}
+
+	currencies := make([]string, 0, len(seen))
+	for currency := range seen {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Knows checks if the table has any rate for currency
+func (t *FXTable) Knows(currency string) bool {
+	currency = NormalizeCurrency(currency)
+	if _, ok := t.rates[currency]; ok {
+		return true
+	}
+	for _, quotes := range t.rates {
+		if _, ok := quotes[currency]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Rate returns how much of to one unit of from buys
+func (t *FXTable) Rate(from, to string) (float64, bool) {
+	from, to = NormalizeCurrency(from), NormalizeCurrency(to)
+	if from == "" || to == "" {
+		return 0, false
+	}
+	if from == to {
+		return 1, true
+	}
+	if rate, ok := t.direct(from, to); ok {
+		return rate, true
+	}
+
+	for _, via := range t.Currencies() {
+		first, ok := t.direct(from, via)
+		if !ok {
+			continue
+		}
+		if second, ok := t.direct(via, to); ok {
+			return first * second, true
+		}
+	}
+	return 0, false
+}
+
+// direct returns the rate of a pair recorded either way round
+func (t *FXTable) direct(from, to string) (float64, bool) {
+	if rate, ok := t.rates[from][to]; ok {
+		return rate, true
+	}
+	if rate, ok := t.rates[to][from]; ok {
+		return 1 / rate, true
+	}
+	return 0, false
+}
+
+// Convert returns amount of from expressed in to, or ErrNoFXRate
+func (t *FXTable) Convert(amount float64, from, to string) (float64, error) {
+	rate, ok := t.Rate(from, to)
+	if !ok {
+		return 0, fmt.Errorf("%w from %s to %s", ErrNoFXRate, from, to)
+	}
+	return amount * rate, nil
+}
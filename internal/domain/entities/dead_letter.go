@@ -0,0 +1,80 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeadLetterStatus string
+
+const (
+	DeadLetterStatusPending     DeadLetterStatus = "pending"
+	DeadLetterStatusResubmitted DeadLetterStatus = "resubmitted"
+	DeadLetterStatusDiscarded   DeadLetterStatus = "discarded"
+)
+
+// DeadLetterRecord is an upstream record quarantined during ingestion because it could not be
+// converted or failed validation. Payload holds the raw item so it can be fixed and re-submitted.
+type DeadLetterRecord struct {
+	ID      uuid.UUID `json:"id" db:"id"`
+	BatchID string    `json:"batch_id" db:"batch_id"`
+	// Source is the upstream source the record came from, empty for records quarantined before it was kept
+	Source     string           `json:"source,omitempty" db:"source"`
+	Payload    json.RawMessage  `json:"payload" db:"payload"`
+	Reason     string           `json:"reason" db:"reason"`
+	Status     DeadLetterStatus `json:"status" db:"status"`
+	Attempts   int              `json:"attempts" db:"attempts"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at" db:"updated_at"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+func NewDeadLetterRecord(batchID, source string, payload json.RawMessage, reason string) *DeadLetterRecord {
+	now := time.Now()
+	return &DeadLetterRecord{
+		ID:        uuid.New(),
+		BatchID:   batchID,
+		Source:    source,
+		Payload:   payload,
+		Reason:    reason,
+		Status:    DeadLetterStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// IsPending checks if the record is still waiting to be re-submitted or discarded
+func (d *DeadLetterRecord) IsPending() bool {
+	return d.Status == DeadLetterStatusPending
+}
+
+// Fix replaces the payload with a corrected version
+func (d *DeadLetterRecord) Fix(payload json.RawMessage) {
+	d.Payload = payload
+	d.UpdatedAt = time.Now()
+}
+
+// RecordAttempt notes a re-submission that was rejected again
+func (d *DeadLetterRecord) RecordAttempt(reason string) {
+	d.Attempts++
+	d.Reason = reason
+	d.UpdatedAt = time.Now()
+}
+
+func (d *DeadLetterRecord) MarkResubmitted() {
+	d.Attempts++
+	d.resolve(DeadLetterStatusResubmitted)
+}
+
+func (d *DeadLetterRecord) Discard() {
+	d.resolve(DeadLetterStatusDiscarded)
+}
+
+func (d *DeadLetterRecord) resolve(status DeadLetterStatus) {
+	now := time.Now()
+	d.Status = status
+	d.UpdatedAt = now
+	d.ResolvedAt = &now
+}
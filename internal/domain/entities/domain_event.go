@@ -0,0 +1,211 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainEventType names a kind of domain event; subscribers register for the types they handle
+type DomainEventType string
+
+const (
+	// EventStockEventIngested is emitted for each analyst action stored for the first time
+	EventStockEventIngested DomainEventType = "stock.event_ingested"
+	// EventStockEventRevised is emitted when upstream corrects an analyst action already stored
+	EventStockEventRevised DomainEventType = "stock.event_revised"
+	// EventRatingChanged is emitted for each ingested analyst action that moved a broker's rating
+	EventRatingChanged DomainEventType = "stock.rating_changed"
+	// EventUserRegistered is emitted when an account is created
+	EventUserRegistered DomainEventType = "user.registered"
+	// EventSubscriptionCreated is emitted when a user starts a subscription, before it is paid
+	EventSubscriptionCreated DomainEventType = "subscription.created"
+	// EventSubscriptionActivated is emitted when a payment activates a subscription and upgrades its user
+	EventSubscriptionActivated DomainEventType = "subscription.activated"
+)
+
+// DomainEventTypes lists every type in the catalog
+var DomainEventTypes = []DomainEventType{
+	EventStockEventIngested, EventStockEventRevised, EventRatingChanged,
+	EventUserRegistered, EventSubscriptionCreated, EventSubscriptionActivated,
+}
+
+// DomainEvent records a state change other parts of the system may react to. It is written to the outbox
+// in the same transaction as the change, so an event exists if and only if the change was committed.
+// Payload holds one of the typed payloads below, matching Type.
+type DomainEvent struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	Type        DomainEventType `json:"type" db:"event_type"`
+	AggregateID uuid.UUID       `json:"aggregate_id" db:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at" db:"occurred_at"`
+}
+
+func newDomainEvent(eventType DomainEventType, aggregateID uuid.UUID, payload interface{}) *DomainEvent {
+	// The payloads are plain structs, which always encode
+	data, _ := json.Marshal(payload)
+	return &DomainEvent{
+		ID:          uuid.New(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+		OccurredAt:  time.Now(),
+	}
+}
+
+// Decode unmarshals the payload into the typed payload of the event's type
+func (e *DomainEvent) Decode(payload interface{}) error {
+	if err := json.Unmarshal(e.Payload, payload); err != nil {
+		return fmt.Errorf("failed to decode %s event %s: %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+// StockEventPayload describes an analyst action as it was stored, for StockEventIngested and StockEventRevised
+type StockEventPayload struct {
+	StockID    uuid.UUID  `json:"stock_id"`
+	Ticker     string     `json:"ticker"`
+	Company    string     `json:"company"`
+	BrokerID   uuid.UUID  `json:"broker_id"`
+	Action     string     `json:"action"`
+	ActionType ActionType `json:"action_type"`
+	RatingFrom string     `json:"rating_from"`
+	RatingTo   string     `json:"rating_to"`
+	TargetFrom float64    `json:"target_from"`
+	TargetTo   float64    `json:"target_to"`
+	Currency   string     `json:"currency"`
+	EventTime  time.Time  `json:"event_time"`
+	Source     string     `json:"source"`
+}
+
+func newStockEventPayload(stock *Stock) StockEventPayload {
+	return StockEventPayload{
+		StockID:    stock.ID,
+		Ticker:     stock.Ticker,
+		Company:    stock.Company,
+		BrokerID:   stock.BrokerID,
+		Action:     stock.Action,
+		ActionType: stock.ActionType,
+		RatingFrom: stock.RatingFrom,
+		RatingTo:   stock.RatingTo,
+		TargetFrom: stock.TargetFrom,
+		TargetTo:   stock.TargetTo,
+		Currency:   stock.Currency,
+		EventTime:  stock.EventTime,
+		Source:     stock.Source,
+	}
+}
+
+// RatingChangedPayload describes a broker moving its rating of a stock. Direction is "upgrade" or
+// "downgrade" when both ratings map onto tiers, and empty otherwise.
+type RatingChangedPayload struct {
+	StockID    uuid.UUID  `json:"stock_id"`
+	Ticker     string     `json:"ticker"`
+	BrokerID   uuid.UUID  `json:"broker_id"`
+	RatingFrom string     `json:"rating_from"`
+	RatingTo   string     `json:"rating_to"`
+	FromTier   RatingTier `json:"from_tier"`
+	ToTier     RatingTier `json:"to_tier"`
+	Direction  string     `json:"direction,omitempty"`
+	EventTime  time.Time  `json:"event_time"`
+}
+
+// UserRegisteredPayload describes a new account
+type UserRegisteredPayload struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+	Tier   UserTier  `json:"tier"`
+}
+
+// SubscriptionPayload describes a subscription, for SubscriptionCreated and SubscriptionActivated.
+// Tier is the tier of its user after the change.
+type SubscriptionPayload struct {
+	SubscriptionID   uuid.UUID          `json:"subscription_id"`
+	UserID           uuid.UUID          `json:"user_id"`
+	Plan             SubscriptionPlan   `json:"plan"`
+	Status           SubscriptionStatus `json:"status"`
+	Price            float64            `json:"price"`
+	Currency         string             `json:"currency"`
+	EndDate          time.Time          `json:"end_date"`
+	PaymentReference string             `json:"payment_reference,omitempty"`
+	Tier             UserTier           `json:"tier,omitempty"`
+}
+
+func newSubscriptionPayload(subscription *Subscription) SubscriptionPayload {
+	return SubscriptionPayload{
+		SubscriptionID:   subscription.ID,
+		UserID:           subscription.UserID,
+		Plan:             subscription.Plan,
+		Status:           subscription.Status,
+		Price:            subscription.Price,
+		Currency:         subscription.Currency,
+		EndDate:          subscription.EndDate,
+		PaymentReference: subscription.PaymentReference,
+	}
+}
+
+// IngestedStockEvents returns the events of storing stock for the first time: StockEventIngested, and
+// RatingChanged when the action moved the broker's rating
+func IngestedStockEvents(stock *Stock) []*DomainEvent {
+	events := []*DomainEvent{newDomainEvent(EventStockEventIngested, stock.ID, newStockEventPayload(stock))}
+
+	// Initiations and actions without a rating have nothing to move from or to
+	ratingFrom, ratingTo := strings.TrimSpace(stock.RatingFrom), strings.TrimSpace(stock.RatingTo)
+	if ratingFrom == "" || ratingTo == "" {
+		return events
+	}
+	fromTier, toTier := stock.normalizedRatingTiers()
+	if fromTier == toTier && strings.EqualFold(ratingFrom, ratingTo) {
+		return events
+	}
+
+	var direction string
+	if fromTier.IsValid() && toTier.IsValid() {
+		switch {
+		case toTier.Score() > fromTier.Score():
+			direction = "upgrade"
+		case toTier.Score() < fromTier.Score():
+			direction = "downgrade"
+		}
+	}
+	return append(events, newDomainEvent(EventRatingChanged, stock.ID, RatingChangedPayload{
+		StockID:    stock.ID,
+		Ticker:     stock.Ticker,
+		BrokerID:   stock.BrokerID,
+		RatingFrom: stock.RatingFrom,
+		RatingTo:   stock.RatingTo,
+		FromTier:   fromTier,
+		ToTier:     toTier,
+		Direction:  direction,
+		EventTime:  stock.EventTime,
+	}))
+}
+
+// RevisedStockEvent returns the StockEventRevised event of a stored stock corrected to its current values
+func RevisedStockEvent(stock *Stock) *DomainEvent {
+	return newDomainEvent(EventStockEventRevised, stock.ID, newStockEventPayload(stock))
+}
+
+// UserRegisteredEvent returns the event of creating user
+func UserRegisteredEvent(user *User) *DomainEvent {
+	return newDomainEvent(EventUserRegistered, user.ID, UserRegisteredPayload{
+		UserID: user.ID,
+		Email:  user.Email,
+		Tier:   user.Tier,
+	})
+}
+
+// SubscriptionCreatedEvent returns the event of starting subscription
+func SubscriptionCreatedEvent(subscription *Subscription) *DomainEvent {
+	return newDomainEvent(EventSubscriptionCreated, subscription.ID, newSubscriptionPayload(subscription))
+}
+
+// SubscriptionActivatedEvent returns the event of activating subscription, which moved user to its tier
+func SubscriptionActivatedEvent(subscription *Subscription, user *User) *DomainEvent {
+	payload := newSubscriptionPayload(subscription)
+	payload.Tier = user.Tier
+	return newDomainEvent(EventSubscriptionActivated, subscription.ID, payload)
+}
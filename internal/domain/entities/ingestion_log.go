@@ -2,6 +2,7 @@ package entities
 
 import (
 	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -14,18 +15,21 @@ const (
 )
 
 type IngestionLog struct {
-	ID                uuid.UUID       `json:"id" db:"id"`
-	BatchID           string          `json:"batch_id" db:"batch_id"`
-	TotalRecords      int             `json:"total_records" db:"total_records"`
-	SuccessfulRecords int             `json:"successful_records" db:"successful_records"`
-	FailedRecords     int             `json:"failed_records" db:"failed_records"`
-	Status            IngestionStatus `json:"status" db:"status"`
+	ID                uuid.UUID              `json:"id" db:"id"`
+	BatchID           string                 `json:"batch_id" db:"batch_id"`
+	TotalRecords      int                    `json:"total_records" db:"total_records"`
+	SuccessfulRecords int                    `json:"successful_records" db:"successful_records"`
+	FailedRecords     int                    `json:"failed_records" db:"failed_records"`
+	InsertedRecords   int                    `json:"inserted_records" db:"inserted_records"`
+	UpdatedRecords    int                    `json:"updated_records" db:"updated_records"`
+	DuplicateRecords  int                    `json:"duplicate_records" db:"duplicate_records"`
+	Status            IngestionStatus        `json:"status" db:"status"`
 	ErrorDetails      map[string]interface{} `json:"error_details,omitempty" db:"error_details"`
-	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
-	CompletedAt       *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt         time.Time              `json:"created_at" db:"created_at"`
+	CompletedAt       *time.Time             `json:"completed_at,omitempty" db:"completed_at"`
 }
 
-func NewIngestionLog( batchID string, totalRecords int ) *IngestionLog {
+func NewIngestionLog(batchID string, totalRecords int) *IngestionLog {
 	return &IngestionLog{
 		ID:                uuid.New(),
 		BatchID:           batchID,
@@ -48,4 +52,33 @@ func (il *IngestionLog) Fail(errorDetails map[string]interface{}) {
 	il.ErrorDetails = errorDetails
 	now := time.Now()
 	il.CompletedAt = &now
-}
\ No newline at end of file
+}
+
+// RecordBatch adds the outcome of a persisted batch to the run counters
+func (il *IngestionLog) RecordBatch(successful, failed int) {
+	il.TotalRecords += successful + failed
+	il.SuccessfulRecords += successful
+	il.FailedRecords += failed
+}
+
+// RecordPersisted adds the outcome of a batch written to the stocks table to the run counters.
+// Inserted, updated and duplicate records all count as successful.
+func (il *IngestionLog) RecordPersisted(inserted, updated, duplicates, failed int) {
+	il.RecordBatch(inserted+updated+duplicates, failed)
+	il.InsertedRecords += inserted
+	il.UpdatedRecords += updated
+	il.DuplicateRecords += duplicates
+}
+
+// IsCompleted checks if the run finished without a fatal error
+func (il *IngestionLog) IsCompleted() bool {
+	return il.Status == IngestionStatusCompleted
+}
+
+// Duration returns how long the run took, or how long it has been running so far
+func (il *IngestionLog) Duration() time.Duration {
+	if il.CompletedAt == nil {
+		return time.Since(il.CreatedAt)
+	}
+	return il.CompletedAt.Sub(il.CreatedAt)
+}
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// Lease grants one holder exclusive use of a named job until it expires. Token is the fencing token:
+// it grows every time the lease changes hands, so work done under a superseded grant can be refused.
+type Lease struct {
+	Name       string    `json:"name" db:"name"`
+	Holder     string    `json:"holder" db:"holder"`
+	Token      int64     `json:"token" db:"token"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+	AcquiredAt time.Time `json:"acquired_at" db:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at" db:"renewed_at"`
+}
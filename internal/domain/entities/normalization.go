@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RatingTier is the canonical form of the free-text ratings brokers publish
+type RatingTier string
+
+const (
+	RatingTierStrongBuy  RatingTier = "strong_buy"
+	RatingTierBuy        RatingTier = "buy"
+	RatingTierHold       RatingTier = "hold"
+	RatingTierSell       RatingTier = "sell"
+	RatingTierStrongSell RatingTier = "strong_sell"
+	// RatingTierUnknown marks a rating no rule maps yet
+	RatingTierUnknown RatingTier = ""
+)
+
+// RatingTiers lists the canonical tiers from the most to the least favourable
+var RatingTiers = []RatingTier{RatingTierStrongBuy, RatingTierBuy, RatingTierHold, RatingTierSell, RatingTierStrongSell}
+
+// Score places the tier between 0 (strong sell) and 1 (strong buy); unknown ratings score 0
+func (t RatingTier) Score() float64 {
+	switch t {
+	case RatingTierStrongBuy:
+		return 1.0
+	case RatingTierBuy:
+		return 0.8
+	case RatingTierHold:
+		return 0.5
+	case RatingTierSell:
+		return 0.2
+	default:
+		return 0.0
+	}
+}
+
+func (t RatingTier) IsValid() bool {
+	for _, tier := range RatingTiers {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// ActionType is the canonical form of the free-text actions brokers publish
+type ActionType string
+
+const (
+	ActionTypeUpgrade     ActionType = "upgrade"
+	ActionTypeDowngrade   ActionType = "downgrade"
+	ActionTypeInitiate    ActionType = "initiate"
+	ActionTypeReiterate   ActionType = "reiterate"
+	ActionTypeTargetRaise ActionType = "target_raise"
+	ActionTypeTargetLower ActionType = "target_lower"
+	// ActionTypeUnknown marks an action no rule maps yet
+	ActionTypeUnknown ActionType = ""
+)
+
+// ActionTypes lists the canonical action types
+var ActionTypes = []ActionType{
+	ActionTypeUpgrade, ActionTypeDowngrade, ActionTypeInitiate,
+	ActionTypeReiterate, ActionTypeTargetRaise, ActionTypeTargetLower,
+}
+
+func (a ActionType) IsValid() bool {
+	for _, actionType := range ActionTypes {
+		if a == actionType {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizationKind tells which vocabulary a rule belongs to
+type NormalizationKind string
+
+const (
+	NormalizationKindRating NormalizationKind = "rating"
+	NormalizationKindAction NormalizationKind = "action"
+)
+
+// NormalizationRule maps a raw rating or action string onto its canonical value. Rating rules match
+// the whole rating, action rules any action containing the pattern; both ignore case and spacing.
+// A rule with a brokerage only applies to that broker's events and wins over rules for every broker.
+type NormalizationRule struct {
+	ID        uuid.UUID         `json:"id" db:"id"`
+	Kind      NormalizationKind `json:"kind" db:"kind"`
+	Brokerage string            `json:"brokerage" db:"brokerage"`
+	Pattern   string            `json:"pattern" db:"pattern"`
+	Value     string            `json:"value" db:"value"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+func NewNormalizationRule(kind NormalizationKind, brokerage, pattern, value string) *NormalizationRule {
+	now := time.Now()
+	return &NormalizationRule{
+		ID:        uuid.New(),
+		Kind:      kind,
+		Brokerage: strings.TrimSpace(brokerage),
+		Pattern:   NormalizeLabel(pattern),
+		Value:     value,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate checks that the rule has a pattern and maps it onto a canonical value of its kind
+func (r *NormalizationRule) Validate() error {
+	if r.Pattern == "" {
+		return fmt.Errorf("pattern is required")
+	}
+
+	switch r.Kind {
+	case NormalizationKindRating:
+		if !RatingTier(r.Value).IsValid() {
+			return fmt.Errorf("unknown rating tier %q", r.Value)
+		}
+	case NormalizationKindAction:
+		if !ActionType(r.Value).IsValid() {
+			return fmt.Errorf("unknown action type %q", r.Value)
+		}
+	default:
+		return fmt.Errorf("unknown rule kind %q, expected rating or action", r.Kind)
+	}
+
+	return nil
+}
+
+// Change replaces what the rule matches and the value it maps to
+func (r *NormalizationRule) Change(brokerage, pattern, value string) {
+	r.Brokerage = strings.TrimSpace(brokerage)
+	r.Pattern = NormalizeLabel(pattern)
+	r.Value = value
+	r.UpdatedAt = time.Now()
+}
+
+// NormalizeLabel puts a raw rating, action or pattern in the form rules are matched in:
+// lower case, with runs of whitespace, dashes and underscores collapsed into single spaces
+func NormalizeLabel(raw string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(raw), func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '-' || r == '_'
+	}), " ")
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusDelivered OutboxStatus = "delivered"
+	// OutboxStatusFailed marks an event whose subscribers kept failing until it ran out of attempts
+	OutboxStatusFailed OutboxStatus = "failed"
+)
+
+// OutboxEvent is a domain event held in the outbox until every subscriber has handled it. Attempts
+// counts the deliveries started, including one that is in flight.
+type OutboxEvent struct {
+	DomainEvent
+	Status        OutboxStatus `json:"status" db:"status"`
+	Attempts      int          `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time    `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     string       `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt   *time.Time   `json:"delivered_at,omitempty" db:"delivered_at"`
+}
@@ -0,0 +1,357 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QualitySeverity sets what happens to an ingested stock that breaks a data-quality rule
+type QualitySeverity string
+
+const (
+	// QualitySeverityWarn keeps the stock and only counts the violation
+	QualitySeverityWarn QualitySeverity = "warn"
+	// QualitySeverityQuarantine stores the upstream record as a dead letter for review instead of the stock
+	QualitySeverityQuarantine QualitySeverity = "quarantine"
+	// QualitySeverityReject drops the upstream record
+	QualitySeverityReject QualitySeverity = "reject"
+)
+
+// rank orders severities from the mildest, so the outcome of a stock is that of the worst rule it breaks
+func (s QualitySeverity) rank() int {
+	switch s {
+	case QualitySeverityWarn:
+		return 1
+	case QualitySeverityQuarantine:
+		return 2
+	case QualitySeverityReject:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// QualityRule is a declarative check on one field of an ingested stock, named by its JSON name. It applies
+// to every stock, or only to those whose text fields equal the values in When, and is broken when the field
+// fails any of the constraints set: Min and Max for targets, MaxLength, OneOf and NotEqualTo for text fields,
+// and MaxAhead for the event time.
+type QualityRule struct {
+	Name     string            `json:"name"`
+	Severity QualitySeverity   `json:"severity"`
+	Field    string            `json:"field"`
+	When     map[string]string `json:"when,omitempty"`
+
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// MaxLength is the longest text allowed, in characters
+	MaxLength int `json:"max_length,omitempty"`
+	// OneOf is the vocabulary the text must belong to, compared case-insensitively; empty text is not in it
+	OneOf []string `json:"one_of,omitempty"`
+	// NotEqualTo names another text field the text must differ from, compared case-insensitively
+	NotEqualTo string `json:"not_equal_to,omitempty"`
+	// MaxAhead is how far past ingestion an event may be dated, as a duration such as "1h"
+	MaxAhead string `json:"max_ahead,omitempty"`
+}
+
+// QualityViolation is a rule broken by a stock
+type QualityViolation struct {
+	Rule     string          `json:"rule"`
+	Severity QualitySeverity `json:"severity"`
+	Reason   string          `json:"reason"`
+}
+
+func (v QualityViolation) Error() string {
+	return fmt.Sprintf("quality rule %s: %s", v.Rule, v.Reason)
+}
+
+// qualityTextFields reads the text fields rules can check or condition on
+var qualityTextFields = map[string]func(*Stock) string{
+	"ticker":           func(s *Stock) string { return s.Ticker },
+	"company":          func(s *Stock) string { return s.Company },
+	"brokerage":        func(s *Stock) string { return s.Brokerage },
+	"action":           func(s *Stock) string { return s.Action },
+	"rating_from":      func(s *Stock) string { return s.RatingFrom },
+	"rating_to":        func(s *Stock) string { return s.RatingTo },
+	"action_type":      func(s *Stock) string { return string(s.ActionType) },
+	"rating_from_tier": func(s *Stock) string { return string(s.RatingFromTier) },
+	"rating_to_tier":   func(s *Stock) string { return string(s.RatingToTier) },
+	"source":           func(s *Stock) string { return s.Source },
+	"currency":         func(s *Stock) string { return s.Currency },
+}
+
+// qualityNumberFields reads the numeric fields rules can check
+var qualityNumberFields = map[string]func(*Stock) float64{
+	"target_from": func(s *Stock) float64 { return s.TargetFrom },
+	"target_to":   func(s *Stock) float64 { return s.TargetTo },
+}
+
+const qualityTimeField = "event_time"
+
+// QualityRuleSet is a validated list of rules, checked in order
+type QualityRuleSet struct {
+	rules    []QualityRule
+	maxAhead []time.Duration
+}
+
+// NewQualityRuleSet validates the rules: names are required and unique, severities known, and every rule
+// sets at least one constraint that suits its field.
+func NewQualityRuleSet(rules []QualityRule) (*QualityRuleSet, error) {
+	set := &QualityRuleSet{
+		rules:    rules,
+		maxAhead: make([]time.Duration, len(rules)),
+	}
+	names := make(map[string]bool, len(rules))
+
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("quality rule %d: name is required", i+1)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("quality rule %s: name is not unique", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if rule.Severity.rank() == 0 {
+			return nil, fmt.Errorf("quality rule %s: unknown severity %q", rule.Name, rule.Severity)
+		}
+		for field := range rule.When {
+			if _, ok := qualityTextFields[field]; !ok {
+				return nil, fmt.Errorf("quality rule %s: cannot condition on field %q", rule.Name, field)
+			}
+		}
+
+		maxAhead, err := rule.validate()
+		if err != nil {
+			return nil, fmt.Errorf("quality rule %s: %w", rule.Name, err)
+		}
+		set.maxAhead[i] = maxAhead
+	}
+
+	return set, nil
+}
+
+// validate checks the constraints of the rule suit its field, returning the parsed MaxAhead
+func (r QualityRule) validate() (time.Duration, error) {
+	_, isText := qualityTextFields[r.Field]
+	_, isNumber := qualityNumberFields[r.Field]
+	isTime := r.Field == qualityTimeField
+	if !isText && !isNumber && !isTime {
+		return 0, fmt.Errorf("unknown field %q", r.Field)
+	}
+
+	textConstraints := r.MaxLength > 0 || len(r.OneOf) > 0 || r.NotEqualTo != ""
+	numberConstraints := r.Min != nil || r.Max != nil
+	switch {
+	case textConstraints && !isText:
+		return 0, fmt.Errorf("max_length, one_of and not_equal_to only apply to text fields, not %s", r.Field)
+	case numberConstraints && !isNumber:
+		return 0, fmt.Errorf("min and max only apply to target prices, not %s", r.Field)
+	case r.MaxAhead != "" && !isTime:
+		return 0, fmt.Errorf("max_ahead only applies to %s, not %s", qualityTimeField, r.Field)
+	case !textConstraints && !numberConstraints && r.MaxAhead == "":
+		return 0, fmt.Errorf("no constraint set")
+	}
+
+	if r.NotEqualTo != "" {
+		if _, ok := qualityTextFields[r.NotEqualTo]; !ok {
+			return 0, fmt.Errorf("cannot compare with field %q", r.NotEqualTo)
+		}
+	}
+
+	if r.MaxAhead == "" {
+		return 0, nil
+	}
+	maxAhead, err := time.ParseDuration(r.MaxAhead)
+	if err != nil || maxAhead < 0 {
+		return 0, fmt.Errorf("invalid max_ahead %q", r.MaxAhead)
+	}
+	return maxAhead, nil
+}
+
+// ParseQualityRules reads a JSON array of rules into a validated set
+func ParseQualityRules(data []byte) (*QualityRuleSet, error) {
+	var rules []QualityRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode quality rules: %w", err)
+	}
+	return NewQualityRuleSet(rules)
+}
+
+// DefaultQualityRules catches the upstream slips seen most often: missing or negative targets, upgrades and
+// downgrades that leave the rating unchanged, events dated in the future, overlong tickers and actions the
+// vocabulary cannot map.
+func DefaultQualityRules() []QualityRule {
+	zero, cent := 0.0, 0.01
+	return []QualityRule{
+		{Name: "target_to_positive", Severity: QualitySeverityWarn, Field: "target_to", Min: &cent},
+		{Name: "target_from_not_negative", Severity: QualitySeverityQuarantine, Field: "target_from", Min: &zero},
+		{Name: "upgrade_changes_rating", Severity: QualitySeverityWarn, Field: "rating_to", NotEqualTo: "rating_from",
+			When: map[string]string{"action_type": string(ActionTypeUpgrade)}},
+		{Name: "downgrade_changes_rating", Severity: QualitySeverityWarn, Field: "rating_to", NotEqualTo: "rating_from",
+			When: map[string]string{"action_type": string(ActionTypeDowngrade)}},
+		{Name: "event_time_not_future", Severity: QualitySeverityQuarantine, Field: qualityTimeField, MaxAhead: "1h"},
+		{Name: "ticker_length", Severity: QualitySeverityQuarantine, Field: "ticker", MaxLength: 10},
+		{Name: "action_type_known", Severity: QualitySeverityWarn, Field: "action_type", OneOf: []string{
+			string(ActionTypeUpgrade), string(ActionTypeDowngrade), string(ActionTypeInitiate),
+			string(ActionTypeReiterate), string(ActionTypeTargetRaise), string(ActionTypeTargetLower),
+		}},
+	}
+}
+
+// Rules returns the rules of the set in the order they are checked
+func (s *QualityRuleSet) Rules() []QualityRule {
+	return s.rules
+}
+
+// Check returns the rules the stock breaks, with event times compared against now
+func (s *QualityRuleSet) Check(stock *Stock, now time.Time) []QualityViolation {
+	var violations []QualityViolation
+	for i, rule := range s.rules {
+		if !rule.applies(stock) {
+			continue
+		}
+		if reason := rule.check(stock, now, s.maxAhead[i]); reason != "" {
+			violations = append(violations, QualityViolation{Rule: rule.Name, Severity: rule.Severity, Reason: reason})
+		}
+	}
+	return violations
+}
+
+func (r QualityRule) applies(stock *Stock) bool {
+	for field, value := range r.When {
+		if !strings.EqualFold(strings.TrimSpace(qualityTextFields[field](stock)), value) {
+			return false
+		}
+	}
+	return true
+}
+
+// check returns why the stock breaks the rule, or an empty string if it does not
+func (r QualityRule) check(stock *Stock, now time.Time, maxAhead time.Duration) string {
+	if read, ok := qualityNumberFields[r.Field]; ok {
+		value := read(stock)
+		if r.Min != nil && value < *r.Min {
+			return fmt.Sprintf("%s %g is below %g", r.Field, value, *r.Min)
+		}
+		if r.Max != nil && value > *r.Max {
+			return fmt.Sprintf("%s %g is above %g", r.Field, value, *r.Max)
+		}
+		return ""
+	}
+
+	if r.Field == qualityTimeField {
+		if r.MaxAhead != "" && stock.EventTime.After(now.Add(maxAhead)) {
+			return fmt.Sprintf("%s %s is more than %s ahead of ingestion", r.Field, stock.EventTime.Format(time.RFC3339), r.MaxAhead)
+		}
+		return ""
+	}
+
+	value := strings.TrimSpace(qualityTextFields[r.Field](stock))
+	if r.MaxLength > 0 && len([]rune(value)) > r.MaxLength {
+		return fmt.Sprintf("%s %q is longer than %d characters", r.Field, value, r.MaxLength)
+	}
+	if len(r.OneOf) > 0 && !containsFold(r.OneOf, value) {
+		return fmt.Sprintf("%s %q is not one of %s", r.Field, value, strings.Join(r.OneOf, ", "))
+	}
+	if r.NotEqualTo != "" && strings.EqualFold(value, strings.TrimSpace(qualityTextFields[r.NotEqualTo](stock))) {
+		return fmt.Sprintf("%s %q equals %s", r.Field, value, r.NotEqualTo)
+	}
+	return ""
+}
+
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// WorstSeverity returns the severity of the worst violation, or an empty severity when there is none
+func WorstSeverity(violations []QualityViolation) QualitySeverity {
+	var worst QualitySeverity
+	for _, v := range violations {
+		if v.Severity.rank() > worst.rank() {
+			worst = v.Severity
+		}
+	}
+	return worst
+}
+
+// QualitySummary counts how the stocks of a run fared against the data-quality rules. Each stock counts once,
+// under the worst severity it broke, while Rules counts the violations of every rule broken at least once.
+type QualitySummary struct {
+	ID          uuid.UUID                    `json:"id" db:"id"`
+	BatchID     string                       `json:"batch_id" db:"batch_id"`
+	Source      string                       `json:"source" db:"source"`
+	Checked     int                          `json:"checked" db:"checked"`
+	Passed      int                          `json:"passed" db:"passed"`
+	Warned      int                          `json:"warned" db:"warned"`
+	Quarantined int                          `json:"quarantined" db:"quarantined"`
+	Rejected    int                          `json:"rejected" db:"rejected"`
+	Rules       map[string]*QualityRuleCount `json:"rules" db:"rules"`
+	CreatedAt   time.Time                    `json:"created_at" db:"created_at"`
+}
+
+// QualityRuleCount counts the violations of one rule, keeping the first as an example
+type QualityRuleCount struct {
+	Severity   QualitySeverity `json:"severity"`
+	Violations int             `json:"violations"`
+	Example    string          `json:"example"`
+}
+
+func NewQualitySummary(batchID, source string) *QualitySummary {
+	return &QualitySummary{
+		ID:        uuid.New(),
+		BatchID:   batchID,
+		Source:    source,
+		Rules:     make(map[string]*QualityRuleCount),
+		CreatedAt: time.Now(),
+	}
+}
+
+// Record counts a checked stock and the violations found on it, returning the severity it is handled with
+func (s *QualitySummary) Record(ticker string, violations []QualityViolation) QualitySeverity {
+	s.Checked++
+
+	for _, v := range violations {
+		count, ok := s.Rules[v.Rule]
+		if !ok {
+			count = &QualityRuleCount{Severity: v.Severity, Example: ticker + ": " + v.Reason}
+			s.Rules[v.Rule] = count
+		}
+		count.Violations++
+	}
+
+	worst := WorstSeverity(violations)
+	switch worst {
+	case QualitySeverityWarn:
+		s.Warned++
+	case QualitySeverityQuarantine:
+		s.Quarantined++
+	case QualitySeverityReject:
+		s.Rejected++
+	default:
+		s.Passed++
+	}
+	return worst
+}
+
+// BrokenRules returns the names of the rules broken at least once, sorted
+func (s *QualitySummary) BrokenRules() []string {
+	names := make([]string, 0, len(s.Rules))
+	for name := range s.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,167 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaProfile describes the shape of the upstream records a run received from a source, so vendor format
+// changes show up as drift from a baseline profile instead of as quietly wrong data: the keys the client does
+// not know, how often each known field arrives absent, null or empty, and how often times and prices fail to parse.
+type SchemaProfile struct {
+	ID      uuid.UUID `json:"id" db:"id"`
+	BatchID string    `json:"batch_id" db:"batch_id"`
+	Source  string    `json:"source" db:"source"`
+	Records int       `json:"records" db:"records"`
+	// UnknownKeys counts the records carrying each key the client ignores
+	UnknownKeys map[string]int `json:"unknown_keys" db:"-"`
+	// MissingFields counts the records where each known field is absent, null or empty
+	MissingFields map[string]int `json:"missing_fields" db:"-"`
+	// TimeValues and PriceValues count the non-empty event times and target prices, of which the failures did not parse
+	TimeValues    int `json:"time_values" db:"-"`
+	TimeFailures  int `json:"time_failures" db:"-"`
+	PriceValues   int `json:"price_values" db:"-"`
+	PriceFailures int `json:"price_failures" db:"-"`
+	// Drift lists where the profile moved away from the baseline it was compared with
+	Drift      []SchemaDrift `json:"drift,omitempty" db:"drift"`
+	IsBaseline bool          `json:"is_baseline" db:"is_baseline"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+}
+
+func NewSchemaProfile(batchID, source string) *SchemaProfile {
+	return &SchemaProfile{
+		ID:            uuid.New(),
+		BatchID:       batchID,
+		Source:        source,
+		UnknownKeys:   make(map[string]int),
+		MissingFields: make(map[string]int),
+		CreatedAt:     time.Now(),
+	}
+}
+
+// RecordUnknownKey counts a record carrying key, which the client does not know
+func (p *SchemaProfile) RecordUnknownKey(key string) {
+	p.UnknownKeys[key]++
+}
+
+// RecordMissing counts a record where field is absent, null or empty
+func (p *SchemaProfile) RecordMissing(field string) {
+	p.MissingFields[field]++
+}
+
+// RecordTime counts a non-empty event time and whether it parsed
+func (p *SchemaProfile) RecordTime(parsed bool) {
+	p.TimeValues++
+	if !parsed {
+		p.TimeFailures++
+	}
+}
+
+// RecordPrice counts a non-empty target price and whether it parsed
+func (p *SchemaProfile) RecordPrice(parsed bool) {
+	p.PriceValues++
+	if !parsed {
+		p.PriceFailures++
+	}
+}
+
+// UnknownKeyRate returns the share of records carrying key
+func (p *SchemaProfile) UnknownKeyRate(key string) float64 {
+	return rate(p.UnknownKeys[key], p.Records)
+}
+
+// MissingRate returns the share of records where field is absent, null or empty
+func (p *SchemaProfile) MissingRate(field string) float64 {
+	return rate(p.MissingFields[field], p.Records)
+}
+
+// TimeFailureRate returns the share of non-empty event times that did not parse
+func (p *SchemaProfile) TimeFailureRate() float64 {
+	return rate(p.TimeFailures, p.TimeValues)
+}
+
+// PriceFailureRate returns the share of non-empty target prices that did not parse
+func (p *SchemaProfile) PriceFailureRate() float64 {
+	return rate(p.PriceFailures, p.PriceValues)
+}
+
+func rate(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
+
+// DriftThresholds set how far a profile may move from its baseline before it counts as drift.
+// Rates are shares between 0 and 1.
+type DriftThresholds struct {
+	// UnknownKeyRate is the share of records a key the baseline never carried may appear in
+	UnknownKeyRate float64
+	// MissingRate is the increase allowed in the share of records missing a field
+	MissingRate float64
+	// ParseFailureRate is the increase allowed in the share of times or prices failing to parse
+	ParseFailureRate float64
+}
+
+type SchemaDriftKind string
+
+const (
+	SchemaDriftUnknownKey   SchemaDriftKind = "unknown_key"
+	SchemaDriftMissingField SchemaDriftKind = "missing_field"
+	SchemaDriftTimeFormat   SchemaDriftKind = "time_format"
+	SchemaDriftPriceFormat  SchemaDriftKind = "price_format"
+)
+
+// SchemaDrift is a rate that moved beyond its threshold between the baseline and a profile
+type SchemaDrift struct {
+	Kind     SchemaDriftKind `json:"kind"`
+	Field    string          `json:"field,omitempty"`
+	Baseline float64         `json:"baseline"`
+	Current  float64         `json:"current"`
+}
+
+func (d SchemaDrift) String() string {
+	subject := string(d.Kind)
+	if d.Field != "" {
+		subject += " " + d.Field
+	}
+	return fmt.Sprintf("%s: %.1f%% -> %.1f%%", subject, d.Baseline*100, d.Current*100)
+}
+
+// CompareTo lists where the profile drifted from baseline beyond thresholds, by kind and field
+func (p *SchemaProfile) CompareTo(baseline *SchemaProfile, thresholds DriftThresholds) []SchemaDrift {
+	var drift []SchemaDrift
+
+	for key := range p.UnknownKeys {
+		if baseline.UnknownKeys[key] > 0 {
+			continue
+		}
+		if current := p.UnknownKeyRate(key); current > thresholds.UnknownKeyRate {
+			drift = append(drift, SchemaDrift{Kind: SchemaDriftUnknownKey, Field: key, Current: current})
+		}
+	}
+
+	for field := range p.MissingFields {
+		if current, previous := p.MissingRate(field), baseline.MissingRate(field); current-previous > thresholds.MissingRate {
+			drift = append(drift, SchemaDrift{Kind: SchemaDriftMissingField, Field: field, Baseline: previous, Current: current})
+		}
+	}
+
+	if current, previous := p.TimeFailureRate(), baseline.TimeFailureRate(); current-previous > thresholds.ParseFailureRate {
+		drift = append(drift, SchemaDrift{Kind: SchemaDriftTimeFormat, Baseline: previous, Current: current})
+	}
+	if current, previous := p.PriceFailureRate(), baseline.PriceFailureRate(); current-previous > thresholds.ParseFailureRate {
+		drift = append(drift, SchemaDrift{Kind: SchemaDriftPriceFormat, Baseline: previous, Current: current})
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Kind != drift[j].Kind {
+			return drift[i].Kind < drift[j].Kind
+		}
+		return drift[i].Field < drift[j].Field
+	})
+	return drift
+}
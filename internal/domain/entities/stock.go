@@ -1,7 +1,7 @@
 package entities
 
 import (
-	"strings"
+	"math"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -19,10 +19,23 @@ type Stock struct {
 	RatingTo   string    `json:"rating_to" db:"rating_to"`
 	TargetFrom float64   `json:"target_from" db:"target_from"`
 	TargetTo   float64   `json:"target_to" db:"target_to"`
+	// Currency is the ISO 4217 code both targets are quoted in
+	Currency   string    `json:"currency" db:"currency"`
 	EventTime  time.Time `json:"event_time" db:"event_time"`
 	PriceClose *float64  `json:"price_close,omitempty" db:"price_close"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	// PriceCloseDate is the trading day PriceClose was taken from
+	PriceCloseDate *time.Time `json:"price_close_date,omitempty" db:"price_close_date"`
+	Source         string     `json:"source" db:"source"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Canonical forms of Action, RatingFrom and RatingTo, filled in by a Vocabulary
+	ActionType     ActionType `json:"action_type" db:"action_type"`
+	RatingFromTier RatingTier `json:"rating_from_tier" db:"rating_from_tier"`
+	RatingToTier   RatingTier `json:"rating_to_tier" db:"rating_to_tier"`
+
+	// Payload is the upstream item the stock was parsed from, stored alongside it when set
+	Payload *StockPayload `json:"-" db:"-"`
 }
 
 func NewStock(ticker, company, brokerage, action string, eventTime time.Time) *Stock {
@@ -33,6 +46,7 @@ func NewStock(ticker, company, brokerage, action string, eventTime time.Time) *S
 		Brokerage: brokerage,
 		Action:    action,
 		EventTime: eventTime,
+		Currency:  DefaultCurrency,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -43,17 +57,46 @@ func (s *Stock) Validate() error {
 	return validate.Struct(s)
 }
 
-func (s *Stock) IsUpgrade() bool {
-	upgradeActions := []string{"upgraded by", "raised to", "initiated by"}
+// HasRevisedFields checks if an upstream record for the same event corrects any of the analyst fields.
+// Targets are compared to the cent, the precision they are stored with.
+func (s *Stock) HasRevisedFields(previous *Stock) bool {
+	return len(s.RevisedFields(previous)) > 0
+}
 
-	for _, action := range upgradeActions {
-		if strings.Contains(strings.ToLower(s.Action), action) {
-			return true
-		}
+// RevisedFields lists, by their JSON name, the analyst fields this record corrects in previous
+func (s *Stock) RevisedFields(previous *Stock) []string {
+	var fields []string
+	if s.Company != previous.Company {
+		fields = append(fields, "company")
 	}
+	if s.BrokerID != previous.BrokerID {
+		fields = append(fields, "broker_id")
+	}
+	if s.Action != previous.Action {
+		fields = append(fields, "action")
+	}
+	if s.RatingFrom != previous.RatingFrom {
+		fields = append(fields, "rating_from")
+	}
+	if s.RatingTo != previous.RatingTo {
+		fields = append(fields, "rating_to")
+	}
+	if math.Round(s.TargetFrom*100) != math.Round(previous.TargetFrom*100) {
+		fields = append(fields, "target_from")
+	}
+	if math.Round(s.TargetTo*100) != math.Round(previous.TargetTo*100) {
+		fields = append(fields, "target_to")
+	}
+	if s.Currency != previous.Currency {
+		fields = append(fields, "currency")
+	}
+	return fields
+}
 
-	return false
-
+// IsUpgrade checks if the broker raised its view of the stock: a rating upgrade or new coverage
+func (s *Stock) IsUpgrade() bool {
+	actionType := s.normalizedActionType()
+	return actionType == ActionTypeUpgrade || actionType == ActionTypeInitiate
 }
 
 func (s *Stock) GetPriceTargetChange() float64 {
@@ -63,22 +106,10 @@ func (s *Stock) GetPriceTargetChange() float64 {
 	return (s.TargetTo - s.TargetFrom) / s.TargetFrom
 }
 
+// GetRatingScore scores the ratings before and after the event by their tier
 func (s *Stock) GetRatingScore() (fromScore, toScore float64) {
-	ratingScores := map[string]float64{
-		"strong buy":   1.0,
-		"buy":          0.8,
-		"outperform":   0.75,
-		"hold":         0.5,
-		"neutral":      0.4,
-		"underperform": 0.25,
-		"sell":         0.2,
-		"strong sell":  0.0,
-	}
-
-	fromScore = ratingScores[strings.ToLower(s.RatingFrom)]
-	toScore = ratingScores[strings.ToLower(s.RatingTo)]
-
-	return
+	fromTier, toTier := s.normalizedRatingTiers()
+	return fromTier.Score(), toTier.Score()
 }
 
 func (s *Stock) GetPriceChange() float64 {
@@ -97,15 +128,32 @@ func (s *Stock) GetRatingChangeScore() float64 {
 // IsRecommendation determines if this is a positive recommendation
 func (s *Stock) IsRecommendation() bool {
 	// Check for positive actions
-	positiveActions := []string{"upgraded", "initiated", "reiterated"}
-	action := strings.ToLower(s.Action)
-
-	for _, positive := range positiveActions {
-		if strings.Contains(action, positive) {
-			return true
-		}
+	switch s.normalizedActionType() {
+	case ActionTypeUpgrade, ActionTypeInitiate, ActionTypeReiterate:
+		return true
 	}
 
 	// Check for rating improvement
 	return s.GetRatingChangeScore() > 0
 }
+
+// normalizedActionType returns the stored action type, or classifies the action with the built-in
+// vocabulary for stocks that were never normalized
+func (s *Stock) normalizedActionType() ActionType {
+	if s.ActionType != ActionTypeUnknown {
+		return s.ActionType
+	}
+	return defaultVocabulary.ActionType(s.Brokerage, s.Action)
+}
+
+// normalizedRatingTiers returns the stored rating tiers, falling back to the built-in vocabulary like normalizedActionType
+func (s *Stock) normalizedRatingTiers() (from, to RatingTier) {
+	from, to = s.RatingFromTier, s.RatingToTier
+	if from == RatingTierUnknown {
+		from = defaultVocabulary.RatingTier(s.Brokerage, s.RatingFrom)
+	}
+	if to == RatingTierUnknown {
+		to = defaultVocabulary.RatingTier(s.Brokerage, s.RatingTo)
+	}
+	return from, to
+}
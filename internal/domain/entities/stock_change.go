@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockChangeKind string
+
+const (
+	StockChangeCreated StockChangeKind = "created"
+	StockChangeUpdated StockChangeKind = "updated"
+	StockChangeDeleted StockChangeKind = "deleted"
+)
+
+// StockChange is the audit record of an admin creating, editing or deleting a stock event through the API.
+// Before and After hold the event on either side of the change: Before is nil for a creation and After for
+// a deletion. It outlives the event, so the history of a deleted event can still be read.
+type StockChange struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	StockID   uuid.UUID       `json:"stock_id" db:"stock_id"`
+	Kind      StockChangeKind `json:"kind" db:"kind"`
+	ChangedBy uuid.UUID       `json:"changed_by" db:"changed_by"`
+	// Fields lists the fields an update changed, by their JSON name
+	Fields    []string  `json:"fields,omitempty" db:"fields"`
+	Before    *Stock    `json:"before,omitempty" db:"before"`
+	After     *Stock    `json:"after,omitempty" db:"after"`
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+}
+
+func NewStockChange(kind StockChangeKind, changedBy uuid.UUID, before, after *Stock) *StockChange {
+	change := &StockChange{
+		ID:        uuid.New(),
+		Kind:      kind,
+		ChangedBy: changedBy,
+		Before:    before,
+		After:     after,
+		ChangedAt: time.Now(),
+	}
+
+	switch {
+	case after != nil:
+		change.StockID = after.ID
+	case before != nil:
+		change.StockID = before.ID
+	}
+
+	if before != nil && after != nil {
+		if after.Ticker != before.Ticker {
+			change.Fields = append(change.Fields, "ticker")
+		}
+		if !after.EventTime.Equal(before.EventTime) {
+			change.Fields = append(change.Fields, "event_time")
+		}
+		change.Fields = append(change.Fields, after.RevisedFields(before)...)
+	}
+
+	return change
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockPayload is the raw upstream item a stock event was parsed from, kept for lineage and
+// so the event can be re-derived when the parsing rules change, without fetching it again
+type StockPayload struct {
+	StockID   uuid.UUID       `json:"stock_id" db:"stock_id"`
+	BatchID   string          `json:"batch_id" db:"batch_id"`
+	Source    string          `json:"source" db:"source"`
+	FetchedAt time.Time       `json:"fetched_at" db:"fetched_at"`
+	Payload   json.RawMessage `json:"payload" db:"payload"`
+}
+
+// NewStockPayload records payload as fetched from source at fetchedAt by the run batchID.
+// The stock it belongs to is only known once the event is stored.
+func NewStockPayload(batchID, source string, fetchedAt time.Time, payload json.RawMessage) *StockPayload {
+	return &StockPayload{
+		BatchID:   batchID,
+		Source:    source,
+		FetchedAt: fetchedAt,
+		Payload:   payload,
+	}
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockRevision keeps the values a stock event had before an upstream correction replaced them
+type StockRevision struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	StockID    uuid.UUID `json:"stock_id" db:"stock_id"`
+	Company    string    `json:"company" db:"company"`
+	BrokerID   uuid.UUID `json:"broker_id" db:"broker_id"`
+	Action     string    `json:"action" db:"action"`
+	RatingFrom string    `json:"rating_from" db:"rating_from"`
+	RatingTo   string    `json:"rating_to" db:"rating_to"`
+	TargetFrom float64   `json:"target_from" db:"target_from"`
+	TargetTo   float64   `json:"target_to" db:"target_to"`
+	Currency   string    `json:"currency" db:"currency"`
+	ValidFrom  time.Time `json:"valid_from" db:"valid_from"`
+	ValidTo    time.Time `json:"valid_to" db:"valid_to"`
+
+	ActionType     ActionType `json:"action_type" db:"action_type"`
+	RatingFromTier RatingTier `json:"rating_from_tier" db:"rating_from_tier"`
+	RatingToTier   RatingTier `json:"rating_to_tier" db:"rating_to_tier"`
+}
+
+// NewStockRevision captures the current values of previous, which are being superseded at supersededAt
+func NewStockRevision(previous *Stock, supersededAt time.Time) *StockRevision {
+	return &StockRevision{
+		ID:         uuid.New(),
+		StockID:    previous.ID,
+		Company:    previous.Company,
+		BrokerID:   previous.BrokerID,
+		Action:     previous.Action,
+		RatingFrom: previous.RatingFrom,
+		RatingTo:   previous.RatingTo,
+		TargetFrom: previous.TargetFrom,
+		TargetTo:   previous.TargetTo,
+		Currency:   previous.Currency,
+		ValidFrom:  previous.UpdatedAt,
+		ValidTo:    supersededAt,
+
+		ActionType:     previous.ActionType,
+		RatingFromTier: previous.RatingFromTier,
+		RatingToTier:   previous.RatingToTier,
+	}
+}
@@ -9,6 +9,7 @@ import (
 )
 
 type UserTier string
+type UserRole string
 type SubscriptionStatus string
 
 const (
@@ -17,6 +18,10 @@ const (
 	TIER_BASIC   UserTier = "basic"   // Registered users
 	TIER_PREMIUM UserTier = "premium" // Premium subscribers
 
+	// User Roles
+	ROLE_USER  UserRole = "user"  // Regular users
+	ROLE_ADMIN UserRole = "admin" // Operators with access to admin endpoints
+
 	// Subscription Status
 	SUB_STATUS_ACTIVE    SubscriptionStatus = "active"
 	SUB_STATUS_CANCELLED SubscriptionStatus = "cancelled"
@@ -48,6 +53,7 @@ type User struct {
 	FirstName  string     `json:"first_name" db:"first_name" validate:"required,min=1,max=100"`
 	LastName   string     `json:"last_name" db:"last_name" validate:"required,min=1,max=100"`
 	Tier       UserTier   `json:"tier" db:"tier"`
+	Role       UserRole   `json:"role" db:"role"`
 	IsVerified bool       `json:"is_verified" db:"is_verified"`
 	LastLogin  *time.Time `json:"last_login,omitempty" db:"last_login"`
 	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
@@ -69,6 +75,7 @@ func NewUser(email, password, firstName, lastName string) (*User, error) {
 		FirstName:  firstName,
 		LastName:   lastName,
 		Tier:       TIER_BASIC,
+		Role:       ROLE_USER,
 		IsVerified: false,
 		CreatedAt:  now,
 		UpdatedAt:  now,
@@ -107,6 +114,11 @@ func (u *User) CanAccessAIFeatures() bool {
 	return u.IsVerified && u.Tier == TIER_PREMIUM
 }
 
+// IsAdmin checks if the user can access administrative endpoints
+func (u *User) IsAdmin() bool {
+	return u.Role == ROLE_ADMIN
+}
+
 // GetAPIRateLimit returns the hourly API rate limit based on user tier
 func (u *User) GetAPIRateLimit() int {
 	if !u.IsVerified {
@@ -186,6 +198,7 @@ func (u *User) SanitizeForJSON() map[string]interface{} {
 		"first_name":  u.FirstName,
 		"last_name":   u.LastName,
 		"tier":        u.Tier,
+		"role":        u.Role,
 		"is_verified": u.IsVerified,
 		"last_login":  u.LastLogin,
 		"created_at":  u.CreatedAt,
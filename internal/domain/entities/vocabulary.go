@@ -0,0 +1,143 @@
+package entities
+
+import "strings"
+
+// defaultRatings maps the ratings most brokers publish onto their tier
+var defaultRatings = map[RatingTier][]string{
+	RatingTierStrongBuy: {"strong buy", "conviction buy", "top pick"},
+	RatingTierBuy: {
+		"buy", "outperform", "overweight", "market outperform", "sector outperform", "positive",
+		"accumulate", "add", "moderate buy", "speculative buy", "outperformer",
+	},
+	RatingTierHold: {
+		"hold", "neutral", "equal weight", "market perform", "sector perform", "in line", "peer perform",
+		"sector weight", "market weight", "perform", "mixed", "fair value",
+	},
+	RatingTierSell: {
+		"sell", "underperform", "underweight", "market underperform", "sector underperform", "reduce",
+		"negative", "moderate sell", "underperformer",
+	},
+	RatingTierStrongSell: {"strong sell"},
+}
+
+// defaultActions maps phrases found in the actions most brokers publish onto their type
+var defaultActions = map[ActionType][]string{
+	ActionTypeUpgrade:     {"upgrade", "upgraded", "raised to"},
+	ActionTypeDowngrade:   {"downgrade", "downgraded", "lowered to", "cut to"},
+	ActionTypeInitiate:    {"initiate", "initiated", "initiates"},
+	ActionTypeReiterate:   {"reiterate", "reiterated", "reiterates", "maintained", "maintains", "reaffirmed"},
+	ActionTypeTargetRaise: {"target raised", "raises target", "raised target"},
+	ActionTypeTargetLower: {"target lowered", "lowers target", "lowered target", "target cut"},
+}
+
+var defaultVocabulary = NewVocabulary(nil)
+
+// DefaultVocabulary returns the built-in vocabulary, without any stored rules
+func DefaultVocabulary() *Vocabulary {
+	return defaultVocabulary
+}
+
+// Vocabulary normalizes raw ratings and actions. Stored rules are layered over the built-in defaults:
+// a rule for the event's broker wins over a rule for every broker, which wins over the defaults.
+// Among action rules of the same level, the longest matching pattern wins.
+type Vocabulary struct {
+	ratings map[string]map[string]RatingTier
+	actions map[string][]actionPattern
+}
+
+type actionPattern struct {
+	pattern string
+	value   ActionType
+}
+
+// anyBroker keys the rules that apply to every broker; builtIn keys the defaults
+const (
+	anyBroker = ""
+	builtIn   = "\x00"
+)
+
+// NewVocabulary builds a vocabulary from the built-in defaults and the given rules
+func NewVocabulary(rules []*NormalizationRule) *Vocabulary {
+	v := &Vocabulary{
+		ratings: make(map[string]map[string]RatingTier),
+		actions: make(map[string][]actionPattern),
+	}
+
+	for tier, labels := range defaultRatings {
+		for _, label := range labels {
+			v.addRating(builtIn, label, tier)
+		}
+	}
+	for actionType, patterns := range defaultActions {
+		for _, pattern := range patterns {
+			v.addAction(builtIn, pattern, actionType)
+		}
+	}
+
+	for _, rule := range rules {
+		broker := strings.ToLower(rule.Brokerage)
+		switch rule.Kind {
+		case NormalizationKindRating:
+			v.addRating(broker, rule.Pattern, RatingTier(rule.Value))
+		case NormalizationKindAction:
+			v.addAction(broker, rule.Pattern, ActionType(rule.Value))
+		}
+	}
+
+	return v
+}
+
+func (v *Vocabulary) addRating(broker, label string, tier RatingTier) {
+	if v.ratings[broker] == nil {
+		v.ratings[broker] = make(map[string]RatingTier)
+	}
+	v.ratings[broker][NormalizeLabel(label)] = tier
+}
+
+func (v *Vocabulary) addAction(broker, pattern string, actionType ActionType) {
+	v.actions[broker] = append(v.actions[broker], actionPattern{pattern: NormalizeLabel(pattern), value: actionType})
+}
+
+// RatingTier returns the tier of a raw rating published by brokerage
+func (v *Vocabulary) RatingTier(brokerage, raw string) RatingTier {
+	label := NormalizeLabel(raw)
+	if label == "" {
+		return RatingTierUnknown
+	}
+
+	for _, broker := range []string{strings.ToLower(brokerage), anyBroker, builtIn} {
+		if tier, ok := v.ratings[broker][label]; ok {
+			return tier
+		}
+	}
+	return RatingTierUnknown
+}
+
+// ActionType returns the type of a raw action published by brokerage
+func (v *Vocabulary) ActionType(brokerage, raw string) ActionType {
+	label := " " + NormalizeLabel(raw) + " "
+	if label == "  " {
+		return ActionTypeUnknown
+	}
+
+	for _, broker := range []string{strings.ToLower(brokerage), anyBroker, builtIn} {
+		var match actionPattern
+		for _, candidate := range v.actions[broker] {
+			// Patterns only match whole words, so "upgrade" does not match "upgraded"
+			if len(candidate.pattern) > len(match.pattern) && strings.Contains(label, " "+candidate.pattern+" ") {
+				match = candidate
+			}
+		}
+		if match.pattern != "" {
+			return match.value
+		}
+	}
+	return ActionTypeUnknown
+}
+
+// Normalize fills in the canonical action type and rating tiers of stock from its raw values
+func (v *Vocabulary) Normalize(stock *Stock) {
+	stock.ActionType = v.ActionType(stock.Brokerage, stock.Action)
+	stock.RatingFromTier = v.RatingTier(stock.Brokerage, stock.RatingFrom)
+	stock.RatingToTier = v.RatingTier(stock.Brokerage, stock.RatingTo)
+}
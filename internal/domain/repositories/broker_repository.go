@@ -15,4 +15,15 @@ type BrokerRepository interface {
 	Update(ctx context.Context, broker *entities.Broker) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	UpsertByName(ctx context.Context, broker *entities.Broker) error
+
+	// Aliases
+	ListAliases(ctx context.Context) ([]*entities.BrokerAlias, error)
+	CreateAlias(ctx context.Context, alias *entities.BrokerAlias) error
+	DeleteAlias(ctx context.Context, id uuid.UUID) error
+
+	// Merge re-points the stocks and aliases of merge.MergedID to merge.SurvivorID, keeps the merged name
+	// as an alias, deletes the merged broker and stores merge, all in one transaction. It fills in the
+	// counts of merge.
+	Merge(ctx context.Context, merge *entities.BrokerMerge) error
+	ListMerges(ctx context.Context, limit int) ([]*entities.BrokerMerge, error)
 }
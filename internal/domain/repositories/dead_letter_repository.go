@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"stock-tracker/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetterRepository defines the interface for quarantined upstream records
+type DeadLetterRepository interface {
+	// Create stores a newly quarantined record
+	Create(ctx context.Context, record *entities.DeadLetterRecord) error
+
+	// GetByID retrieves a record by its ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error)
+
+	// List retrieves records with the given status (all statuses when empty), newest first,
+	// along with the total number of matching records
+	List(ctx context.Context, status entities.DeadLetterStatus, limit, offset int) ([]*entities.DeadLetterRecord, int, error)
+
+	// Update persists the payload, reason, status and attempts of a record. Only a record that is still
+	// pending is updated, so a record resolved in the meantime gives ErrNotFound instead of being resolved twice.
+	Update(ctx context.Context, record *entities.DeadLetterRecord) error
+}
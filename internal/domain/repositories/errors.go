@@ -0,0 +1,18 @@
+package repositories
+
+import "errors"
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// ErrLeaseHeld is returned when another holder has an unexpired lease on the job
+var ErrLeaseHeld = errors.New("lease is held by another holder")
+
+// ErrLeaseLost is returned when a lease has expired or passed to another holder since it was granted
+var ErrLeaseLost = errors.New("lease was lost")
+
+// ErrDuplicate is returned when a record conflicts with a stored one on a unique key
+var ErrDuplicate = errors.New("record already exists")
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed or was issued for another sort order
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
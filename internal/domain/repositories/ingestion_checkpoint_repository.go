@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"stock-tracker/internal/domain/entities"
+)
+
+// IngestionCheckpointRepository defines the interface for ingestion checkpoint persistence
+type IngestionCheckpointRepository interface {
+	// Save creates the checkpoint or overwrites its progress
+	Save(ctx context.Context, checkpoint *entities.IngestionCheckpoint) error
+
+	// GetLatest retrieves the most recently opened checkpoint of a source, or ErrNotFound if there is none
+	GetLatest(ctx context.Context, source string) (*entities.IngestionCheckpoint, error)
+}
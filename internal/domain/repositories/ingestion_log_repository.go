@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"stock-tracker/internal/domain/entities"
+)
+
+// IngestionLogRepository defines the interface for ingestion run persistence
+type IngestionLogRepository interface {
+	// Create stores a new ingestion run
+	Create(ctx context.Context, log *entities.IngestionLog) error
+
+	// Update persists the counters, status and completion time of a run
+	Update(ctx context.Context, log *entities.IngestionLog) error
+
+	// GetByBatchID retrieves a run by its batch ID
+	GetByBatchID(ctx context.Context, batchID string) (*entities.IngestionLog, error)
+
+	// List retrieves runs ordered from newest to oldest along with the total number of runs
+	List(ctx context.Context, limit, offset int) ([]*entities.IngestionLog, int, error)
+
+	// GetLastSuccessful retrieves the most recent run that completed successfully
+	GetLastSuccessful(ctx context.Context) (*entities.IngestionLog, error)
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// LeaseRepository grants time-bound exclusive leases on named jobs
+type LeaseRepository interface {
+	// TryAcquire grants holder the lease on name for ttl if it is free or expired, with a fencing token
+	// greater than any granted before, or returns ErrLeaseHeld
+	TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (*entities.Lease, error)
+
+	// Renew extends the lease for ttl while it is still held under its fencing token, or returns ErrLeaseLost
+	Renew(ctx context.Context, lease *entities.Lease, ttl time.Duration) error
+
+	// Release expires the lease if it is still held under its fencing token, so another holder can take it at once
+	Release(ctx context.Context, lease *entities.Lease) error
+}
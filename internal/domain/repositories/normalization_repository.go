@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"stock-tracker/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// NormalizationRepository defines the interface for the rules that normalize ratings and actions,
+// and for the normalized values stored with each stock
+type NormalizationRepository interface {
+	// ListRules retrieves the rules of the given kind (all kinds when empty), ordered by kind, brokerage and pattern
+	ListRules(ctx context.Context, kind entities.NormalizationKind) ([]*entities.NormalizationRule, error)
+
+	// GetRule retrieves a rule by its ID
+	GetRule(ctx context.Context, id uuid.UUID) (*entities.NormalizationRule, error)
+
+	// CreateRule stores a new rule, failing with ErrDuplicate if one already matches the same pattern
+	CreateRule(ctx context.Context, rule *entities.NormalizationRule) error
+
+	// UpdateRule persists the brokerage, pattern and value of a rule
+	UpdateRule(ctx context.Context, rule *entities.NormalizationRule) error
+
+	// DeleteRule removes a rule
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+
+	// ListStockLabels retrieves every distinct combination of raw labels stored with stocks, with its normalized values
+	ListStockLabels(ctx context.Context) ([]StockLabels, error)
+
+	// UpdateStockLabels stores the normalized values of the given label combinations on every matching stock,
+	// returning the number of stocks changed
+	UpdateStockLabels(ctx context.Context, labels []StockLabels) (int, error)
+}
+
+// StockLabels is a combination of raw labels shared by stocks, along with their normalized values
+type StockLabels struct {
+	Brokerage      string              `json:"brokerage"`
+	Action         string              `json:"action"`
+	RatingFrom     string              `json:"rating_from"`
+	RatingTo       string              `json:"rating_to"`
+	ActionType     entities.ActionType `json:"action_type"`
+	RatingFromTier entities.RatingTier `json:"rating_from_tier"`
+	RatingToTier   entities.RatingTier `json:"rating_to_tier"`
+	Stocks         int                 `json:"stocks"`
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// OutboxRepository hands out the domain events written to the outbox by the other repositories, which
+// add them in the transactions of the changes they record
+type OutboxRepository interface {
+	// Claim takes up to limit pending events that are due, oldest first, counting an attempt on each and
+	// hiding them from other claims for claimFor, so a dispatcher that dies mid-delivery only delays them
+	Claim(ctx context.Context, limit int, claimFor time.Duration) ([]*entities.OutboxEvent, error)
+
+	// MarkDelivered records that every subscriber handled the event
+	MarkDelivered(ctx context.Context, id uuid.UUID) error
+
+	// Reschedule records a failed delivery and makes the event due again at the given time
+	Reschedule(ctx context.Context, id uuid.UUID, lastError string, at time.Time) error
+
+	// MarkFailed records a failed delivery and gives up on the event
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"stock-tracker/internal/domain/entities"
+)
+
+// QualitySummaryRepository defines the interface for persisting the data-quality summaries of runs
+type QualitySummaryRepository interface {
+	// Save stores the summary of a run
+	Save(ctx context.Context, summary *entities.QualitySummary) error
+
+	// GetByBatchID retrieves the summary of a run, or ErrNotFound if the run has none
+	GetByBatchID(ctx context.Context, batchID string) (*entities.QualitySummary, error)
+
+	// List retrieves summaries ordered from newest to oldest along with the total number of summaries
+	List(ctx context.Context, limit, offset int) ([]*entities.QualitySummary, int, error)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"stock-tracker/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// SchemaProfileRepository defines the interface for persisting the upstream schema profiles of runs
+type SchemaProfileRepository interface {
+	// Save stores the profile; a baseline profile replaces the previous baseline of its source
+	Save(ctx context.Context, profile *entities.SchemaProfile) error
+
+	// GetBaseline retrieves the baseline profile of a source, or ErrNotFound if there is none
+	GetBaseline(ctx context.Context, source string) (*entities.SchemaProfile, error)
+
+	// GetLatest retrieves the most recent profile of a source, or ErrNotFound if there is none
+	GetLatest(ctx context.Context, source string) (*entities.SchemaProfile, error)
+
+	// SetBaseline makes the profile the baseline of its source, or returns ErrNotFound
+	SetBaseline(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// StockChangeRepository reads the audit trail of stock events edited by admins. Changes are recorded
+// by StockRepository.ApplyChange, in the transaction that makes them.
+type StockChangeRepository interface {
+	// ListByStock retrieves the changes made to a stock event, newest first
+	ListByStock(ctx context.Context, stockID uuid.UUID) ([]*entities.StockChange, error)
+}
@@ -15,21 +15,84 @@ type StockRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Stock, error)
 	Update(ctx context.Context, stock *entities.Stock) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// ApplyChange creates, updates or deletes the stock of an admin's change by its kind and stores the
+	// change in the stock_changes audit trail, all in one transaction
+	ApplyChange(ctx context.Context, change *entities.StockChange) error
 
 	//Query operations
 	GetByTicker(ctx context.Context, ticker string) ([]*entities.Stock, error)
+	GetByTickerAsOf(ctx context.Context, ticker string, asOf time.Time) ([]*entities.Stock, error)
+	// GetByTickerWithCredibility also returns the credibility score of each broker covering the ticker, by broker ID
+	GetByTickerWithCredibility(ctx context.Context, ticker string) ([]*entities.Stock, map[uuid.UUID]float64, error)
 	GetLatestByTicker(ctx context.Context, ticker string) (*entities.Stock, error)
 	GetAll(ctx context.Context, filters valueObjects.StockFilters) ([]*entities.Stock, *valueObjects.Pagination, error)
 	GetRecentByTickers(ctx context.Context, since time.Time) (map[string][]*entities.Stock, error)
+	GetNearbyEvents(ctx context.Context, tickers []string, from, to time.Time) ([]*entities.Stock, error)
 
 	//Batch operations
 	BulkCreate(ctx context.Context, stocks []*entities.Stock) error
 	BulkUpdate(ctx context.Context, stocks []*entities.Stock) error
+	// BulkUpsert also writes the domain events of the stocks it inserts or corrects to the outbox
+	BulkUpsert(ctx context.Context, stocks []*entities.Stock) (*UpsertResult, error)
 
 	//Analytics queries
 	GetTopMoversByTarget(ctx context.Context, limit int) ([]*entities.Stock, error)
 	GetUniqueTickersCount(ctx context.Context) (int, error)
+	// GetLatestEventTime returns the newest event time stored from source, or nil when it has stored none
+	GetLatestEventTime(ctx context.Context, source string) (*time.Time, error)
 	GetBrokerageStats(ctx context.Context) ([]BrokerageStats, error)
+
+	//Price enrichment
+	ListPricingTickers(ctx context.Context, filter PricingFilter) ([]string, error)
+	ListPricingCandidates(ctx context.Context, ticker string, filter PricingFilter) ([]PricingCandidate, error)
+	UpdateClosePrices(ctx context.Context, prices []ClosePrice) (int, error)
+
+	//Lineage
+	ListPayloads(ctx context.Context, filter PayloadFilter) ([]StoredPayload, error)
+}
+
+// PayloadFilter selects a page of stored raw payloads, in stock ID order
+type PayloadFilter struct {
+	// Source restricts the payloads to those fetched from this source when set
+	Source string
+	// BatchID restricts the payloads to those stored by this run when set
+	BatchID string
+	// Tickers restricts the payloads to the events of these tickers when set
+	Tickers []string
+	// After is the stock ID the page starts after, uuid.Nil for the first page
+	After uuid.UUID
+	Limit int
+}
+
+// StoredPayload is a raw payload with the key of the stock event it is stored against
+type StoredPayload struct {
+	Payload   *entities.StockPayload
+	Ticker    string
+	EventTime time.Time
+}
+
+// PricingFilter selects the stocks to enrich with a closing price: by default those without one
+type PricingFilter struct {
+	// From and To bound the event time when set
+	From time.Time
+	To   time.Time
+	// Tickers restricts enrichment to these tickers when set
+	Tickers []string
+	// Overwrite includes stocks that already have a close, to re-enrich them
+	Overwrite bool
+}
+
+// PricingCandidate is a stock event to enrich with a closing price
+type PricingCandidate struct {
+	ID        uuid.UUID
+	EventTime time.Time
+}
+
+// ClosePrice is the closing price found for a stock event and the trading day it was taken from
+type ClosePrice struct {
+	StockID uuid.UUID
+	Close   float64
+	Date    time.Time
 }
 
 type BrokerageStats struct {
@@ -37,3 +100,19 @@ type BrokerageStats struct {
 	Count     int     `json:"count"`
 	AvgScore  float64 `json:"avg_score"`
 }
+
+// UpsertResult counts how the stocks passed to BulkUpsert were applied.
+// A stock that fails is rolled back on its own and listed in Failures.
+type UpsertResult struct {
+	Inserted   int             `json:"inserted"`
+	Updated    int             `json:"updated"`
+	Duplicates int             `json:"duplicates"`
+	Failed     int             `json:"failed"`
+	Failures   []UpsertFailure `json:"-"`
+}
+
+// UpsertFailure is a stock that could not be stored, with the reason
+type UpsertFailure struct {
+	Stock *entities.Stock
+	Err   error
+}
@@ -25,6 +25,9 @@ type SubscriptionRepository interface {
 	// Update modifies an existing subscription in the repository
 	Update(ctx context.Context, subscription *entities.Subscription) error
 
+	// Activate stores an activated subscription and the upgraded tier of its user in one transaction
+	Activate(ctx context.Context, subscription *entities.Subscription, user *entities.User) error
+
 	// GetExpiring retrieves all subscriptions that will expire within the given duration
 	GetExpiring(ctx context.Context, within time.Duration) ([]*entities.Subscription, error)
 
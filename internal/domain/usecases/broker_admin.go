@@ -0,0 +1,156 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+// DefaultDuplicateSimilarity is the similarity from which two broker names are suggested as duplicates
+const DefaultDuplicateSimilarity = 0.7
+
+type BrokerAdminUseCase struct {
+	brokerRepo repositories.BrokerRepository
+	logger     logger.Logger
+}
+
+func NewBrokerAdminUseCase(brokerRepo repositories.BrokerRepository, logger logger.Logger) BrokerUseCase {
+	return &BrokerAdminUseCase{
+		brokerRepo: brokerRepo,
+		logger:     logger,
+	}
+}
+
+// ListAliases returns every broker alias
+func (uc *BrokerAdminUseCase) ListAliases(ctx context.Context) ([]*entities.BrokerAlias, error) {
+	aliases, err := uc.brokerRepo.ListAliases(ctx)
+	if err != nil {
+		uc.logger.Error("Failed to list broker aliases", "error", err)
+		return nil, fmt.Errorf("failed to retrieve broker aliases: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// CreateAlias attributes the events published under alias to a broker. An alias matching the name of
+// another broker is rejected: those brokers are duplicates and should be merged instead.
+func (uc *BrokerAdminUseCase) CreateAlias(ctx context.Context, brokerID uuid.UUID, name string) (*entities.BrokerAlias, error) {
+	key := entities.BrokerKey(name)
+	if key == "" {
+		return nil, fmt.Errorf("%w: alias is required", ErrBrokerAliasInvalid)
+	}
+
+	broker, err := uc.brokerRepo.GetByID(ctx, brokerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve broker %s: %w", brokerID, err)
+	}
+
+	brokers, err := uc.brokerRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve brokers: %w", err)
+	}
+	for _, other := range brokers {
+		if entities.BrokerKey(other.Name) != key {
+			continue
+		}
+		if other.ID == broker.ID {
+			return nil, fmt.Errorf("%w: %q already matches the name of %s", ErrBrokerAliasInvalid, name, broker.Name)
+		}
+		return nil, fmt.Errorf("%w: %q matches broker %s, merge the brokers instead", ErrBrokerAliasInvalid, name, other.Name)
+	}
+
+	alias := entities.NewBrokerAlias(broker.ID, name)
+	if err := uc.brokerRepo.CreateAlias(ctx, alias); err != nil {
+		uc.logger.Error("Failed to create broker alias", "alias", alias.Alias, "error", err)
+		return nil, err
+	}
+
+	uc.logger.Info("Created broker alias", "broker", broker.Name, "alias", alias.Alias)
+	return alias, nil
+}
+
+// DeleteAlias removes an alias; events published under it create a new broker again
+func (uc *BrokerAdminUseCase) DeleteAlias(ctx context.Context, id uuid.UUID) error {
+	if err := uc.brokerRepo.DeleteAlias(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete broker alias %s: %w", id, err)
+	}
+
+	uc.logger.Info("Deleted broker alias", "id", id)
+	return nil
+}
+
+// SuggestDuplicates returns the pairs of brokers whose names are at least minSimilarity alike, most similar
+// first. They are only suggestions: nothing changes until the brokers are merged.
+func (uc *BrokerAdminUseCase) SuggestDuplicates(ctx context.Context, minSimilarity float64) ([]BrokerDuplicate, error) {
+	if minSimilarity <= 0 || minSimilarity > 1 {
+		minSimilarity = DefaultDuplicateSimilarity
+	}
+
+	brokers, err := uc.brokerRepo.GetAll(ctx)
+	if err != nil {
+		uc.logger.Error("Failed to list brokers", "error", err)
+		return nil, fmt.Errorf("failed to retrieve brokers: %w", err)
+	}
+
+	duplicates := []BrokerDuplicate{}
+	for i, broker := range brokers {
+		for _, other := range brokers[i+1:] {
+			if similarity := entities.BrokerSimilarity(broker.Name, other.Name); similarity >= minSimilarity {
+				duplicates = append(duplicates, BrokerDuplicate{Broker: broker, Duplicate: other, Similarity: similarity})
+			}
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Similarity > duplicates[j].Similarity
+	})
+
+	return duplicates, nil
+}
+
+// MergeBrokers folds the merged broker into the survivor, which keeps its own name and credibility score.
+// Every stock of the merged broker is re-pointed to the survivor and its name becomes an alias.
+func (uc *BrokerAdminUseCase) MergeBrokers(ctx context.Context, survivorID, mergedID uuid.UUID, reason string) (*entities.BrokerMerge, error) {
+	if survivorID == mergedID {
+		return nil, fmt.Errorf("%w: a broker cannot be merged into itself", ErrBrokerMergeInvalid)
+	}
+
+	survivor, err := uc.brokerRepo.GetByID(ctx, survivorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve surviving broker %s: %w", survivorID, err)
+	}
+	merged, err := uc.brokerRepo.GetByID(ctx, mergedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve merged broker %s: %w", mergedID, err)
+	}
+
+	merge := entities.NewBrokerMerge(survivor, merged, reason)
+	if err := uc.brokerRepo.Merge(ctx, merge); err != nil {
+		uc.logger.Error("Failed to merge brokers", "merged", merged.Name, "error", err)
+		return nil, fmt.Errorf("failed to merge broker %s into %s: %w", merged.Name, survivor.Name, err)
+	}
+
+	uc.logger.Info("Merged brokers", "survivor", survivor.Name, "merged", merged.Name, "stocks", merge.StocksMoved)
+	return merge, nil
+}
+
+// ListMerges returns the most recent broker merges
+func (uc *BrokerAdminUseCase) ListMerges(ctx context.Context, limit int) ([]*entities.BrokerMerge, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	merges, err := uc.brokerRepo.ListMerges(ctx, limit)
+	if err != nil {
+		uc.logger.Error("Failed to list broker merges", "error", err)
+		return nil, fmt.Errorf("failed to retrieve broker merges: %w", err)
+	}
+
+	return merges, nil
+}
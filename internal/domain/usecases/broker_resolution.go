@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+)
+
+// loadBrokerIndex maps the key of every broker name and alias onto its broker. When two brokers share a
+// key the first by name keeps it until they are merged; aliases are explicit, so they win over names.
+func loadBrokerIndex(ctx context.Context, brokerRepo repositories.BrokerRepository) (map[string]*entities.Broker, error) {
+	brokers, err := brokerRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brokers: %w", err)
+	}
+
+	aliases, err := brokerRepo.ListAliases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broker aliases: %w", err)
+	}
+
+	index := make(map[string]*entities.Broker, len(brokers)+len(aliases))
+	byID := make(map[uuid.UUID]*entities.Broker, len(brokers))
+	for _, broker := range brokers {
+		byID[broker.ID] = broker
+		key := entities.BrokerKey(broker.Name)
+		if _, taken := index[key]; !taken {
+			index[key] = broker
+		}
+	}
+
+	for _, alias := range aliases {
+		if broker, ok := byID[alias.BrokerID]; ok {
+			index[entities.BrokerKey(alias.Alias)] = broker
+		}
+	}
+
+	return index, nil
+}
+
+// resolveBroker finds the broker by name or alias, creating it with the default credibility score when unknown
+func resolveBroker(ctx context.Context, brokerRepo repositories.BrokerRepository, name string) (*entities.Broker, error) {
+	index, err := loadBrokerIndex(ctx, brokerRepo)
+	if err != nil {
+		return nil, err
+	}
+	if broker, ok := index[entities.BrokerKey(name)]; ok {
+		return broker, nil
+	}
+
+	broker := entities.NewBroker(name, 0.60)
+	if err := brokerRepo.Create(ctx, broker); err != nil {
+		return nil, err
+	}
+	return broker, nil
+}
@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"stock-tracker/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrBrokerAliasInvalid is returned when an alias is empty or already resolves to a broker by its name
+	ErrBrokerAliasInvalid = errors.New("invalid broker alias")
+	// ErrBrokerMergeInvalid is returned when a broker is merged into itself
+	ErrBrokerMergeInvalid = errors.New("invalid broker merge")
+)
+
+// BrokerDuplicate is a pair of brokers whose names suggest they are the same broker
+type BrokerDuplicate struct {
+	Broker     *entities.Broker `json:"broker"`
+	Duplicate  *entities.Broker `json:"duplicate"`
+	Similarity float64          `json:"similarity"`
+}
+
+type BrokerUseCase interface {
+	ListAliases(ctx context.Context) ([]*entities.BrokerAlias, error)
+	CreateAlias(ctx context.Context, brokerID uuid.UUID, alias string) (*entities.BrokerAlias, error)
+	DeleteAlias(ctx context.Context, id uuid.UUID) error
+	SuggestDuplicates(ctx context.Context, minSimilarity float64) ([]BrokerDuplicate, error)
+	MergeBrokers(ctx context.Context, survivorID, mergedID uuid.UUID, reason string) (*entities.BrokerMerge, error)
+	ListMerges(ctx context.Context, limit int) ([]*entities.BrokerMerge, error)
+}
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+)
+
+// SetQualityRules makes runs and backfills check each stock against the rules once it is normalized. Stocks
+// breaking a warn rule are kept, while those breaking a quarantine or reject rule are dead-lettered or dropped
+// and count as failed. The summary of each run is stored in summaryRepo; a dry run reports it instead.
+func (uc *StockIngestionUseCase) SetQualityRules(rules *entities.QualityRuleSet, summaryRepo repositories.QualitySummaryRepository) {
+	uc.qualityRules = rules
+	uc.qualitySummaries = summaryRepo
+}
+
+// startQuality opens the data-quality summary of a run, or returns nil when no rules are set
+func (uc *StockIngestionUseCase) startQuality(batchID string) *entities.QualitySummary {
+	if uc.qualityRules == nil {
+		return nil
+	}
+	return entities.NewQualitySummary(batchID, uc.source.Name())
+}
+
+// checkQuality records the rules the stock breaks in the summary and returns the worst violation,
+// or nil when the stock breaks none or no rules are set
+func (uc *StockIngestionUseCase) checkQuality(summary *entities.QualitySummary, stock *entities.Stock, now time.Time) *entities.QualityViolation {
+	if summary == nil {
+		return nil
+	}
+
+	violations := uc.qualityRules.Check(stock, now)
+	worst := summary.Record(stock.Ticker, violations)
+	for i := range violations {
+		if violations[i].Severity == worst {
+			return &violations[i]
+		}
+	}
+	return nil
+}
+
+// recordQuality stores the data-quality summary of a run, logging the rules broken; losing it only costs a warning
+func (uc *StockIngestionUseCase) recordQuality(ctx context.Context, summary *entities.QualitySummary) {
+	if summary == nil || summary.Checked == 0 || uc.qualitySummaries == nil {
+		return
+	}
+
+	if summary.Passed < summary.Checked {
+		uc.logger.Warn("Upstream records broke data-quality rules", "batchID", summary.BatchID,
+			"warned", summary.Warned, "quarantined", summary.Quarantined, "rejected", summary.Rejected,
+			"rules", strings.Join(summary.BrokenRules(), ", "))
+	}
+
+	// The run context may already be cancelled, but the summary of a failed run still tells what it saw
+	if err := uc.qualitySummaries.Save(context.WithoutCancel(ctx), summary); err != nil {
+		uc.logger.Warn("Failed to record data-quality summary", "batchID", summary.BatchID, "error", err)
+	}
+}
@@ -0,0 +1,241 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/valueObjects"
+	"stock-tracker/internal/infrastructure/clients"
+	"stock-tracker/pkg/logger"
+)
+
+// resubmittedSource marks stocks stored by an admin from a dead letter quarantined before its upstream
+// source was recorded
+const resubmittedSource = "dead_letter"
+
+type DeadLetterReviewUseCase struct {
+	deadLetterRepo    repositories.DeadLetterRepository
+	stockRepo         repositories.StockRepository
+	brokerRepo        repositories.BrokerRepository
+	normalizationRepo repositories.NormalizationRepository
+	qualityRules      *entities.QualityRuleSet
+	logger            logger.Logger
+}
+
+func NewDeadLetterReviewUseCase(
+	deadLetterRepo repositories.DeadLetterRepository,
+	stockRepo repositories.StockRepository,
+	brokerRepo repositories.BrokerRepository,
+	normalizationRepo repositories.NormalizationRepository,
+	qualityRules *entities.QualityRuleSet,
+	logger logger.Logger,
+) DeadLetterUseCase {
+	return &DeadLetterReviewUseCase{
+		deadLetterRepo:    deadLetterRepo,
+		stockRepo:         stockRepo,
+		brokerRepo:        brokerRepo,
+		normalizationRepo: normalizationRepo,
+		qualityRules:      qualityRules,
+		logger:            logger,
+	}
+}
+
+// ListDeadLetters returns quarantined records from newest to oldest with pagination
+func (uc *DeadLetterReviewUseCase) ListDeadLetters(ctx context.Context, status entities.DeadLetterStatus, limit, offset int) ([]*entities.DeadLetterRecord, *valueObjects.Pagination, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	records, total, err := uc.deadLetterRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		uc.logger.Error("Failed to list dead letter records", "error", err)
+		return nil, nil, fmt.Errorf("failed to retrieve dead letter records: %w", err)
+	}
+
+	pagination := &valueObjects.Pagination{
+		Page:       (offset / limit) + 1,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: (total + limit - 1) / limit,
+	}
+	pagination.HasNext = pagination.Page < pagination.TotalPages
+	pagination.HasPrev = pagination.Page > 1
+
+	return records, pagination, nil
+}
+
+// GetDeadLetter returns a single quarantined record
+func (uc *DeadLetterReviewUseCase) GetDeadLetter(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error) {
+	record, err := uc.deadLetterRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve dead letter record %s: %w", id, err)
+	}
+
+	return record, nil
+}
+
+// FixDeadLetter replaces the payload of a pending record; it is only validated on re-submission
+func (uc *DeadLetterReviewUseCase) FixDeadLetter(ctx context.Context, id uuid.UUID, payload json.RawMessage) (*entities.DeadLetterRecord, error) {
+	record, err := uc.getPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var item clients.StockAPIItem
+	if err := json.Unmarshal(payload, &item); err != nil {
+		return nil, fmt.Errorf("%w: payload is not a stock item: %v", ErrDeadLetterInvalid, err)
+	}
+
+	record.Fix(payload)
+	if err := uc.save(ctx, record); err != nil {
+		uc.logger.Error("Failed to fix dead letter record", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to fix dead letter record %s: %w", id, err)
+	}
+
+	uc.logger.Info("Fixed dead letter record", "id", id)
+	return record, nil
+}
+
+// ResubmitDeadLetter runs the record through conversion, validation and the data-quality rules again and
+// stores the stock under the source it came from if it now passes. Storing the stock is idempotent, so if
+// resolving the record fails afterwards, re-submitting it again only finds the stock already stored.
+func (uc *DeadLetterReviewUseCase) ResubmitDeadLetter(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error) {
+	record, err := uc.getPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stock, reason := decodeDeadLetter(record.Payload)
+	if reason == nil {
+		stock.Source = record.Source
+		if stock.Source == "" {
+			stock.Source = resubmittedSource
+		}
+
+		vocabulary, err := loadVocabulary(ctx, uc.normalizationRepo)
+		if err != nil {
+			uc.logger.Error("Failed to load normalization rules", "id", id, "error", err)
+			return nil, err
+		}
+		vocabulary.Normalize(stock)
+		reason = uc.checkQuality(id, stock)
+	}
+	if reason != nil {
+		record.RecordAttempt(reason.Error())
+		if err := uc.save(ctx, record); err != nil {
+			uc.logger.Error("Failed to record dead letter attempt", "id", id, "error", err)
+			return nil, fmt.Errorf("failed to update dead letter record %s: %w", id, err)
+		}
+		return record, fmt.Errorf("%w: %v", ErrDeadLetterInvalid, reason)
+	}
+
+	broker, err := resolveBroker(ctx, uc.brokerRepo, stock.Brokerage)
+	if err != nil {
+		uc.logger.Error("Failed to resolve broker for dead letter record", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to resolve broker: %w", err)
+	}
+	stock.BrokerID = broker.ID
+
+	result, err := uc.stockRepo.BulkUpsert(ctx, []*entities.Stock{stock})
+	if err == nil && len(result.Failures) > 0 {
+		err = result.Failures[0].Err
+	}
+	if err != nil {
+		uc.logger.Error("Failed to store re-submitted stock", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to store re-submitted stock: %w", err)
+	}
+
+	record.MarkResubmitted()
+	if err := uc.save(ctx, record); err != nil {
+		uc.logger.Error("Failed to resolve dead letter record", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to update dead letter record %s: %w", id, err)
+	}
+
+	uc.logger.Info("Re-submitted dead letter record", "id", id, "ticker", stock.Ticker)
+	return record, nil
+}
+
+// DiscardDeadLetter resolves a pending record without storing it
+func (uc *DeadLetterReviewUseCase) DiscardDeadLetter(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error) {
+	record, err := uc.getPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	record.Discard()
+	if err := uc.save(ctx, record); err != nil {
+		uc.logger.Error("Failed to discard dead letter record", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to discard dead letter record %s: %w", id, err)
+	}
+
+	uc.logger.Info("Discarded dead letter record", "id", id)
+	return record, nil
+}
+
+func (uc *DeadLetterReviewUseCase) getPending(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error) {
+	record, err := uc.GetDeadLetter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !record.IsPending() {
+		return nil, fmt.Errorf("%w: %s", ErrDeadLetterResolved, record.Status)
+	}
+	return record, nil
+}
+
+// save persists a change to a pending record, failing with ErrDeadLetterResolved if a concurrent request
+// resolved it first
+func (uc *DeadLetterReviewUseCase) save(ctx context.Context, record *entities.DeadLetterRecord) error {
+	err := uc.deadLetterRepo.Update(ctx, record)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("%w: by a concurrent request", ErrDeadLetterResolved)
+	}
+	return err
+}
+
+// checkQuality returns the first quarantine or reject rule the stock breaks, or nil when it breaks none
+// or no rules are set
+func (uc *DeadLetterReviewUseCase) checkQuality(id uuid.UUID, stock *entities.Stock) error {
+	if uc.qualityRules == nil {
+		return nil
+	}
+
+	for _, violation := range uc.qualityRules.Check(stock, time.Now()) {
+		if violation.Severity != entities.QualitySeverityWarn {
+			return violation
+		}
+		uc.logger.Warn("Re-submitted stock broke a data-quality rule", "id", id, "rule", violation.Rule, "reason", violation.Reason)
+	}
+	return nil
+}
+
+// decodeDeadLetter runs a quarantined payload through the same conversion and validation as ingestion
+func decodeDeadLetter(payload json.RawMessage) (*entities.Stock, error) {
+	var item clients.StockAPIItem
+	if err := json.Unmarshal(payload, &item); err != nil {
+		return nil, fmt.Errorf("payload is not a stock item: %w", err)
+	}
+
+	stock, err := clients.ConvertAPIItem(item)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateStock(stock); err != nil {
+		return nil, err
+	}
+
+	return stock, nil
+}
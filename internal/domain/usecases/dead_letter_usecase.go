@@ -0,0 +1,26 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/valueObjects"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrDeadLetterResolved is returned when acting on a record that was already re-submitted or discarded
+	ErrDeadLetterResolved = errors.New("dead letter record already resolved")
+	// ErrDeadLetterInvalid is returned when a re-submitted record still fails conversion or validation
+	ErrDeadLetterInvalid = errors.New("dead letter record is still invalid")
+)
+
+type DeadLetterUseCase interface {
+	ListDeadLetters(ctx context.Context, status entities.DeadLetterStatus, limit, offset int) ([]*entities.DeadLetterRecord, *valueObjects.Pagination, error)
+	GetDeadLetter(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error)
+	FixDeadLetter(ctx context.Context, id uuid.UUID, payload json.RawMessage) (*entities.DeadLetterRecord, error)
+	ResubmitDeadLetter(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error)
+	DiscardDeadLetter(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error)
+}
@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+// EventHandler reacts to a domain event. Delivery is at least once: an event is handed out again after a
+// failure or a crash mid-delivery, to every subscriber, so handlers must tolerate seeing it twice.
+type EventHandler func(ctx context.Context, event *entities.DomainEvent) error
+
+type eventSubscriber struct {
+	name    string
+	types   map[entities.DomainEventType]bool
+	handler EventHandler
+}
+
+func (s *eventSubscriber) wants(eventType entities.DomainEventType) bool {
+	return len(s.types) == 0 || s.types[eventType]
+}
+
+const (
+	defaultOutboxBatchSize   = 100
+	defaultOutboxMaxAttempts = 10
+	// outboxClaim is how long a claimed event stays hidden from other dispatchers while it is delivered
+	outboxClaim = time.Minute
+	// outboxBackoff is the wait before the second attempt, doubled after each failure up to outboxMaxBackoff
+	outboxBackoff    = 5 * time.Second
+	outboxMaxBackoff = time.Hour
+)
+
+// EventDispatcher delivers the domain events in the outbox to in-process subscribers. An event is marked
+// delivered once every subscriber interested in it has handled it; if one fails, the event is retried with
+// exponential backoff until it runs out of attempts and is marked failed. Dispatchers in several replicas
+// can share the outbox, as each claim hides its events from the others.
+type EventDispatcher struct {
+	outboxRepo  repositories.OutboxRepository
+	subscribers []*eventSubscriber
+	batchSize   int
+	maxAttempts int
+	logger      logger.Logger
+}
+
+// NewEventDispatcher creates a dispatcher giving each event up to maxAttempts deliveries, or the default
+// number when maxAttempts is not positive
+func NewEventDispatcher(outboxRepo repositories.OutboxRepository, maxAttempts int, logger logger.Logger) *EventDispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultOutboxMaxAttempts
+	}
+	return &EventDispatcher{
+		outboxRepo:  outboxRepo,
+		batchSize:   defaultOutboxBatchSize,
+		maxAttempts: maxAttempts,
+		logger:      logger,
+	}
+}
+
+// Subscribe registers handler under name for events of the given types, or of every type when none is given.
+// Subscribers are registered before the dispatcher runs.
+func (d *EventDispatcher) Subscribe(name string, handler EventHandler, types ...entities.DomainEventType) {
+	subscriber := &eventSubscriber{name: name, handler: handler, types: make(map[entities.DomainEventType]bool)}
+	for _, eventType := range types {
+		subscriber.types[eventType] = true
+	}
+	d.subscribers = append(d.subscribers, subscriber)
+}
+
+// Run dispatches pending events until ctx is done, draining the outbox and then polling it every interval
+func (d *EventDispatcher) Run(ctx context.Context, interval time.Duration) {
+	d.logger.Info("Dispatching domain events", "subscribers", len(d.subscribers), "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			claimed, err := d.DispatchPending(ctx)
+			if err != nil && ctx.Err() == nil {
+				d.logger.Warn("Failed to dispatch domain events", "error", err)
+			}
+			if err != nil || claimed < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending claims one batch of due events and delivers it, returning how many events were claimed.
+// Events left undelivered when ctx is done are claimed again once their claim expires.
+func (d *EventDispatcher) DispatchPending(ctx context.Context) (int, error) {
+	events, err := d.outboxRepo.Claim(ctx, d.batchSize, outboxClaim)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim domain events: %w", err)
+	}
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return len(events), err
+		}
+		if err := d.dispatch(ctx, event); err != nil {
+			return len(events), err
+		}
+	}
+	return len(events), nil
+}
+
+// dispatch delivers event to its subscribers and records the outcome in the outbox. The outcome is
+// recorded even if ctx was cancelled mid-delivery, so the event is retried on schedule.
+func (d *EventDispatcher) dispatch(ctx context.Context, event *entities.OutboxEvent) error {
+	var failures []string
+	for _, subscriber := range d.subscribers {
+		if !subscriber.wants(event.Type) {
+			continue
+		}
+		if err := d.deliver(ctx, subscriber, &event.DomainEvent); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", subscriber.name, err))
+		}
+	}
+
+	recordCtx := context.WithoutCancel(ctx)
+	if len(failures) == 0 {
+		return d.outboxRepo.MarkDelivered(recordCtx, event.ID)
+	}
+
+	lastError := strings.Join(failures, "; ")
+	if event.Attempts >= d.maxAttempts {
+		d.logger.Error("Gave up delivering domain event", "id", event.ID, "type", event.Type, "attempts", event.Attempts, "error", lastError)
+		return d.outboxRepo.MarkFailed(recordCtx, event.ID, lastError)
+	}
+
+	retryAt := time.Now().Add(outboxRetryDelay(event.Attempts))
+	d.logger.Warn("Failed to deliver domain event", "id", event.ID, "type", event.Type, "attempts", event.Attempts, "retryAt", retryAt, "error", lastError)
+	return d.outboxRepo.Reschedule(recordCtx, event.ID, lastError, retryAt)
+}
+
+// deliver hands event to one subscriber, turning a panic into an error so it cannot take the dispatcher down
+func (d *EventDispatcher) deliver(ctx context.Context, subscriber *eventSubscriber, event *entities.DomainEvent) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("subscriber panicked: %v", recovered)
+		}
+	}()
+
+	return subscriber.handler(ctx, event)
+}
+
+// outboxRetryDelay is the wait after a failed attempt, doubling from outboxBackoff up to outboxMaxBackoff
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxMaxBackoff)
+}
@@ -0,0 +1,212 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/infrastructure/clients"
+)
+
+// runMode adjusts what a pipeline run reads and writes; the zero value is a regular run
+type runMode struct {
+	// window restricts a backfill to the events dated within it
+	window *eventWindow
+	// report collects what a dry run would change instead of writing it
+	report *DryRunReport
+	// fence is checked before each page is written when the run holds a lease
+	fence Fence
+	// schema profiles the pages of the run when drift detection is enabled
+	schema *schemaWatch
+	// quality summarises the data-quality rules broken by the stocks of the run when rules are set
+	quality *entities.QualitySummary
+}
+
+// eventWindow is an inclusive range of event times
+type eventWindow struct {
+	from time.Time
+	to   time.Time
+}
+
+func (w *eventWindow) contains(eventTime time.Time) bool {
+	return !eventTime.Before(w.from) && !eventTime.After(w.to)
+}
+
+// DryRunReport summarises what an ingestion run would change
+type DryRunReport struct {
+	Source     string          `json:"source"`
+	Pages      int             `json:"pages"`
+	New        int             `json:"new"`
+	Unchanged  int             `json:"unchanged"`
+	Changed    []StockDiff     `json:"changed"`
+	Invalid    []InvalidRecord `json:"invalid"`
+	NewBrokers []string        `json:"new_brokers"`
+	// Quality summarises the data-quality rules the stocks would break, when rules are set
+	Quality *entities.QualitySummary `json:"quality,omitempty"`
+
+	mu sync.Mutex
+}
+
+// StockDiff describes a stored event that a run would correct
+type StockDiff struct {
+	Ticker    string    `json:"ticker"`
+	EventTime time.Time `json:"event_time"`
+	Fields    []string  `json:"fields"`
+}
+
+// InvalidRecord describes an upstream record that a run would quarantine
+type InvalidRecord struct {
+	Ticker string `json:"ticker"`
+	Reason string `json:"reason"`
+}
+
+func (r *DryRunReport) recordInvalid(item clients.StockAPIItem, reason error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Invalid = append(r.Invalid, InvalidRecord{Ticker: item.Ticker, Reason: reason.Error()})
+}
+
+func (r *DryRunReport) recordNewBrokers(brokers []*entities.Broker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, broker := range brokers {
+		r.NewBrokers = append(r.NewBrokers, broker.Name)
+	}
+}
+
+// Backfill reloads the events dated within [from, to] as a run of its own, correcting the stored copies
+// that upstream has revised since. The source is read from the start under a separate checkpoint, so the
+// regular schedule is unaffected; sources served newest first are only read until events before from.
+func (uc *StockIngestionUseCase) Backfill(ctx context.Context, from, to time.Time) (*entities.IngestionLog, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid backfill window: %s is not before %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	var run *entities.IngestionLog
+
+	err := uc.exclusive(ctx, func(ctx context.Context, fence Fence) error {
+		batchID := uuid.New().String()
+		startTime := time.Now()
+
+		uc.logger.Info("Starting stock backfill", "batchID", batchID, "from", from, "to", to)
+
+		var err error
+		run, err = uc.recordRun(ctx, batchID, func(ctx context.Context, run *entities.IngestionLog) error {
+			var watermark *time.Time
+			if uc.source.NewestFirst() {
+				watermark = &from
+			}
+
+			checkpoint := entities.NewIngestionCheckpoint(batchID, uc.backfillSource(), watermark)
+			if err := uc.checkpointRepo.Save(ctx, checkpoint); err != nil {
+				uc.logger.Error("Failed to open backfill checkpoint", "error", err)
+				return fmt.Errorf("failed to open backfill checkpoint: %w", err)
+			}
+
+			return uc.runPipeline(ctx, run, checkpoint, runMode{window: &eventWindow{from: from, to: to}, fence: fence})
+		})
+		if err != nil {
+			return err
+		}
+
+		uc.logger.Info("Stock backfill completed", "batchID", batchID, "stocks", run.SuccessfulRecords, "duration", time.Since(startTime))
+		return nil
+	})
+
+	return run, err
+}
+
+// DryRun fetches and validates what the next run would ingest and compares it with the stored events.
+// Nothing is written: no stocks, brokers, dead letters, checkpoints or run records.
+func (uc *StockIngestionUseCase) DryRun(ctx context.Context) (*DryRunReport, error) {
+	batchID := uuid.New().String()
+
+	checkpoint, _, err := uc.nextCheckpoint(ctx, batchID)
+	if err != nil {
+		uc.logger.Error("Failed to read ingestion checkpoint", "error", err)
+		return nil, fmt.Errorf("failed to read ingestion checkpoint: %w", err)
+	}
+
+	// The run only carries the pipeline counters and is never recorded
+	run := entities.NewIngestionLog(batchID, 0)
+	report := &DryRunReport{Source: uc.source.Name()}
+
+	mode := runMode{report: report, quality: uc.startQuality(batchID)}
+	if err := uc.runPipeline(ctx, run, checkpoint, mode); err != nil {
+		return nil, err
+	}
+	report.Quality = mode.quality
+
+	return report, nil
+}
+
+// Checkpoints returns the latest checkpoint of the source and of its backfills, leaving out those never opened
+func (uc *StockIngestionUseCase) Checkpoints(ctx context.Context) ([]*entities.IngestionCheckpoint, error) {
+	var checkpoints []*entities.IngestionCheckpoint
+
+	for _, source := range []string{uc.source.Name(), uc.backfillSource()} {
+		checkpoint, err := uc.checkpointRepo.GetLatest(ctx, source)
+		if errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve checkpoint of %s: %w", source, err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+// backfillSource keeps backfill checkpoints apart from those of the regular runs of the source
+func (uc *StockIngestionUseCase) backfillSource() string {
+	return uc.source.Name() + ":backfill"
+}
+
+// diffPage classifies the stocks of a page as new, unchanged or correcting a stored event
+func (uc *StockIngestionUseCase) diffPage(ctx context.Context, stocks []*entities.Stock, report *DryRunReport) error {
+	stored := make(map[string]*entities.Stock)
+	if len(stocks) > 0 {
+		tickers, from, to := eventSpan(stocks)
+		events, err := uc.stockRepo.GetNearbyEvents(ctx, tickers, from, to)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			stored[eventKey(event)] = event
+		}
+	}
+
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	report.Pages++
+	for _, stock := range stocks {
+		previous, exists := stored[eventKey(stock)]
+		switch {
+		case !exists:
+			report.New++
+		case stock.HasRevisedFields(previous):
+			report.Changed = append(report.Changed, StockDiff{
+				Ticker:    stock.Ticker,
+				EventTime: stock.EventTime,
+				Fields:    stock.RevisedFields(previous),
+			})
+		default:
+			report.Unchanged++
+		}
+	}
+
+	return nil
+}
+
+// eventKey identifies an event the way the stocks table does, by ticker and event time
+func eventKey(stock *entities.Stock) string {
+	return stock.Ticker + "@" + stock.EventTime.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,366 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/infrastructure/clients"
+)
+
+// ingestionPage is the unit of work flowing through the pipeline. Each stage fills in
+// its part and the persist stage commits the page as a whole before checkpointing it.
+type ingestionPage struct {
+	number    int
+	items     []clients.StockAPIItem
+	rawItems  []json.RawMessage
+	fetchedAt time.Time
+	stocks    []*entities.Stock
+	nextPage  string
+	caughtUp  bool
+}
+
+// runTally serialises updates to the run counters coming from concurrent stages and batches
+type runTally struct {
+	mu  sync.Mutex
+	run *entities.IngestionLog
+}
+
+// rejected counts records that never reached the stocks table
+func (t *runTally) rejected(count int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.run.RecordBatch(0, count)
+}
+
+// persisted counts the outcome of a batch written to the stocks table
+func (t *runTally) persisted(result *repositories.UpsertResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.run.RecordPersisted(result.Inserted, result.Updated, result.Duplicates, result.Failed)
+}
+
+// runPipeline fetches, converts, enriches and persists pages as concurrent stages.
+// Stages are connected by channels of pipelineBuffer pages, so a slow stage applies
+// backpressure upstream, and the first stage to fail cancels all the others. A failed
+// fetch is the exception: pages already fetched are still committed before it is reported.
+// Runs that write profile the upstream schema and summarise data quality, both recorded however the run ends.
+func (uc *StockIngestionUseCase) runPipeline(ctx context.Context, run *entities.IngestionLog, checkpoint *entities.IngestionCheckpoint, mode runMode) error {
+	vocabulary, err := loadVocabulary(ctx, uc.normalization)
+	if err != nil {
+		uc.logger.Error("Failed to load normalization rules", "error", err)
+		return err
+	}
+
+	if mode.report == nil {
+		if mode.schema, err = uc.watchSchema(ctx, run.BatchID); err != nil {
+			uc.logger.Error("Failed to load schema baseline", "error", err)
+			return fmt.Errorf("failed to load schema baseline: %w", err)
+		}
+		mode.quality = uc.startQuality(run.BatchID)
+		// A dry run reports its quality summary instead of storing it
+		defer uc.recordQuality(ctx, mode.quality)
+	}
+
+	err = uc.streamPages(ctx, run, checkpoint, mode, vocabulary)
+	uc.recordSchema(ctx, mode.schema, err)
+	return err
+}
+
+// streamPages runs the stages of runPipeline and waits for all of them to finish
+func (uc *StockIngestionUseCase) streamPages(ctx context.Context, run *entities.IngestionLog, checkpoint *entities.IngestionCheckpoint, mode runMode, vocabulary *entities.Vocabulary) error {
+	eg, ctx := errgroup.WithContext(ctx)
+
+	fetched := make(chan *ingestionPage, uc.pipelineBuffer)
+	converted := make(chan *ingestionPage, uc.pipelineBuffer)
+	enriched := make(chan *ingestionPage, uc.pipelineBuffer)
+
+	// Closed by the convert stage once it reaches the watermark, so fetching stops early
+	caughtUp := make(chan struct{})
+
+	tally := &runTally{run: run}
+
+	var fetchErr error
+	eg.Go(func() error {
+		defer close(fetched)
+		fetchErr = uc.fetchPages(ctx, checkpoint, caughtUp, fetched)
+		if ctx.Err() != nil {
+			return fetchErr
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		defer close(converted)
+		return uc.convertPages(ctx, run.BatchID, checkpoint, mode, vocabulary, caughtUp, fetched, converted, tally)
+	})
+	eg.Go(func() error {
+		defer close(enriched)
+		return uc.enrichPages(ctx, mode, converted, enriched, tally)
+	})
+	eg.Go(func() error {
+		return uc.persistPages(ctx, run.BatchID, checkpoint, mode, enriched, tally)
+	})
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// fetchPages follows the source cursor from the checkpoint until the last page or until caughtUp is closed
+func (uc *StockIngestionUseCase) fetchPages(ctx context.Context, checkpoint *entities.IngestionCheckpoint, caughtUp <-chan struct{}, out chan<- *ingestionPage) error {
+	cursor := checkpoint.NextPage
+
+	for number := checkpoint.PagesFetched + 1; ; number++ {
+		// Pages past the watermark hold nothing new, so none is fetched once it is reached
+		select {
+		case <-caughtUp:
+			return nil
+		default:
+		}
+
+		response, err := uc.source.FetchRawPage(ctx, cursor)
+		if err != nil {
+			uc.logger.Error("Failed to fetch stocks from source", "page", number, "error", err)
+			return fmt.Errorf("failed to fetch stocks: page %d: %w", number, err)
+		}
+
+		page := &ingestionPage{
+			number:    number,
+			items:     response.Items,
+			rawItems:  response.RawItems,
+			fetchedAt: time.Now(),
+			nextPage:  response.NextPage,
+		}
+		select {
+		case out <- page:
+		case <-caughtUp:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if response.NextPage == "" {
+			return nil
+		}
+		cursor = response.NextPage
+	}
+}
+
+// convertPages turns raw items into stocks. Pages are served newest first, so the first event
+// older than the watermark means everything after it has been stored by a previous run.
+// Items that fail conversion or validation are quarantined as dead letters, and events outside
+// the window of a backfill are skipped. The ratings and action of stocks are normalized before
+// the data-quality rules are checked, and each stock kept carries the raw item it was parsed
+// from to be stored alongside it.
+func (uc *StockIngestionUseCase) convertPages(ctx context.Context, batchID string, checkpoint *entities.IngestionCheckpoint, mode runMode, vocabulary *entities.Vocabulary, caughtUp chan<- struct{}, in <-chan *ingestionPage, out chan<- *ingestionPage, tally *runTally) error {
+	reject := func(item clients.StockAPIItem, reason error) {
+		if mode.report != nil {
+			mode.report.recordInvalid(item, reason)
+			return
+		}
+		uc.quarantine(ctx, batchID, item, reason)
+	}
+	drop := func(item clients.StockAPIItem, reason error) {
+		if mode.report != nil {
+			mode.report.recordInvalid(item, reason)
+			return
+		}
+		uc.logger.Warn("Rejected upstream record", "ticker", item.Ticker, "reason", reason)
+	}
+
+	for page := range in {
+		if err := mode.schema.observe(page.items, page.rawItems); err != nil {
+			uc.logger.Error("Stopped ingestion on upstream schema drift", "page", page.number, "error", err)
+			return err
+		}
+
+		page.stocks = make([]*entities.Stock, 0, len(page.items))
+		failed := 0
+
+		for i, item := range page.items {
+			stock, err := clients.ConvertAPIItem(item)
+			if err != nil {
+				reject(item, err)
+				failed++
+				continue
+			}
+			stock.Source = uc.source.Name()
+			if checkpoint.IsBeforeWatermark(stock.EventTime) {
+				page.caughtUp = true
+				break
+			}
+			if mode.window != nil && !mode.window.contains(stock.EventTime) {
+				continue
+			}
+			vocabulary.Normalize(stock)
+			if violation := uc.checkQuality(mode.quality, stock, page.fetchedAt); violation != nil {
+				switch violation.Severity {
+				case entities.QualitySeverityQuarantine:
+					reject(item, violation)
+					failed++
+					continue
+				case entities.QualitySeverityReject:
+					drop(item, violation)
+					failed++
+					continue
+				}
+			}
+			if err := validateStock(stock); err != nil {
+				reject(item, err)
+				failed++
+				continue
+			}
+			if payload, err := rawItem(page, i); err == nil {
+				stock.Payload = entities.NewStockPayload(batchID, stock.Source, page.fetchedAt, payload)
+			}
+			page.stocks = append(page.stocks, stock)
+		}
+
+		if failed > 0 {
+			tally.rejected(failed)
+		}
+
+		// Fetching stops as soon as the watermark is seen, before the page is handed on
+		if page.caughtUp {
+			close(caughtUp)
+		}
+
+		select {
+		case out <- page:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if page.caughtUp {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// rawItem returns item i of a page as received, or encoded from its decoded form when the source kept no raw items
+func rawItem(page *ingestionPage, i int) (json.RawMessage, error) {
+	if i < len(page.rawItems) {
+		return page.rawItems[i], nil
+	}
+	return json.Marshal(page.items[i])
+}
+
+// quarantine stores an item that cannot be ingested as a dead letter; losing it only costs a warning
+func (uc *StockIngestionUseCase) quarantine(ctx context.Context, batchID string, item clients.StockAPIItem, reason error) {
+	payload, err := json.Marshal(item)
+	if err == nil {
+		err = uc.deadLetterRepo.Create(ctx, entities.NewDeadLetterRecord(batchID, uc.source.Name(), payload, reason.Error()))
+	}
+	if err != nil {
+		uc.logger.Warn("Failed to quarantine upstream record", "ticker", item.Ticker, "error", err)
+		return
+	}
+
+	uc.logger.Warn("Quarantined upstream record", "ticker", item.Ticker, "reason", reason)
+}
+
+// validateStock applies the entity validation rules to a converted stock
+func validateStock(stock *entities.Stock) error {
+	if err := stock.Validate(); err != nil {
+		return fmt.Errorf("invalid stock: %w", err)
+	}
+	return nil
+}
+
+// enrichPages assigns broker IDs, loading the known brokers once per run. A dry run
+// reports the brokers it would create instead of creating them.
+func (uc *StockIngestionUseCase) enrichPages(ctx context.Context, mode runMode, in <-chan *ingestionPage, out chan<- *ingestionPage, tally *runTally) error {
+	var brokerMap map[string]*entities.Broker
+
+	for page := range in {
+		if len(page.stocks) > 0 {
+			if brokerMap == nil {
+				var err error
+				if brokerMap, err = uc.loadBrokers(ctx); err != nil {
+					uc.logger.Error("Failed to enrich stocks with brokers", "error", err)
+					tally.rejected(len(page.stocks))
+					return fmt.Errorf("failed to enrich stocks with brokers: %w", err)
+				}
+			}
+			if mode.report != nil {
+				mode.report.recordNewBrokers(assignBrokers(page.stocks, brokerMap))
+			} else {
+				uc.enrichWithBrokerInfo(ctx, page.stocks, brokerMap)
+			}
+		}
+
+		select {
+		case out <- page:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// persistPages stores each page and only then advances the checkpoint past it,
+// so a run that stops midway resumes from the last committed page. A dry run compares
+// each page with the stored events instead and leaves the checkpoint untouched.
+func (uc *StockIngestionUseCase) persistPages(ctx context.Context, batchID string, checkpoint *entities.IngestionCheckpoint, mode runMode, in <-chan *ingestionPage, tally *runTally) error {
+	for page := range in {
+		if mode.report != nil {
+			if err := uc.diffPage(ctx, page.stocks, mode.report); err != nil {
+				uc.logger.Error("Failed to compare stocks with stored events", "page", page.number, "error", err)
+				return fmt.Errorf("failed to compare stocks with stored events: %w", err)
+			}
+			continue
+		}
+
+		if mode.fence != nil {
+			if err := mode.fence.Check(ctx); err != nil {
+				uc.logger.Error("Stopped ingestion without the job lease", "page", page.number, "error", err)
+				return fmt.Errorf("failed to confirm job lease: %w", err)
+			}
+		}
+
+		if len(page.stocks) > 0 {
+			stocks, superseded, err := uc.deduplicate(ctx, page.stocks)
+			if err != nil {
+				uc.logger.Error("Failed to de-duplicate stocks across sources", "error", err)
+				return fmt.Errorf("failed to de-duplicate stocks: %w", err)
+			}
+			if covered := len(page.stocks) - len(stocks); covered > 0 {
+				tally.persisted(&repositories.UpsertResult{Duplicates: covered})
+			}
+
+			//Process Stocks in batches using worker pool
+			failures, err := uc.processStocksInBatches(ctx, stocks, tally)
+			if err != nil {
+				uc.logger.Error("Error during stock ingestion", "error", err)
+				return fmt.Errorf("error during stock ingestion: %w", err)
+			}
+
+			uc.removeSuperseded(ctx, superseded, failures)
+		}
+
+		checkpoint.BatchID = batchID
+		checkpoint.Advance(page.nextPage, len(page.stocks))
+		if page.nextPage == "" || page.caughtUp {
+			checkpoint.Complete()
+		}
+
+		if err := uc.checkpointRepo.Save(ctx, checkpoint); err != nil {
+			uc.logger.Error("Failed to save ingestion checkpoint", "page", page.number, "error", err)
+			return fmt.Errorf("failed to save ingestion checkpoint: %w", err)
+		}
+
+		uc.logger.Info("Committed ingestion checkpoint", "batchID", batchID, "page", page.number, "stocks", len(page.stocks))
+	}
+
+	return nil
+}
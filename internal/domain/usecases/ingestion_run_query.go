@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/valueObjects"
+	"stock-tracker/pkg/logger"
+)
+
+type IngestionRunQueryUseCase struct {
+	ingestionLogRepo repositories.IngestionLogRepository
+	qualityRepo      repositories.QualitySummaryRepository
+	logger           logger.Logger
+}
+
+func NewIngestionRunQueryUseCase(
+	ingestionLogRepo repositories.IngestionLogRepository,
+	qualityRepo repositories.QualitySummaryRepository,
+	logger logger.Logger,
+) IngestionRunUseCase {
+	return &IngestionRunQueryUseCase{
+		ingestionLogRepo: ingestionLogRepo,
+		qualityRepo:      qualityRepo,
+		logger:           logger,
+	}
+}
+
+// ListRuns returns ingestion runs from newest to oldest with pagination
+func (uc *IngestionRunQueryUseCase) ListRuns(ctx context.Context, limit, offset int) ([]*entities.IngestionLog, *valueObjects.Pagination, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	runs, total, err := uc.ingestionLogRepo.List(ctx, limit, offset)
+	if err != nil {
+		uc.logger.Error("Failed to list ingestion runs", "error", err)
+		return nil, nil, fmt.Errorf("failed to retrieve ingestion runs: %w", err)
+	}
+
+	pagination := &valueObjects.Pagination{
+		Page:       (offset / limit) + 1,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: (total + limit - 1) / limit,
+	}
+	pagination.HasNext = pagination.Page < pagination.TotalPages
+	pagination.HasPrev = pagination.Page > 1
+
+	return runs, pagination, nil
+}
+
+// GetRun returns a single ingestion run by its batch ID
+func (uc *IngestionRunQueryUseCase) GetRun(ctx context.Context, batchID string) (*entities.IngestionLog, error) {
+	run, err := uc.ingestionLogRepo.GetByBatchID(ctx, batchID)
+	if err != nil {
+		uc.logger.Error("Failed to get ingestion run", "batch_id", batchID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve ingestion run %s: %w", batchID, err)
+	}
+
+	return run, nil
+}
+
+// GetLastSuccessfulRun returns the most recent run that completed successfully
+func (uc *IngestionRunQueryUseCase) GetLastSuccessfulRun(ctx context.Context) (*entities.IngestionLog, error) {
+	run, err := uc.ingestionLogRepo.GetLastSuccessful(ctx)
+	if err != nil {
+		uc.logger.Error("Failed to get last successful ingestion run", "error", err)
+		return nil, fmt.Errorf("failed to retrieve last successful ingestion run: %w", err)
+	}
+
+	return run, nil
+}
+
+// ListQualitySummaries returns the data-quality summaries of runs from newest to oldest with pagination
+func (uc *IngestionRunQueryUseCase) ListQualitySummaries(ctx context.Context, limit, offset int) ([]*entities.QualitySummary, *valueObjects.Pagination, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	summaries, total, err := uc.qualityRepo.List(ctx, limit, offset)
+	if err != nil {
+		uc.logger.Error("Failed to list quality summaries", "error", err)
+		return nil, nil, fmt.Errorf("failed to retrieve quality summaries: %w", err)
+	}
+
+	pagination := &valueObjects.Pagination{
+		Page:       (offset / limit) + 1,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: (total + limit - 1) / limit,
+	}
+	pagination.HasNext = pagination.Page < pagination.TotalPages
+	pagination.HasPrev = pagination.Page > 1
+
+	return summaries, pagination, nil
+}
+
+// GetQualitySummary returns the data-quality summary of a run by its batch ID
+func (uc *IngestionRunQueryUseCase) GetQualitySummary(ctx context.Context, batchID string) (*entities.QualitySummary, error) {
+	summary, err := uc.qualityRepo.GetByBatchID(ctx, batchID)
+	if err != nil {
+		uc.logger.Error("Failed to get quality summary", "batch_id", batchID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve quality summary of %s: %w", batchID, err)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,15 @@
+package usecases
+
+import (
+	"context"
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/valueObjects"
+)
+
+type IngestionRunUseCase interface {
+	ListRuns(ctx context.Context, limit, offset int) ([]*entities.IngestionLog, *valueObjects.Pagination, error)
+	GetRun(ctx context.Context, batchID string) (*entities.IngestionLog, error)
+	GetLastSuccessfulRun(ctx context.Context) (*entities.IngestionLog, error)
+	ListQualitySummaries(ctx context.Context, limit, offset int) ([]*entities.QualitySummary, *valueObjects.Pagination, error)
+	GetQualitySummary(ctx context.Context, batchID string) (*entities.QualitySummary, error)
+}
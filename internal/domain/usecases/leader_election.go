@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+// LeaderElector runs jobs under leases so that, across all replicas sharing the lease repository, only one
+// instance runs a given job at a time. A holder that dies stops renewing; its lease expires after ttl and
+// the next replica to try takes over.
+type LeaderElector struct {
+	leaseRepo repositories.LeaseRepository
+	holder    string
+	ttl       time.Duration
+	logger    logger.Logger
+}
+
+func NewLeaderElector(leaseRepo repositories.LeaseRepository, holder string, ttl time.Duration, logger logger.Logger) *LeaderElector {
+	return &LeaderElector{
+		leaseRepo: leaseRepo,
+		holder:    holder,
+		ttl:       ttl,
+		logger:    logger,
+	}
+}
+
+// Fence guards the writes of a job against a lost lease. Check renews the lease under its fencing token
+// and fails once it has expired or passed to another holder, so an instance that stalled past its lease
+// stops writing instead of racing the new holder.
+type Fence interface {
+	Check(ctx context.Context) error
+}
+
+// RunExclusive runs job while holding the lease on name, or returns ErrLeaseHeld without running it.
+// The lease is renewed every third of its ttl and released when job returns. If a renewal finds the
+// lease lost, the context passed to job is cancelled and the error returned matches ErrLeaseLost.
+func (e *LeaderElector) RunExclusive(ctx context.Context, name string, job func(ctx context.Context, fence Fence) error) error {
+	requested := time.Now()
+	lease, err := e.leaseRepo.TryAcquire(ctx, name, e.holder, e.ttl)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lease on %s: %w", name, err)
+	}
+
+	e.logger.Info("Acquired job lease", "job", name, "holder", e.holder, "token", lease.Token)
+
+	held := &heldLease{elector: e, lease: lease, deadline: requested.Add(e.ttl)}
+	jobCtx, cancel := context.WithCancelCause(ctx)
+
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		e.keepRenewed(jobCtx, held, cancel)
+	}()
+
+	err = job(jobCtx, held)
+	lost := errors.Is(context.Cause(jobCtx), repositories.ErrLeaseLost)
+	cancel(nil)
+	<-renewed
+
+	// The job context may be cancelled, but the lease should still be handed back promptly
+	if releaseErr := e.leaseRepo.Release(context.WithoutCancel(ctx), lease); releaseErr != nil {
+		e.logger.Warn("Failed to release job lease", "job", name, "error", releaseErr)
+	}
+
+	if err != nil && lost && !errors.Is(err, repositories.ErrLeaseLost) {
+		return fmt.Errorf("%w: %w", repositories.ErrLeaseLost, err)
+	}
+	return err
+}
+
+// keepRenewed renews the lease until ctx is done. A renewal that fails for another reason is retried
+// on the next tick, until the lease would have expired anyway.
+func (e *LeaderElector) keepRenewed(ctx context.Context, held *heldLease, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := held.Check(ctx)
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+
+		if errors.Is(err, repositories.ErrLeaseLost) || held.expired() {
+			e.logger.Error("Lost job lease", "job", held.lease.Name, "error", err)
+			cancel(repositories.ErrLeaseLost)
+			return
+		}
+		e.logger.Warn("Failed to renew job lease", "job", held.lease.Name, "error", err)
+	}
+}
+
+// heldLease serialises renewals of a lease coming from the renewal loop and from fence checks
+type heldLease struct {
+	elector *LeaderElector
+
+	mu    sync.Mutex
+	lease *entities.Lease
+	// deadline is when the lease expires by the local clock, measured conservatively from before each renewal
+	deadline time.Time
+}
+
+// Check renews the lease, failing with ErrLeaseLost once it is no longer held
+func (h *heldLease) Check(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	requested := time.Now()
+	if err := h.elector.leaseRepo.Renew(ctx, h.lease, h.elector.ttl); err != nil {
+		return err
+	}
+	h.deadline = requested.Add(h.elector.ttl)
+	return nil
+}
+
+func (h *heldLease) expired() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return time.Now().After(h.deadline)
+}
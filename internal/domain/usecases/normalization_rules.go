@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+type NormalizationRulesUseCase struct {
+	normalizationRepo repositories.NormalizationRepository
+	logger            logger.Logger
+}
+
+func NewNormalizationRulesUseCase(normalizationRepo repositories.NormalizationRepository, logger logger.Logger) NormalizationUseCase {
+	return &NormalizationRulesUseCase{
+		normalizationRepo: normalizationRepo,
+		logger:            logger,
+	}
+}
+
+// ListRules returns the stored rules of the given kind, or of every kind when kind is empty
+func (uc *NormalizationRulesUseCase) ListRules(ctx context.Context, kind entities.NormalizationKind) ([]*entities.NormalizationRule, error) {
+	rules, err := uc.normalizationRepo.ListRules(ctx, kind)
+	if err != nil {
+		uc.logger.Error("Failed to list normalization rules", "error", err)
+		return nil, fmt.Errorf("failed to retrieve normalization rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// CreateRule stores a rule mapping pattern onto value. It applies to the events ingested from then on;
+// stored events keep their values until Reapply.
+func (uc *NormalizationRulesUseCase) CreateRule(ctx context.Context, kind entities.NormalizationKind, brokerage, pattern, value string) (*entities.NormalizationRule, error) {
+	rule := entities.NewNormalizationRule(kind, brokerage, pattern, value)
+	if err := rule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNormalizationRuleInvalid, err)
+	}
+
+	if err := uc.normalizationRepo.CreateRule(ctx, rule); err != nil {
+		uc.logger.Error("Failed to create normalization rule", "pattern", rule.Pattern, "error", err)
+		return nil, err
+	}
+
+	uc.logger.Info("Created normalization rule", "kind", rule.Kind, "pattern", rule.Pattern, "value", rule.Value)
+	return rule, nil
+}
+
+// UpdateRule changes what a rule matches and the value it maps to
+func (uc *NormalizationRulesUseCase) UpdateRule(ctx context.Context, id uuid.UUID, brokerage, pattern, value string) (*entities.NormalizationRule, error) {
+	rule, err := uc.normalizationRepo.GetRule(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve normalization rule %s: %w", id, err)
+	}
+
+	rule.Change(brokerage, pattern, value)
+	if err := rule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNormalizationRuleInvalid, err)
+	}
+
+	if err := uc.normalizationRepo.UpdateRule(ctx, rule); err != nil {
+		uc.logger.Error("Failed to update normalization rule", "id", id, "error", err)
+		return nil, err
+	}
+
+	uc.logger.Info("Updated normalization rule", "id", id, "pattern", rule.Pattern, "value", rule.Value)
+	return rule, nil
+}
+
+// DeleteRule removes a rule; the raw values it mapped fall back to broader rules or the built-in vocabulary
+func (uc *NormalizationRulesUseCase) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	if err := uc.normalizationRepo.DeleteRule(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete normalization rule %s: %w", id, err)
+	}
+
+	uc.logger.Info("Deleted normalization rule", "id", id)
+	return nil
+}
+
+// ListLabels returns the raw label combinations of the stored stocks, most common first. With unmappedOnly,
+// only those with an action or rating no rule maps are returned, which are the ones worth writing rules for.
+func (uc *NormalizationRulesUseCase) ListLabels(ctx context.Context, unmappedOnly bool) ([]repositories.StockLabels, error) {
+	labels, err := uc.normalizationRepo.ListStockLabels(ctx)
+	if err != nil {
+		uc.logger.Error("Failed to list stock labels", "error", err)
+		return nil, fmt.Errorf("failed to retrieve stock labels: %w", err)
+	}
+
+	if !unmappedOnly {
+		return labels, nil
+	}
+
+	unmapped := make([]repositories.StockLabels, 0, len(labels))
+	for _, label := range labels {
+		if isUnmapped(label) {
+			unmapped = append(unmapped, label)
+		}
+	}
+	return unmapped, nil
+}
+
+// Reapply normalizes the stored stocks again with the current rules, returning how many changed
+func (uc *NormalizationRulesUseCase) Reapply(ctx context.Context) (int, error) {
+	vocabulary, err := loadVocabulary(ctx, uc.normalizationRepo)
+	if err != nil {
+		return 0, err
+	}
+
+	labels, err := uc.normalizationRepo.ListStockLabels(ctx)
+	if err != nil {
+		uc.logger.Error("Failed to list stock labels", "error", err)
+		return 0, fmt.Errorf("failed to retrieve stock labels: %w", err)
+	}
+
+	// Only the combinations whose normalized values change are written
+	var changed []repositories.StockLabels
+	for _, label := range labels {
+		normalized := label
+		normalized.ActionType = vocabulary.ActionType(label.Brokerage, label.Action)
+		normalized.RatingFromTier = vocabulary.RatingTier(label.Brokerage, label.RatingFrom)
+		normalized.RatingToTier = vocabulary.RatingTier(label.Brokerage, label.RatingTo)
+		if normalized != label {
+			changed = append(changed, normalized)
+		}
+	}
+
+	updated, err := uc.normalizationRepo.UpdateStockLabels(ctx, changed)
+	if err != nil {
+		uc.logger.Error("Failed to reapply normalization rules", "error", err)
+		return 0, fmt.Errorf("failed to update stock labels: %w", err)
+	}
+
+	uc.logger.Info("Reapplied normalization rules", "labels", len(changed), "stocks", updated)
+	return updated, nil
+}
+
+// isUnmapped checks if any raw value of the combination has no canonical value
+func isUnmapped(label repositories.StockLabels) bool {
+	return label.ActionType == entities.ActionTypeUnknown ||
+		(label.RatingFrom != "" && label.RatingFromTier == entities.RatingTierUnknown) ||
+		(label.RatingTo != "" && label.RatingToTier == entities.RatingTierUnknown)
+}
+
+// loadVocabulary builds the vocabulary from the stored rules, or the built-in one when there is no repository
+func loadVocabulary(ctx context.Context, normalizationRepo repositories.NormalizationRepository) (*entities.Vocabulary, error) {
+	if normalizationRepo == nil {
+		return entities.DefaultVocabulary(), nil
+	}
+
+	rules, err := normalizationRepo.ListRules(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load normalization rules: %w", err)
+	}
+	return entities.NewVocabulary(rules), nil
+}
@@ -0,0 +1,22 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrNormalizationRuleInvalid is returned when a rule has no pattern or maps it onto an unknown value
+var ErrNormalizationRuleInvalid = errors.New("invalid normalization rule")
+
+type NormalizationUseCase interface {
+	ListRules(ctx context.Context, kind entities.NormalizationKind) ([]*entities.NormalizationRule, error)
+	CreateRule(ctx context.Context, kind entities.NormalizationKind, brokerage, pattern, value string) (*entities.NormalizationRule, error)
+	UpdateRule(ctx context.Context, id uuid.UUID, brokerage, pattern, value string) (*entities.NormalizationRule, error)
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	ListLabels(ctx context.Context, unmappedOnly bool) ([]repositories.StockLabels, error)
+	Reapply(ctx context.Context) (int, error)
+}
@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/infrastructure/clients"
+	"stock-tracker/pkg/logger"
+)
+
+// priceLookback is how many days before an event's trading day a close is searched for. It spans the
+// longest runs of weekends and holidays, so an event always gets the close of the previous session.
+const priceLookback = 7 * 24 * time.Hour
+
+// PriceEnrichmentReport counts what an enrichment run did
+type PriceEnrichmentReport struct {
+	Tickers int `json:"tickers"`
+	Stocks  int `json:"stocks"`
+	Priced  int `json:"priced"`
+	// Missing stocks had no close within the lookback, usually because the provider lacks the ticker
+	Missing int `json:"missing"`
+	// Pending stocks happened on a trading day that has not closed yet; a later run prices them
+	Pending int `json:"pending"`
+	// FailedTickers could not be priced because the provider failed; their stocks are left as they were
+	FailedTickers []string `json:"failed_tickers,omitempty"`
+}
+
+// PriceEnrichmentUseCase fills in the closing price of each stock event's trading day. The trading day is
+// the event's date in the market's time zone; when the market was closed that day, the close of the
+// previous session is used instead.
+type PriceEnrichmentUseCase struct {
+	stockRepo repositories.StockRepository
+	provider  clients.PriceProvider
+	market    *time.Location
+	logger    logger.Logger
+	now       func() time.Time
+}
+
+func NewPriceEnrichmentUseCase(
+	stockRepo repositories.StockRepository,
+	provider clients.PriceProvider,
+	market *time.Location,
+	logger logger.Logger,
+) *PriceEnrichmentUseCase {
+	if market == nil {
+		market = time.UTC
+	}
+	return &PriceEnrichmentUseCase{
+		stockRepo: stockRepo,
+		provider:  provider,
+		market:    market,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Enrich prices the stocks selected by filter, ticker by ticker. A ticker the provider fails on is
+// reported and skipped so the others are still priced.
+func (uc *PriceEnrichmentUseCase) Enrich(ctx context.Context, filter repositories.PricingFilter) (*PriceEnrichmentReport, error) {
+	tickers, err := uc.stockRepo.ListPricingTickers(ctx, filter)
+	if err != nil {
+		uc.logger.Error("Failed to list tickers to price", "error", err)
+		return nil, fmt.Errorf("failed to retrieve tickers to price: %w", err)
+	}
+
+	report := &PriceEnrichmentReport{Tickers: len(tickers)}
+	today := uc.tradingDay(uc.now())
+
+	for _, ticker := range tickers {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		if err := uc.enrichTicker(ctx, ticker, filter, today, report); err != nil {
+			uc.logger.Warn("Failed to price ticker", "ticker", ticker, "error", err)
+			report.FailedTickers = append(report.FailedTickers, ticker)
+		}
+	}
+
+	uc.logger.Info("Price enrichment completed", "provider", uc.provider.Name(), "priced", report.Priced, "missing", report.Missing)
+	return report, nil
+}
+
+func (uc *PriceEnrichmentUseCase) enrichTicker(ctx context.Context, ticker string, filter repositories.PricingFilter, today time.Time, report *PriceEnrichmentReport) error {
+	candidates, err := uc.stockRepo.ListPricingCandidates(ctx, ticker, filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve stocks to price: %w", err)
+	}
+	report.Stocks += len(candidates)
+
+	// Only days that have closed can be priced
+	days := make([]time.Time, len(candidates))
+	var first, last time.Time
+	for i, candidate := range candidates {
+		days[i] = uc.tradingDay(candidate.EventTime)
+		if !days[i].Before(today) {
+			continue
+		}
+		if first.IsZero() || days[i].Before(first) {
+			first = days[i]
+		}
+		if days[i].After(last) {
+			last = days[i]
+		}
+	}
+	if first.IsZero() {
+		report.Pending += len(candidates)
+		return nil
+	}
+
+	closes, err := uc.provider.DailyCloses(ctx, ticker, first.Add(-priceLookback), last)
+	if err != nil {
+		return err
+	}
+	sort.Slice(closes, func(i, j int) bool { return closes[i].Date.Before(closes[j].Date) })
+
+	prices := make([]repositories.ClosePrice, 0, len(candidates))
+	for i, candidate := range candidates {
+		if !days[i].Before(today) {
+			report.Pending++
+			continue
+		}
+
+		session, ok := closeOn(closes, days[i])
+		if !ok {
+			report.Missing++
+			continue
+		}
+		prices = append(prices, repositories.ClosePrice{StockID: candidate.ID, Close: session.Close, Date: session.Date})
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+
+	updated, err := uc.stockRepo.UpdateClosePrices(ctx, prices)
+	if err != nil {
+		return err
+	}
+	report.Priced += updated
+
+	return nil
+}
+
+// tradingDay returns the date of t in the market's time zone, at midnight UTC like DailyClose dates
+func (uc *PriceEnrichmentUseCase) tradingDay(t time.Time) time.Time {
+	local := t.In(uc.market)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// closeOn finds the close of day, or of the last session before it within priceLookback, in closes sorted by date
+func closeOn(closes []clients.DailyClose, day time.Time) (clients.DailyClose, bool) {
+	i := sort.Search(len(closes), func(i int) bool { return closes[i].Date.After(day) })
+	if i == 0 {
+		return clients.DailyClose{}, false
+	}
+
+	session := closes[i-1]
+	if day.Sub(session.Date) > priceLookback {
+		return clients.DailyClose{}, false
+	}
+	return session, true
+}
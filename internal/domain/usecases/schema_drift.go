@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/infrastructure/clients"
+)
+
+// ErrSchemaDrift is returned by runs stopped because the upstream schema drifted from its baseline
+var ErrSchemaDrift = errors.New("upstream schema drifted from baseline")
+
+// errSchemaDriftDisabled is returned by the schema queries of a use case without drift detection
+var errSchemaDriftDisabled = errors.New("schema drift detection is not enabled")
+
+// SchemaDriftPolicy sets how runs react to the upstream schema drifting from its baseline
+type SchemaDriftPolicy struct {
+	// Fail stops a drifting run before it writes the page that crossed a threshold, instead of only flagging it
+	Fail bool
+	// MinRecords is the number of records a run must receive before its profile is compared or becomes a baseline
+	MinRecords int
+	Thresholds entities.DriftThresholds
+}
+
+// SetSchemaDrift makes runs and backfills profile the upstream records they receive and compare the profile with
+// the baseline of the source stored in profileRepo. Every profile is stored with the drift found; the first one of
+// a source with enough records becomes its baseline, and AcceptSchema moves the baseline to the latest one.
+func (uc *StockIngestionUseCase) SetSchemaDrift(profileRepo repositories.SchemaProfileRepository, policy SchemaDriftPolicy) {
+	uc.schemaProfiles = profileRepo
+	uc.schemaPolicy = policy
+}
+
+// schemaWatch profiles the pages of one run and compares the profile with the baseline of the source
+type schemaWatch struct {
+	profile  *entities.SchemaProfile
+	baseline *entities.SchemaProfile
+	policy   SchemaDriftPolicy
+}
+
+// watchSchema starts the profile of a run, or returns nil when drift detection is disabled
+func (uc *StockIngestionUseCase) watchSchema(ctx context.Context, batchID string) (*schemaWatch, error) {
+	if uc.schemaProfiles == nil {
+		return nil, nil
+	}
+
+	baseline, err := uc.schemaProfiles.GetBaseline(ctx, uc.source.Name())
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	return &schemaWatch{
+		profile:  entities.NewSchemaProfile(batchID, uc.source.Name()),
+		baseline: baseline,
+		policy:   uc.schemaPolicy,
+	}, nil
+}
+
+// observe adds a page to the profile. When the policy fails drifting runs, it returns ErrSchemaDrift as soon
+// as the profile has drifted, so the page is never written. A nil watch observes nothing.
+func (w *schemaWatch) observe(items []clients.StockAPIItem, rawItems []json.RawMessage) error {
+	if w == nil {
+		return nil
+	}
+
+	clients.ProfilePage(w.profile, items, rawItems)
+	if !w.policy.Fail {
+		return nil
+	}
+	if drift := w.drift(); len(drift) > 0 {
+		return fmt.Errorf("%w: %s", ErrSchemaDrift, describeDrift(drift))
+	}
+	return nil
+}
+
+// drift compares the profile with the baseline, once the run has received enough records for it to be telling
+func (w *schemaWatch) drift() []entities.SchemaDrift {
+	if w.baseline == nil || w.profile.Records < w.policy.MinRecords {
+		return nil
+	}
+	return w.profile.CompareTo(w.baseline, w.policy.Thresholds)
+}
+
+// recordSchema stores the profile of a run with the drift found, which is also logged. A source without a
+// baseline takes the profile of its first run completed with enough records; losing a profile only costs a warning.
+func (uc *StockIngestionUseCase) recordSchema(ctx context.Context, watch *schemaWatch, runErr error) {
+	if watch == nil || watch.profile.Records == 0 {
+		return
+	}
+
+	profile := watch.profile
+	profile.Drift = watch.drift()
+	if watch.baseline == nil && runErr == nil && profile.Records >= watch.policy.MinRecords {
+		profile.IsBaseline = true
+	}
+
+	if len(profile.Drift) > 0 {
+		uc.logger.Warn("Upstream schema drifted from baseline", "source", profile.Source, "drift", describeDrift(profile.Drift))
+	}
+
+	// The run context may already be cancelled, but the profile of a failed run matters most
+	if err := uc.schemaProfiles.Save(context.WithoutCancel(ctx), profile); err != nil {
+		uc.logger.Warn("Failed to record schema profile", "source", profile.Source, "error", err)
+	}
+}
+
+func describeDrift(drift []entities.SchemaDrift) string {
+	descriptions := make([]string, len(drift))
+	for i, d := range drift {
+		descriptions[i] = d.String()
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// SchemaProfiles returns the latest schema profile of the source and its baseline, either nil if there is none
+func (uc *StockIngestionUseCase) SchemaProfiles(ctx context.Context) (latest, baseline *entities.SchemaProfile, err error) {
+	if uc.schemaProfiles == nil {
+		return nil, nil, errSchemaDriftDisabled
+	}
+
+	source := uc.source.Name()
+	if latest, err = uc.schemaProfiles.GetLatest(ctx, source); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil, fmt.Errorf("failed to get latest schema profile: %w", err)
+	}
+	if baseline, err = uc.schemaProfiles.GetBaseline(ctx, source); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil, fmt.Errorf("failed to get schema baseline: %w", err)
+	}
+
+	return latest, baseline, nil
+}
+
+// AcceptSchema makes the latest schema profile of the source its baseline, once a vendor change has been
+// reviewed, so later runs are compared with the new shape. It returns ErrNotFound if no run was profiled yet.
+func (uc *StockIngestionUseCase) AcceptSchema(ctx context.Context) (*entities.SchemaProfile, error) {
+	if uc.schemaProfiles == nil {
+		return nil, errSchemaDriftDisabled
+	}
+
+	latest, err := uc.schemaProfiles.GetLatest(ctx, uc.source.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest schema profile: %w", err)
+	}
+	if err := uc.schemaProfiles.SetBaseline(ctx, latest.ID); err != nil {
+		return nil, fmt.Errorf("failed to accept schema profile: %w", err)
+	}
+
+	latest.IsBaseline = true
+	uc.logger.Info("Accepted upstream schema baseline", "source", latest.Source, "batchID", latest.BatchID)
+	return latest, nil
+}
@@ -0,0 +1,190 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+)
+
+// SetSourcePriority enables de-duplication across sources. Events from different sources for the same
+// ticker and brokerage, with the same target rating and dated within window of each other, describe a
+// single broker action and only one of them is kept. Brokerages match by the key of their names or
+// aliases, so two vendors spelling the same broker differently still agree. Events from different
+// sources for the same ticker and event time share a row whatever they say, so the same rule decides
+// which one is stored. priority lists source names from most to least trusted; unlisted sources rank
+// last, and on a tie the event that was stored first wins.
+func (uc *StockIngestionUseCase) SetSourcePriority(priority []string, window time.Duration) {
+	uc.sourcePriority = priority
+	uc.dedupWindow = window
+}
+
+// sourceRank returns the position of source in the priority list; lower ranks win
+func (uc *StockIngestionUseCase) sourceRank(source string) int {
+	for i, name := range uc.sourcePriority {
+		if name == source {
+			return i
+		}
+	}
+	return len(uc.sourcePriority)
+}
+
+// isSameBrokerAction checks if two events from different sources describe the same broker action
+func (uc *StockIngestionUseCase) isSameBrokerAction(incoming, stored *entities.Stock, brokers map[string]*entities.Broker) bool {
+	if incoming.Source == stored.Source || incoming.Ticker != stored.Ticker || !isSameBroker(incoming, stored, brokers) {
+		return false
+	}
+
+	gap := incoming.EventTime.Sub(stored.EventTime)
+	if gap < 0 {
+		gap = -gap
+	}
+	if gap > uc.dedupWindow {
+		return false
+	}
+
+	return strings.EqualFold(strings.TrimSpace(incoming.RatingTo), strings.TrimSpace(stored.RatingTo))
+}
+
+// isSameBroker checks if two events name the same broker: by ID, by the key of their brokerage names,
+// or by those keys resolving to the same broker through its name or an alias
+func isSameBroker(incoming, stored *entities.Stock, brokers map[string]*entities.Broker) bool {
+	if incoming.BrokerID == stored.BrokerID {
+		return true
+	}
+
+	incomingKey, storedKey := entities.BrokerKey(incoming.Brokerage), entities.BrokerKey(stored.Brokerage)
+	if incomingKey == "" || storedKey == "" {
+		return false
+	}
+	if incomingKey == storedKey {
+		return true
+	}
+
+	incomingBroker, ok := brokers[incomingKey]
+	if !ok {
+		return false
+	}
+	storedBroker, ok := brokers[storedKey]
+	return ok && incomingBroker.ID == storedBroker.ID
+}
+
+// deduplicate drops the stocks that a stored event from an equally or more trusted source already covers,
+// either as the same broker action or by holding the row of the same ticker and event time. For the
+// stocks that outrank stored copies, it returns the IDs of the copies to remove once they are stored.
+func (uc *StockIngestionUseCase) deduplicate(ctx context.Context, stocks []*entities.Stock) ([]*entities.Stock, map[*entities.Stock][]uuid.UUID, error) {
+	if (uc.dedupWindow <= 0 && len(uc.sourcePriority) == 0) || len(stocks) == 0 {
+		return stocks, nil, nil
+	}
+
+	window := max(uc.dedupWindow, 0)
+	tickers, from, to := eventSpan(stocks)
+	nearby, err := uc.stockRepo.GetNearbyEvents(ctx, tickers, from.Add(-window), to.Add(window))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load nearby events: %w", err)
+	}
+	if len(nearby) == 0 {
+		return stocks, nil, nil
+	}
+
+	brokers, err := loadBrokerIndex(ctx, uc.brokerRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	storedByTicker := make(map[string][]*entities.Stock)
+	for _, stored := range nearby {
+		storedByTicker[stored.Ticker] = append(storedByTicker[stored.Ticker], stored)
+	}
+
+	kept := make([]*entities.Stock, 0, len(stocks))
+	superseded := make(map[*entities.Stock][]uuid.UUID)
+
+	for _, stock := range stocks {
+		rank := uc.sourceRank(stock.Source)
+		covered := false
+		var outranked []uuid.UUID
+
+		for _, stored := range storedByTicker[stock.Ticker] {
+			// The upsert overwrites the stored row of the same ticker and event time, whatever it says
+			sameRow := stored.Source != stock.Source && stored.EventTime.Equal(stock.EventTime)
+			if !sameRow && (uc.dedupWindow <= 0 || !uc.isSameBrokerAction(stock, stored, brokers)) {
+				continue
+			}
+			if uc.sourceRank(stored.Source) <= rank {
+				if sameRow {
+					uc.logger.Info("Kept stored event from a more trusted source", "ticker", stock.Ticker, "source", stock.Source, "storedSource", stored.Source)
+				}
+				covered = true
+				break
+			}
+			// A copy with the same event time is overwritten in place by the upsert
+			if !sameRow {
+				outranked = append(outranked, stored.ID)
+			}
+		}
+
+		if covered {
+			continue
+		}
+		kept = append(kept, stock)
+		if len(outranked) > 0 {
+			superseded[stock] = outranked
+		}
+	}
+
+	return kept, superseded, nil
+}
+
+// removeSuperseded deletes the stored copies outranked by stocks that were stored successfully.
+// A copy that cannot be removed only costs a warning; it is retried when the event is seen again.
+func (uc *StockIngestionUseCase) removeSuperseded(ctx context.Context, superseded map[*entities.Stock][]uuid.UUID, failures []repositories.UpsertFailure) {
+	if len(superseded) == 0 {
+		return
+	}
+
+	failed := make(map[*entities.Stock]bool, len(failures))
+	for _, failure := range failures {
+		failed[failure.Stock] = true
+	}
+
+	for stock, ids := range superseded {
+		if failed[stock] {
+			continue
+		}
+		for _, id := range ids {
+			if err := uc.stockRepo.Delete(ctx, id); err != nil {
+				uc.logger.Warn("Failed to remove superseded event", "id", id, "error", err)
+				continue
+			}
+			uc.logger.Info("Removed event superseded by a higher-priority source", "ticker", stock.Ticker, "source", stock.Source, "id", id)
+		}
+	}
+}
+
+// eventSpan returns the distinct tickers of a non-empty set of stocks and the range of their event times
+func eventSpan(stocks []*entities.Stock) ([]string, time.Time, time.Time) {
+	tickerSet := make(map[string]struct{})
+	from, to := stocks[0].EventTime, stocks[0].EventTime
+	for _, stock := range stocks {
+		tickerSet[stock.Ticker] = struct{}{}
+		if stock.EventTime.Before(from) {
+			from = stock.EventTime
+		}
+		if stock.EventTime.After(to) {
+			to = stock.EventTime
+		}
+	}
+
+	tickers := make([]string, 0, len(tickerSet))
+	for ticker := range tickerSet {
+		tickers = append(tickers, ticker)
+	}
+
+	return tickers, from, to
+}
@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"stock-tracker/internal/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrStockInvalid is returned when a stock event written by an admin fails validation
+var ErrStockInvalid = errors.New("invalid stock event")
+
+// StockInput holds the fields of a stock event an admin creates or edits. Currency defaults to USD.
+type StockInput struct {
+	Ticker     string    `json:"ticker"`
+	Company    string    `json:"company"`
+	Brokerage  string    `json:"brokerage"`
+	Action     string    `json:"action"`
+	RatingFrom string    `json:"rating_from"`
+	RatingTo   string    `json:"rating_to"`
+	TargetFrom float64   `json:"target_from"`
+	TargetTo   float64   `json:"target_to"`
+	Currency   string    `json:"currency"`
+	EventTime  time.Time `json:"event_time"`
+}
+
+// StockEditUseCase lets admins correct the stored stock events by hand. Every write is recorded as a
+// StockChange naming the admin who made it.
+type StockEditUseCase interface {
+	CreateStock(ctx context.Context, actor uuid.UUID, input StockInput) (*entities.Stock, error)
+	UpdateStock(ctx context.Context, actor, id uuid.UUID, input StockInput) (*entities.Stock, error)
+	DeleteStock(ctx context.Context, actor, id uuid.UUID) error
+	ListStockChanges(ctx context.Context, id uuid.UUID) ([]*entities.StockChange, error)
+}
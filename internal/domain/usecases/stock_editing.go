@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+// manualSource marks stocks created by an admin through the API
+const manualSource = "manual"
+
+type StockEditingUseCase struct {
+	stockRepo         repositories.StockRepository
+	brokerRepo        repositories.BrokerRepository
+	normalizationRepo repositories.NormalizationRepository
+	changeRepo        repositories.StockChangeRepository
+	logger            logger.Logger
+}
+
+func NewStockEditingUseCase(
+	stockRepo repositories.StockRepository,
+	brokerRepo repositories.BrokerRepository,
+	normalizationRepo repositories.NormalizationRepository,
+	changeRepo repositories.StockChangeRepository,
+	logger logger.Logger,
+) StockEditUseCase {
+	return &StockEditingUseCase{
+		stockRepo:         stockRepo,
+		brokerRepo:        brokerRepo,
+		normalizationRepo: normalizationRepo,
+		changeRepo:        changeRepo,
+		logger:            logger,
+	}
+}
+
+// CreateStock stores a new stock event, creating its broker when unknown
+func (uc *StockEditingUseCase) CreateStock(ctx context.Context, actor uuid.UUID, input StockInput) (*entities.Stock, error) {
+	stock := entities.NewStock(input.Ticker, input.Company, input.Brokerage, input.Action, input.EventTime)
+	stock.Source = manualSource
+	if err := uc.apply(ctx, stock, input); err != nil {
+		return nil, err
+	}
+
+	if err := uc.stockRepo.ApplyChange(ctx, entities.NewStockChange(entities.StockChangeCreated, actor, nil, stock)); err != nil {
+		return nil, fmt.Errorf("failed to create stock: %w", err)
+	}
+
+	uc.logger.Info("Created stock event", "id", stock.ID, "ticker", stock.Ticker, "by", actor)
+	return stock, nil
+}
+
+// UpdateStock replaces the fields of a stock event. Its close price is cleared when the event moves to
+// another ticker or time, so price enrichment takes it again.
+func (uc *StockEditingUseCase) UpdateStock(ctx context.Context, actor, id uuid.UUID, input StockInput) (*entities.Stock, error) {
+	existing, err := uc.stockRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stock := *existing
+	stock.UpdatedAt = time.Now()
+	if err := uc.apply(ctx, &stock, input); err != nil {
+		return nil, err
+	}
+	if stock.Ticker != existing.Ticker || !stock.EventTime.Equal(existing.EventTime) {
+		stock.PriceClose = nil
+		stock.PriceCloseDate = nil
+	}
+
+	change := entities.NewStockChange(entities.StockChangeUpdated, actor, existing, &stock)
+	if err := uc.stockRepo.ApplyChange(ctx, change); err != nil {
+		return nil, fmt.Errorf("failed to update stock: %w", err)
+	}
+
+	uc.logger.Info("Updated stock event", "id", id, "fields", change.Fields, "by", actor)
+	return &stock, nil
+}
+
+// DeleteStock removes a stock event; its change history is kept
+func (uc *StockEditingUseCase) DeleteStock(ctx context.Context, actor, id uuid.UUID) error {
+	existing, err := uc.stockRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.stockRepo.ApplyChange(ctx, entities.NewStockChange(entities.StockChangeDeleted, actor, existing, nil)); err != nil {
+		return fmt.Errorf("failed to delete stock: %w", err)
+	}
+
+	uc.logger.Info("Deleted stock event", "id", id, "ticker", existing.Ticker, "by", actor)
+	return nil
+}
+
+// ListStockChanges returns the changes admins made to a stock event, newest first, even after it was deleted
+func (uc *StockEditingUseCase) ListStockChanges(ctx context.Context, id uuid.UUID) ([]*entities.StockChange, error) {
+	changes, err := uc.changeRepo.ListByStock(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve stock changes: %w", err)
+	}
+	return changes, nil
+}
+
+// apply copies input onto stock and validates the result the way ingestion does, resolving the broker
+// and normalizing the action and ratings
+func (uc *StockEditingUseCase) apply(ctx context.Context, stock *entities.Stock, input StockInput) error {
+	stock.Ticker = strings.ToUpper(strings.TrimSpace(input.Ticker))
+	stock.Company = strings.TrimSpace(input.Company)
+	stock.Brokerage = strings.TrimSpace(input.Brokerage)
+	stock.Action = strings.TrimSpace(input.Action)
+	stock.RatingFrom = strings.TrimSpace(input.RatingFrom)
+	stock.RatingTo = strings.TrimSpace(input.RatingTo)
+	stock.TargetFrom = input.TargetFrom
+	stock.TargetTo = input.TargetTo
+	stock.EventTime = input.EventTime
+
+	stock.Currency = entities.DefaultCurrency
+	if input.Currency != "" {
+		if stock.Currency = entities.NormalizeCurrency(input.Currency); stock.Currency == "" {
+			return fmt.Errorf("%w: invalid currency %q", ErrStockInvalid, input.Currency)
+		}
+	}
+
+	if err := stock.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrStockInvalid, err)
+	}
+	if stock.Brokerage == "" {
+		return fmt.Errorf("%w: brokerage is required", ErrStockInvalid)
+	}
+	if stock.EventTime.IsZero() {
+		return fmt.Errorf("%w: event_time is required", ErrStockInvalid)
+	}
+	if stock.TargetFrom < 0 || stock.TargetTo < 0 {
+		return fmt.Errorf("%w: targets cannot be negative", ErrStockInvalid)
+	}
+
+	broker, err := resolveBroker(ctx, uc.brokerRepo, stock.Brokerage)
+	if err != nil {
+		return fmt.Errorf("failed to resolve broker: %w", err)
+	}
+	stock.BrokerID = broker.ID
+
+	vocabulary, err := loadVocabulary(ctx, uc.normalizationRepo)
+	if err != nil {
+		return err
+	}
+	vocabulary.Normalize(stock)
+	return nil
+}
@@ -2,7 +2,9 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,125 +18,276 @@ import (
 )
 
 type StockIngestionUseCase struct {
-	stockRepo   repositories.StockRepository
-	brokerRepo  repositories.BrokerRepository
-	apiClient   clients.StockAPIClient
-	logger      logger.Logger
-	batchSize   int
-	workerCount int
+	stockRepo        repositories.StockRepository
+	brokerRepo       repositories.BrokerRepository
+	ingestionLogRepo repositories.IngestionLogRepository
+	checkpointRepo   repositories.IngestionCheckpointRepository
+	deadLetterRepo   repositories.DeadLetterRepository
+	source           clients.StockSource
+	logger           logger.Logger
+	batchSize        int
+	workerCount      int
+	pipelineBuffer   int
+	sourcePriority   []string
+	dedupWindow      time.Duration
+	elector          *LeaderElector
+	leaseName        string
+	normalization    repositories.NormalizationRepository
+	schemaProfiles   repositories.SchemaProfileRepository
+	schemaPolicy     SchemaDriftPolicy
+	qualityRules     *entities.QualityRuleSet
+	qualitySummaries repositories.QualitySummaryRepository
 }
 
 func NewStockIngestionUseCase(
 	stockRepo repositories.StockRepository,
 	brokerRepo repositories.BrokerRepository,
-	apiClient clients.StockAPIClient,
+	ingestionLogRepo repositories.IngestionLogRepository,
+	checkpointRepo repositories.IngestionCheckpointRepository,
+	deadLetterRepo repositories.DeadLetterRepository,
+	source clients.StockSource,
 	logger logger.Logger,
 ) *StockIngestionUseCase {
 	return &StockIngestionUseCase{
-		stockRepo:   stockRepo,
-		brokerRepo:  brokerRepo,
-		apiClient:   apiClient,
-		logger:      logger,
-		batchSize:   100,
-		workerCount: 5,
+		stockRepo:        stockRepo,
+		brokerRepo:       brokerRepo,
+		ingestionLogRepo: ingestionLogRepo,
+		checkpointRepo:   checkpointRepo,
+		deadLetterRepo:   deadLetterRepo,
+		source:           source,
+		logger:           logger,
+		batchSize:        100,
+		workerCount:      5,
+		pipelineBuffer:   4,
 	}
 }
 
 func (uc *StockIngestionUseCase) IngestStocks(ctx context.Context) error {
+	_, err := uc.IngestRun(ctx)
+	return err
+}
 
-	batchID := uuid.New().String()
-	startTime := time.Now()
+// IngestRun performs one ingestion like IngestStocks and also returns the recorded run,
+// so callers can tell a clean run from one that rejected records
+func (uc *StockIngestionUseCase) IngestRun(ctx context.Context) (*entities.IngestionLog, error) {
+	var run *entities.IngestionLog
 
-	uc.logger.Info("Starting stock ingestion batch", "batchID", batchID, "startTime", startTime)
+	err := uc.exclusive(ctx, func(ctx context.Context, fence Fence) error {
+		batchID := uuid.New().String()
+		startTime := time.Now()
 
-	stocks, err := uc.apiClient.FetchAllStocks(ctx)
-	if err != nil {
-		uc.logger.Error("Failed to fetch stocks from API", "error", err)
-		return fmt.Errorf("failed to fetch stocks: %w", err)
-	}
+		uc.logger.Info("Starting stock ingestion batch", "batchID", batchID, "startTime", startTime)
 
-	if len(stocks) == 0 {
-		uc.logger.Info("No stocks found in API", "batchID", batchID)
+		var err error
+		run, err = uc.recordRun(ctx, batchID, func(ctx context.Context, run *entities.IngestionLog) error {
+			return uc.ingest(ctx, run, fence)
+		})
+		if err != nil {
+			return err
+		}
+
+		uc.logger.Info("Stock ingestion batch completed", "batchID", batchID, "stocks", run.SuccessfulRecords, "duration", time.Since(startTime))
 		return nil
+	})
+
+	return run, err
+}
+
+// SetLeaderElection makes runs and backfills hold the lease on leaseName while they write, so replicas
+// sharing it never ingest at the same time. A run that finds the lease held fails with ErrLeaseHeld.
+func (uc *StockIngestionUseCase) SetLeaderElection(elector *LeaderElector, leaseName string) {
+	uc.elector = elector
+	uc.leaseName = leaseName
+}
+
+// SetNormalization makes runs normalize ratings and actions with the rules stored in normalizationRepo,
+// read afresh at the start of every run. Without it, only the built-in vocabulary is used.
+func (uc *StockIngestionUseCase) SetNormalization(normalizationRepo repositories.NormalizationRepository) {
+	uc.normalization = normalizationRepo
+}
+
+// exclusive runs job under the lease when leader election is enabled, and directly without a fence otherwise
+func (uc *StockIngestionUseCase) exclusive(ctx context.Context, job func(ctx context.Context, fence Fence) error) error {
+	if uc.elector == nil {
+		return job(ctx, nil)
 	}
+	return uc.elector.RunExclusive(ctx, uc.leaseName, job)
+}
 
-	uc.logger.Info("Fetched stocks from API", "batchID", batchID, "count", len(stocks))
+// recordRun records a run in the ingestion log around ingest, marking it completed or failed by its outcome
+func (uc *StockIngestionUseCase) recordRun(ctx context.Context, batchID string, ingest func(context.Context, *entities.IngestionLog) error) (*entities.IngestionLog, error) {
+	run := entities.NewIngestionLog(batchID, 0)
+	if err := uc.ingestionLogRepo.Create(ctx, run); err != nil {
+		uc.logger.Warn("Failed to record ingestion run start", "batchID", batchID, "error", err)
+	}
 
-	// Enrich the stocks with the Brokes IDs
+	err := ingest(ctx, run)
+	if err != nil {
+		run.Fail(failureDetails(err))
+	} else {
+		run.Complete()
+	}
 
-	if err := uc.enrichWithBrokerInfo(ctx, stocks); err != nil {
-		uc.logger.Error("Failed to enrich stocks with brokers", "error", err)
-		return fmt.Errorf("failed to enrich stocks with brokers: %w", err)
+	// The run context may already be cancelled, but the outcome still has to be recorded
+	if updateErr := uc.ingestionLogRepo.Update(context.WithoutCancel(ctx), run); updateErr != nil {
+		uc.logger.Warn("Failed to record ingestion run result", "batchID", batchID, "error", updateErr)
 	}
 
-	uc.logger.Info("Enriched stocks with brokers", "batchID", batchID, "count", len(stocks))
+	return run, err
+}
 
-	eg, ctx := errgroup.WithContext(ctx)
+// failureDetails describes why a run failed; upstream failures are classified so operators can tell a
+// bad API key from a throttled or unavailable vendor, and whether the next scheduled run may succeed
+func failureDetails(err error) map[string]interface{} {
+	details := map[string]interface{}{"error": err.Error()}
+
+	switch {
+	case errors.Is(err, clients.ErrUnauthorized):
+		details["reason"] = "unauthorized"
+		details["retryable"] = false
+	case errors.Is(err, clients.ErrRateLimited):
+		details["reason"] = "rate_limited"
+		details["retryable"] = true
+	case errors.Is(err, clients.ErrUpstreamUnavailable):
+		details["reason"] = "upstream_unavailable"
+		details["retryable"] = true
+	case errors.Is(err, ErrSchemaDrift):
+		details["reason"] = "schema_drift"
+		details["retryable"] = false
+	}
+
+	return details
+}
 
-	//Process Stocks in batches using worker pool
-	if err := uc.processStocksInBatches(ctx, eg, stocks); err != nil {
-		uc.logger.Error("Failed to process stocks in batches", "error", err)
-		return fmt.Errorf("failed to process stocks in batches: %w", err)
+// ingest streams the upstream API through the ingestion pipeline, starting from the open checkpoint,
+// and accumulates per-batch counters into run
+func (uc *StockIngestionUseCase) ingest(ctx context.Context, run *entities.IngestionLog, fence Fence) error {
+	batchID := run.BatchID
+
+	checkpoint, err := uc.openCheckpoint(ctx, batchID)
+	if err != nil {
+		uc.logger.Error("Failed to open ingestion checkpoint", "error", err)
+		return fmt.Errorf("failed to open ingestion checkpoint: %w", err)
 	}
 
-	if err := eg.Wait(); err != nil {
-		uc.logger.Error("Error during stock ingestion", "error", err)
-		return fmt.Errorf("error during stock ingestion: %w", err)
+	if err := uc.runPipeline(ctx, run, checkpoint, runMode{fence: fence}); err != nil {
+		return err
 	}
-	duration := time.Since(startTime)
-	uc.logger.Info("Stock ingestion batch completed, for a total of", len(stocks), "stocks", "batchID", batchID, "duration", duration)
+
+	if run.TotalRecords == 0 {
+		uc.logger.Info("No new stocks found in API", "batchID", batchID)
+	}
+
 	return nil
 }
 
-func (uc *StockIngestionUseCase) enrichWithBrokerInfo(ctx context.Context, stocks []*entities.Stock) error {
-	// Get all existing brokers
-	brokers, err := uc.brokerRepo.GetAll(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get brokers: %w", err)
+// openCheckpoint resumes the latest checkpoint of the source if its run was interrupted, or saves a new one
+func (uc *StockIngestionUseCase) openCheckpoint(ctx context.Context, batchID string) (*entities.IngestionCheckpoint, error) {
+	checkpoint, resumed, err := uc.nextCheckpoint(ctx, batchID)
+	if err != nil || resumed {
+		return checkpoint, err
 	}
 
-	brokerMap := make(map[string]*entities.Broker)
-	for _, broker := range brokers {
-		brokerMap[broker.Name] = broker
+	if err := uc.checkpointRepo.Save(ctx, checkpoint); err != nil {
+		return nil, err
 	}
 
-	// Create missing brokers and assign IDs
-	var newBrokers []*entities.Broker
-	for _, stock := range stocks {
-		if broker, exists := brokerMap[stock.Brokerage]; exists {
-			stock.BrokerID = broker.ID
-		} else {
-			// Create new broker with default credibility score
-			newBroker := entities.NewBroker(stock.Brokerage, 0.60)
-			newBrokers = append(newBrokers, newBroker)
-			brokerMap[stock.Brokerage] = newBroker
-			stock.BrokerID = newBroker.ID
+	return checkpoint, nil
+}
+
+// nextCheckpoint returns the checkpoint the next run starts from without saving it: the latest checkpoint of
+// the source if its run was interrupted, or a new one. Sources served newest first are watermarked at the
+// newest event stored from them once a previous run has caught up, while other sources are read in full every time.
+func (uc *StockIngestionUseCase) nextCheckpoint(ctx context.Context, batchID string) (*entities.IngestionCheckpoint, bool, error) {
+	source := uc.source.Name()
+
+	latest, err := uc.checkpointRepo.GetLatest(ctx, source)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, false, err
+	}
+
+	if latest != nil && !latest.IsCompleted() {
+		uc.logger.Info("Resuming ingestion from checkpoint", "source", source, "pages", latest.PagesFetched, "nextPage", latest.NextPage)
+		return latest, true, nil
+	}
+
+	var watermark *time.Time
+	if latest != nil && uc.source.NewestFirst() {
+		if watermark, err = uc.stockRepo.GetLatestEventTime(ctx, source); err != nil {
+			return nil, false, err
 		}
 	}
 
+	return entities.NewIngestionCheckpoint(batchID, source, watermark), false, nil
+}
+
+// loadBrokers indexes the existing brokers by the key of their names and aliases
+func (uc *StockIngestionUseCase) loadBrokers(ctx context.Context) (map[string]*entities.Broker, error) {
+	return loadBrokerIndex(ctx, uc.brokerRepo)
+}
+
+func (uc *StockIngestionUseCase) enrichWithBrokerInfo(ctx context.Context, stocks []*entities.Stock, brokerMap map[string]*entities.Broker) {
 	// Save new brokers
-	for _, broker := range newBrokers {
+	for _, broker := range assignBrokers(stocks, brokerMap) {
 		if err := uc.brokerRepo.Create(ctx, broker); err != nil {
 			uc.logger.Warn("Failed to create broker", "name", broker.Name, "error", err)
 		}
 	}
+}
 
-	return nil
+// assignBrokers sets the broker ID of each stock, adding a broker with the default credibility score
+// to brokerMap for every unknown brokerage, and returns the brokers it added
+func assignBrokers(stocks []*entities.Stock, brokerMap map[string]*entities.Broker) []*entities.Broker {
+	var newBrokers []*entities.Broker
+	for _, stock := range stocks {
+		key := entities.BrokerKey(stock.Brokerage)
+		if broker, exists := brokerMap[key]; exists {
+			stock.BrokerID = broker.ID
+		} else {
+			newBroker := entities.NewBroker(stock.Brokerage, 0.60)
+			newBrokers = append(newBrokers, newBroker)
+			brokerMap[key] = newBroker
+			stock.BrokerID = newBroker.ID
+		}
+	}
+	return newBrokers
 }
 
-func (uc *StockIngestionUseCase) processStocksInBatches(ctx context.Context, eg *errgroup.Group, stocks []*entities.Stock) error {
+// processStocksInBatches persists stocks in batches of batchSize, with at most workerCount batches in flight.
+// A batch that cannot be committed at all fails the whole call; records rejected individually are only
+// counted and returned.
+func (uc *StockIngestionUseCase) processStocksInBatches(ctx context.Context, stocks []*entities.Stock, tally *runTally) ([]repositories.UpsertFailure, error) {
 	batches := uc.createBatches(stocks)
 
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(uc.workerCount)
+
+	var mu sync.Mutex
+	var failures []repositories.UpsertFailure
+
 	for i, batch := range batches {
 		batchNum := i
 		batch := batch // capture loop variable
 
 		eg.Go(func() error {
-			return uc.processBatch(ctx, batch, batchNum)
+			result, err := uc.processBatch(ctx, batch, batchNum)
+			if err != nil {
+				tally.rejected(len(batch))
+				return err
+			}
+			tally.persisted(result)
+
+			mu.Lock()
+			failures = append(failures, result.Failures...)
+			mu.Unlock()
+			return nil
 		})
 	}
 
-	return nil
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return failures, nil
 }
 
 // GetStats returns basic statistics about the stock data
@@ -203,18 +356,22 @@ func (uc *StockIngestionUseCase) createBatches(stocks []*entities.Stock) [][]*en
 	return batches
 }
 
-func (uc *StockIngestionUseCase) processBatch(ctx context.Context, batch []*entities.Stock, batchNum int) error {
+func (uc *StockIngestionUseCase) processBatch(ctx context.Context, batch []*entities.Stock, batchNum int) (*repositories.UpsertResult, error) {
 	uc.logger.Info("Processing batch", "batch_num", batchNum, "size", len(batch))
 
 	startTime := time.Now()
-	err := uc.stockRepo.BulkCreate(ctx, batch)
+	result, err := uc.stockRepo.BulkUpsert(ctx, batch)
 	duration := time.Since(startTime)
 
 	if err != nil {
 		uc.logger.Error("Failed to process batch", "batch_num", batchNum, "error", err)
-		return fmt.Errorf("failed to process batch %d: %w", batchNum, err)
+		return nil, fmt.Errorf("failed to process batch %d: %w", batchNum, err)
 	}
 
-	uc.logger.Info("Completed batch", "batch_num", batchNum, "duration", duration)
-	return nil
+	for _, failure := range result.Failures {
+		uc.logger.Warn("Failed to store stock", "batch_num", batchNum, "ticker", failure.Stock.Ticker, "error", failure.Err)
+	}
+
+	uc.logger.Info("Completed batch", "batch_num", batchNum, "result", result, "duration", duration)
+	return result, nil
 }
@@ -2,9 +2,14 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
 	"stock-tracker/internal/domain/repositories"
 	"stock-tracker/internal/domain/valueObjects"
 	"stock-tracker/pkg/logger"
@@ -13,49 +18,149 @@ import (
 type StockQueryUseCase struct {
 	stockRepo  repositories.StockRepository
 	brokerRepo repositories.BrokerRepository
+	fxRates    *entities.FXTable
 	logger     logger.Logger
 }
 
+// NewStockQueryUseCase creates a StockUseCase converting targets with fxRates; a nil table only
+// serves targets in the currency they were quoted in
 func NewStockQueryUseCase(
 	stockRepo repositories.StockRepository,
 	brokerRepo repositories.BrokerRepository,
+	fxRates *entities.FXTable,
 	logger logger.Logger,
 ) StockUseCase {
+	if fxRates == nil {
+		fxRates = entities.NewFXTable()
+	}
 	return &StockQueryUseCase{
 		stockRepo:  stockRepo,
 		brokerRepo: brokerRepo,
+		fxRates:    fxRates,
 		logger:     logger,
 	}
 }
 
-// GetStocks returns stocks with pagination
+// GetStocks returns stocks with pagination, with their targets in the display currency of the filters when set
 func (uc *StockQueryUseCase) GetStocks(ctx context.Context, filters valueObjects.StockFilters) (interface{}, *valueObjects.Pagination, error) {
 	uc.logger.Info("Getting stocks with filters", "filters", filters)
 
+	currency, err := uc.displayCurrency(filters.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	stocks, pagination, err := uc.stockRepo.GetAll(ctx, filters)
 	if err != nil {
 		uc.logger.Error("Failed to get stocks from repository", "error", err)
 		return nil, nil, fmt.Errorf("failed to retrieve stocks: %w", err)
 	}
+	uc.convertTargets(stocks, currency)
 
 	uc.logger.Info("Successfully retrieved stocks", "count", len(stocks), "total", pagination.TotalItems)
 	return stocks, pagination, nil
 }
 
-// GetStocksByTicker returns stocks for a specific ticker
-func (uc *StockQueryUseCase) GetStocksByTicker(ctx context.Context, ticker string) (interface{}, error) {
-	uc.logger.Info("Getting stocks by ticker", "ticker", ticker)
+// GetStocksByTicker returns stocks for a specific ticker, as they looked at asOf when it is set
+// and with their targets in currency when it is not empty
+func (uc *StockQueryUseCase) GetStocksByTicker(ctx context.Context, ticker string, asOf *time.Time, currency string) (interface{}, error) {
+	uc.logger.Info("Getting stocks by ticker", "ticker", ticker, "asOf", asOf)
 
-	stocks, err := uc.stockRepo.GetByTicker(ctx, ticker)
+	currency, err := uc.displayCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var stocks []*entities.Stock
+	if asOf != nil {
+		stocks, err = uc.stockRepo.GetByTickerAsOf(ctx, ticker, *asOf)
+	} else {
+		stocks, err = uc.stockRepo.GetByTicker(ctx, ticker)
+	}
 	if err != nil {
 		uc.logger.Error("Failed to get stocks by ticker", "ticker", ticker, "error", err)
 		return nil, fmt.Errorf("failed to retrieve stocks for ticker %s: %w", ticker, err)
 	}
+	uc.convertTargets(stocks, currency)
 
 	uc.logger.Info("Successfully retrieved stocks by ticker", "ticker", ticker, "count", len(stocks))
 	return stocks, nil
 }
 
+// GetStockByID returns a single stock event, with its targets in currency when it is not empty
+func (uc *StockQueryUseCase) GetStockByID(ctx context.Context, id uuid.UUID, currency string) (*entities.Stock, error) {
+	currency, err := uc.displayCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	stock, err := uc.stockRepo.GetByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			uc.logger.Error("Failed to get stock by ID", "id", id, "error", err)
+		}
+		return nil, fmt.Errorf("failed to retrieve stock %s: %w", id, err)
+	}
+	uc.convertTargets([]*entities.Stock{stock}, currency)
+
+	return stock, nil
+}
+
+// GetTickerConsensus computes the consensus of the brokers covering a ticker from its stored events
+func (uc *StockQueryUseCase) GetTickerConsensus(ctx context.Context, ticker string, currency string) (*entities.TickerConsensus, error) {
+	currency, err := uc.displayCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = entities.DefaultCurrency
+	}
+
+	stocks, credibility, err := uc.stockRepo.GetByTickerWithCredibility(ctx, ticker)
+	if err != nil {
+		uc.logger.Error("Failed to get stocks by ticker", "ticker", ticker, "error", err)
+		return nil, fmt.Errorf("failed to retrieve stocks for ticker %s: %w", ticker, err)
+	}
+	if len(stocks) == 0 {
+		return nil, fmt.Errorf("ticker %s: %w", ticker, repositories.ErrNotFound)
+	}
+
+	uc.convertTargets(stocks, currency)
+	return entities.NewTickerConsensus(ticker, stocks, credibility, currency, time.Now()), nil
+}
+
+// displayCurrency validates a requested display currency, returning it as an ISO 4217 code or empty when none was requested
+func (uc *StockQueryUseCase) displayCurrency(requested string) (string, error) {
+	if requested == "" {
+		return "", nil
+	}
+
+	currency := entities.NormalizeCurrency(requested)
+	if currency == "" || !uc.fxRates.Knows(currency) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedCurrency, requested)
+	}
+	return currency, nil
+}
+
+// convertTargets quotes the targets of stocks in currency, rounded to the cent. Stocks in a currency the
+// table has no rate for keep their own, which their currency field still tells.
+func (uc *StockQueryUseCase) convertTargets(stocks []*entities.Stock, currency string) {
+	if currency == "" {
+		return
+	}
+
+	for _, stock := range stocks {
+		rate, ok := uc.fxRates.Rate(stock.Currency, currency)
+		if !ok {
+			uc.logger.Warn("No exchange rate for stock targets", "ticker", stock.Ticker, "from", stock.Currency, "to", currency)
+			continue
+		}
+		stock.TargetFrom = math.Round(stock.TargetFrom*rate*100) / 100
+		stock.TargetTo = math.Round(stock.TargetTo*rate*100) / 100
+		stock.Currency = currency
+	}
+}
+
 // GetStats returns basic statistics about the stock data
 func (uc *StockQueryUseCase) GetStats(ctx context.Context) (interface{}, error) {
 	uc.logger.Info("Getting stock statistics")
@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/infrastructure/clients"
+)
+
+// reprocessPageSize is the number of stored payloads read and re-derived at a time
+const reprocessPageSize = 500
+
+// Reprocess re-derives the stocks fetched from the source from their stored raw payloads, as a run of its own,
+// so changes to parsing or broker resolution reach the events already ingested without calling the vendor again.
+// Re-derived stocks go through the regular upsert: events whose values change keep their prior values as revisions
+// and the rest count as duplicates. A payload that no longer parses, or now parses to another event, is counted
+// as failed and its stored event is left untouched. filter narrows the payloads by run and ticker.
+func (uc *StockIngestionUseCase) Reprocess(ctx context.Context, filter repositories.PayloadFilter) (*entities.IngestionLog, error) {
+	var run *entities.IngestionLog
+
+	err := uc.exclusive(ctx, func(ctx context.Context, fence Fence) error {
+		batchID := uuid.New().String()
+		startTime := time.Now()
+
+		uc.logger.Info("Starting stock reprocessing", "batchID", batchID, "source", uc.source.Name())
+
+		var err error
+		run, err = uc.recordRun(ctx, batchID, func(ctx context.Context, run *entities.IngestionLog) error {
+			return uc.reprocess(ctx, run, filter, fence)
+		})
+		if err != nil {
+			return err
+		}
+
+		uc.logger.Info("Stock reprocessing completed", "batchID", batchID, "stocks", run.SuccessfulRecords, "duration", time.Since(startTime))
+		return nil
+	})
+
+	return run, err
+}
+
+// reprocess pages through the stored payloads of the source in stock ID order and upserts the stocks
+// re-derived from each page, checking the fence before every write like persistPages
+func (uc *StockIngestionUseCase) reprocess(ctx context.Context, run *entities.IngestionLog, filter repositories.PayloadFilter, fence Fence) error {
+	vocabulary, err := loadVocabulary(ctx, uc.normalization)
+	if err != nil {
+		uc.logger.Error("Failed to load normalization rules", "error", err)
+		return err
+	}
+
+	brokerMap, err := uc.loadBrokers(ctx)
+	if err != nil {
+		uc.logger.Error("Failed to load brokers", "error", err)
+		return fmt.Errorf("failed to enrich stocks with brokers: %w", err)
+	}
+
+	tally := &runTally{run: run}
+
+	filter.Source = uc.source.Name()
+	filter.After = uuid.Nil
+	filter.Limit = reprocessPageSize
+
+	for {
+		payloads, err := uc.stockRepo.ListPayloads(ctx, filter)
+		if err != nil {
+			uc.logger.Error("Failed to list raw payloads", "error", err)
+			return fmt.Errorf("failed to list raw payloads: %w", err)
+		}
+		if len(payloads) == 0 {
+			return nil
+		}
+
+		stocks := make([]*entities.Stock, 0, len(payloads))
+		for _, stored := range payloads {
+			stock, err := rederiveStock(stored, vocabulary)
+			if err != nil {
+				uc.logger.Warn("Failed to reprocess raw payload", "stockID", stored.Payload.StockID, "error", err)
+				tally.rejected(1)
+				continue
+			}
+			stocks = append(stocks, stock)
+		}
+
+		if len(stocks) > 0 {
+			uc.enrichWithBrokerInfo(ctx, stocks, brokerMap)
+
+			if fence != nil {
+				if err := fence.Check(ctx); err != nil {
+					uc.logger.Error("Stopped reprocessing without the job lease", "error", err)
+					return fmt.Errorf("failed to confirm job lease: %w", err)
+				}
+			}
+
+			if _, err := uc.processStocksInBatches(ctx, stocks, tally); err != nil {
+				uc.logger.Error("Error during stock reprocessing", "error", err)
+				return fmt.Errorf("error during stock reprocessing: %w", err)
+			}
+		}
+
+		if len(payloads) < filter.Limit {
+			return nil
+		}
+		filter.After = payloads[len(payloads)-1].Payload.StockID
+	}
+}
+
+// rederiveStock parses a stored payload the way convertPages parses a fetched item. The stock must
+// still be the event the payload is stored against, since the upsert matches events by their key.
+func rederiveStock(stored repositories.StoredPayload, vocabulary *entities.Vocabulary) (*entities.Stock, error) {
+	var item clients.StockAPIItem
+	if err := json.Unmarshal(stored.Payload.Payload, &item); err != nil {
+		return nil, fmt.Errorf("failed to decode raw payload: %w", err)
+	}
+
+	stock, err := clients.ConvertAPIItem(item)
+	if err != nil {
+		return nil, err
+	}
+	if stock.Ticker != stored.Ticker || !stock.EventTime.Equal(stored.EventTime) {
+		return nil, fmt.Errorf("payload now parses to %s at %s, stored as %s at %s", stock.Ticker,
+			stock.EventTime.Format(time.RFC3339), stored.Ticker, stored.EventTime.Format(time.RFC3339))
+	}
+	if err := validateStock(stock); err != nil {
+		return nil, err
+	}
+
+	stock.Source = stored.Payload.Source
+	vocabulary.Normalize(stock)
+	return stock, nil
+}
@@ -2,11 +2,27 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"stock-tracker/internal/domain/entities"
 	"stock-tracker/internal/domain/valueObjects"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+// ErrUnsupportedCurrency is returned when targets are requested in a currency the FX rate table does not know
+var ErrUnsupportedCurrency = errors.New("unsupported display currency")
+
 type StockUseCase interface {
 	GetStocks(ctx context.Context, filters valueObjects.StockFilters) (interface{}, *valueObjects.Pagination, error)
-	GetStocksByTicker(ctx context.Context, ticker string) (interface{}, error)
+	// GetStocksByTicker returns the stocks of a ticker, as they looked at asOf when it is set and with
+	// their targets converted into currency when it is not empty
+	GetStocksByTicker(ctx context.Context, ticker string, asOf *time.Time, currency string) (interface{}, error)
+	// GetStockByID returns a single stock event, with its targets converted into currency when it is not
+	// empty, or repositories.ErrNotFound
+	GetStockByID(ctx context.Context, id uuid.UUID, currency string) (*entities.Stock, error)
+	// GetTickerConsensus computes the consensus of the brokers covering a ticker, with its targets in
+	// currency or in entities.DefaultCurrency when currency is empty, or repositories.ErrNotFound
+	GetTickerConsensus(ctx context.Context, ticker string, currency string) (*entities.TickerConsensus, error)
 	GetStats(ctx context.Context) (interface{}, error)
 }
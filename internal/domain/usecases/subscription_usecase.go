@@ -86,11 +86,6 @@ func (uc *SubscriptionUseCase) SimulatePayment(ctx context.Context, subscription
 	paymentRef := fmt.Sprintf("sim_payment_%s_%d", subscriptionID.String()[:8], time.Now().Unix())
 	subscription.Activate(paymentRef)
 
-	if err := uc.subscriptionRepo.Update(ctx, subscription); err != nil {
-		uc.logger.Error("Failed to activate subscription", "error", err, "subscription_id", subscriptionID)
-		return fmt.Errorf("failed to activate subscription: %w", err)
-	}
-
 	// Update user tier to premium
 	user, err := uc.userRepo.GetByID(ctx, subscription.UserID)
 	if err != nil {
@@ -101,9 +96,10 @@ func (uc *SubscriptionUseCase) SimulatePayment(ctx context.Context, subscription
 	user.Tier = entities.TIER_PREMIUM
 	user.SetUpdatedAt(time.Now())
 
-	if err := uc.userRepo.Update(ctx, user); err != nil {
-		uc.logger.Error("Failed to update user tier", "error", err, "user_id", user.ID)
-		return fmt.Errorf("failed to update user tier: %w", err)
+	// The subscription and the tier change together, so a user never pays without being upgraded
+	if err := uc.subscriptionRepo.Activate(ctx, subscription, user); err != nil {
+		uc.logger.Error("Failed to activate subscription", "error", err, "subscription_id", subscriptionID, "user_id", user.ID)
+		return fmt.Errorf("failed to activate subscription: %w", err)
 	}
 
 	uc.logger.Info("Payment simulated and subscription activated",
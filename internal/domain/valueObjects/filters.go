@@ -2,22 +2,71 @@ package valueObjects
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// Ticker match modes of StockFilters.TickerMatch
+const (
+	// TickerMatchContains matches the tickers containing the filter, ignoring case
+	TickerMatchContains = "contains"
+	// TickerMatchExact matches the ticker equal to the filter, ignoring case
+	TickerMatchExact = "exact"
+)
+
+// Rating directions of StockFilters.RatingDirection, comparing the tiers of the ratings a broker moved between.
+// Events whose ratings do not both map onto a tier have no direction.
+const (
+	RatingDirectionUpgrade   = "upgrade"
+	RatingDirectionDowngrade = "downgrade"
+	RatingDirectionUnchanged = "unchanged"
 )
 
 type StockFilters struct {
-	Ticker     string     `json:"ticker,omitempty" form:"ticker"`
-	Company    string     `json:"company,omitempty" form:"company"`
-	Brokerage  string     `json:"brokerage,omitempty" form:"brokerage"`
-	Action     string     `json:"action,omitempty" form:"action"`
-	RatingFrom string     `json:"rating_from,omitempty" form:"rating_from"`
-	RatingTo   string     `json:"rating_to,omitempty" form:"rating_to"`
-	DateFrom   *time.Time `json:"date_from,omitempty" form:"date_from"`
-	DateTo     *time.Time `json:"date_to,omitempty" form:"date_to"`
-	SortBy     string     `json:"sort_by,omitempty" form:"sort_by"`
-	SortOrder  string     `json:"sort_order,omitempty" form:"sort_order"`
-	Limit      int        `json:"limit,omitempty" form:"limit"`
-	Offset     int        `json:"offset,omitempty" form:"offset"`
+	// Ticker matches tickers according to TickerMatch
+	Ticker string `json:"ticker,omitempty" form:"ticker"`
+	// TickerMatch is TickerMatchContains or TickerMatchExact; empty means contains
+	TickerMatch string `json:"ticker_match,omitempty" form:"ticker_match"`
+	// Tickers restricts the events to these tickers when set, ignoring case
+	Tickers []string `json:"tickers,omitempty" form:"tickers"`
+	Company string   `json:"company,omitempty" form:"company"`
+	// Brokerage matches the brokers whose name contains it, ignoring case
+	Brokerage string `json:"brokerage,omitempty" form:"brokerage"`
+	// Brokerages restricts the events to the brokers with these names when set, ignoring case
+	Brokerages []string `json:"brokerages,omitempty" form:"brokerages"`
+	// Action matches the raw actions containing it, ignoring case
+	Action string `json:"action,omitempty" form:"action"`
+	// ActionType matches the normalized action type
+	ActionType entities.ActionType `json:"action_type,omitempty" form:"action_type"`
+	// RatingFrom and RatingTo match the raw ratings equal to them, ignoring case
+	RatingFrom string `json:"rating_from,omitempty" form:"rating_from"`
+	RatingTo   string `json:"rating_to,omitempty" form:"rating_to"`
+	// RatingDirection is one of the RatingDirection constants
+	RatingDirection string `json:"rating_direction,omitempty" form:"rating_direction"`
+	// MinTargetChange and MaxTargetChange bound the change from target_from to target_to, in percent of
+	// target_from. Events without a previous target have no change and are left out when either is set.
+	MinTargetChange *float64   `json:"min_target_change,omitempty" form:"min_target_change"`
+	MaxTargetChange *float64   `json:"max_target_change,omitempty" form:"max_target_change"`
+	Source          string     `json:"source,omitempty" form:"source"`
+	DateFrom        *time.Time `json:"date_from,omitempty" form:"date_from"`
+	DateTo          *time.Time `json:"date_to,omitempty" form:"date_to"`
+	AsOf            *time.Time `json:"as_of,omitempty" form:"as_of"`
+	// Currency is the display currency targets are converted into; empty keeps the currency of each event
+	Currency string `json:"currency,omitempty" form:"currency"`
+	// Sort orders the stocks by several fields; when empty they are ordered by SortBy in SortOrder
+	Sort      []SortKey `json:"sort,omitempty" form:"sort"`
+	SortBy    string    `json:"sort_by,omitempty" form:"sort_by"`
+	SortOrder string    `json:"sort_order,omitempty" form:"sort_order"`
+	Limit     int       `json:"limit,omitempty" form:"limit"`
+	Offset    int       `json:"offset,omitempty" form:"offset"`
+	// Cursor continues from a page returned before, instead of skipping Offset stocks. A cursor is tied
+	// to the sort order it was issued for.
+	Cursor string `json:"cursor,omitempty" form:"cursor"`
+	// IncludeTotal counts the matching stocks on cursor pages; offset pages are always counted
+	IncludeTotal bool `json:"include_total,omitempty" form:"include_total"`
 }
 
 func (f *StockFilters) SetDefaults() {
@@ -37,30 +86,81 @@ func (f *StockFilters) SetDefaults() {
 	// when no date range is specified by the user
 }
 
+// SortKeys returns the fields to order the stocks by, from Sort or else from SortBy and SortOrder
+func (f *StockFilters) SortKeys() []SortKey {
+	if len(f.Sort) > 0 {
+		return f.Sort
+	}
+	return []SortKey{{Field: f.SortBy, Descending: strings.EqualFold(f.SortOrder, "desc")}}
+}
+
 func (f *StockFilters) Validate() error {
 	if f.DateFrom != nil && f.DateTo != nil && f.DateFrom.After(*f.DateTo) {
 		return errors.New("date_from must be before date_to")
 	}
 
+	switch f.TickerMatch {
+	case "", TickerMatchContains, TickerMatchExact:
+	default:
+		return fmt.Errorf("ticker_match must be %q or %q", TickerMatchContains, TickerMatchExact)
+	}
+	if f.TickerMatch == TickerMatchExact && f.Ticker == "" {
+		return errors.New("ticker_match=exact requires a ticker")
+	}
+
+	if f.ActionType != entities.ActionTypeUnknown && !f.ActionType.IsValid() {
+		return fmt.Errorf("action_type must be one of %v", entities.ActionTypes)
+	}
+
+	switch f.RatingDirection {
+	case "", RatingDirectionUpgrade, RatingDirectionDowngrade, RatingDirectionUnchanged:
+	default:
+		return fmt.Errorf("rating_direction must be %q, %q or %q", RatingDirectionUpgrade, RatingDirectionDowngrade, RatingDirectionUnchanged)
+	}
+
+	if f.MinTargetChange != nil && f.MaxTargetChange != nil && *f.MinTargetChange > *f.MaxTargetChange {
+		return errors.New("min_target_change must not be greater than max_target_change")
+	}
+
+	for _, key := range f.SortKeys() {
+		if !IsSortable(key.Field) {
+			return fmt.Errorf("cannot sort by %q, sortable fields are %s", key.Field, strings.Join(SortableFields, ", "))
+		}
+	}
+	if !strings.EqualFold(f.SortOrder, "asc") && !strings.EqualFold(f.SortOrder, "desc") {
+		return errors.New(`sort_order must be "asc" or "desc"`)
+	}
+
 	if f.Limit <= 0 {
 		return errors.New("limit must be greater than 0")
 	}
 	if f.Limit > 1000 {
-		return errors.New("limit must be less than 1000")
+		return errors.New("limit must be at most 1000")
 	}
 
 	if f.Offset < 0 {
-		return errors.New("offset must be greater than 0")
+		return errors.New("offset must not be negative")
+	}
+	if f.Cursor != "" && f.Offset != 0 {
+		return errors.New("offset cannot be combined with cursor")
 	}
 
 	return nil
 }
 
+// TotalUnknown is the total of items and pages of a cursor page whose total was not counted
+const TotalUnknown = -1
+
+// Pagination describes a page of results. Offset pages are numbered; cursor pages have Page 0 and only
+// know their totals when asked to count them. NextCursor and PrevCursor are set on either kind of page
+// that has a neighbour, so a client can switch to cursors at any point.
 type Pagination struct {
-	Page       int  `json:"page" form:"page"`
-	Limit      int  `json:"limit" form:"limit"`
-	TotalPages int  `json:"total_pages"`
-	TotalItems int  `json:"total_items"`
-	HasNext    bool `json:"has_next"`
-	HasPrev    bool `json:"has_prev"`
+	Page       int    `json:"page" form:"page"`
+	Limit      int    `json:"limit" form:"limit"`
+	TotalPages int    `json:"total_pages"`
+	TotalItems int    `json:"total_items"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
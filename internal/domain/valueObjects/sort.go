@@ -0,0 +1,81 @@
+package valueObjects
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Public names of the fields stocks can be sorted by
+const (
+	SortEventTime         = "event_time"
+	SortTicker            = "ticker"
+	SortCompany           = "company"
+	SortBrokerage         = "brokerage"
+	SortAction            = "action"
+	SortTargetFrom        = "target_from"
+	SortTargetTo          = "target_to"
+	SortTargetChange      = "target_change"
+	SortBrokerCredibility = "broker_credibility"
+	SortCreatedAt         = "created_at"
+)
+
+// SortableFields lists the fields stocks can be sorted by
+var SortableFields = []string{
+	SortEventTime, SortTicker, SortCompany, SortBrokerage, SortAction,
+	SortTargetFrom, SortTargetTo, SortTargetChange, SortBrokerCredibility, SortCreatedAt,
+}
+
+// SortKey orders stocks by one field
+type SortKey struct {
+	Field      string `json:"field"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+func (k SortKey) String() string {
+	if k.Descending {
+		return "-" + k.Field
+	}
+	return k.Field
+}
+
+// IsSortable checks if stocks can be sorted by field
+func IsSortable(field string) bool {
+	for _, sortable := range SortableFields {
+		if field == sortable {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSort reads a sort spec: a comma separated list of fields, each sorted in ascending order or in
+// descending order when prefixed with "-", such as "-target_change,ticker". Earlier fields take precedence.
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return nil, errors.New("sort must not contain empty fields")
+		}
+
+		key := SortKey{Field: item}
+		switch item[0] {
+		case '-':
+			key = SortKey{Field: item[1:], Descending: true}
+		case '+':
+			key = SortKey{Field: item[1:]}
+		}
+
+		if !IsSortable(key.Field) {
+			return nil, fmt.Errorf("cannot sort by %q, sortable fields are %s", key.Field, strings.Join(SortableFields, ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("sort lists %q more than once", key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
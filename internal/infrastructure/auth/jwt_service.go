@@ -29,6 +29,7 @@ type Claims struct {
 	UserID uuid.UUID         `json:"user_id"`
 	Email  string            `json:"email"`
 	Tier   entities.UserTier `json:"tier"`
+	Role   entities.UserRole `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -64,6 +65,7 @@ func (s *jwtService) GenerateTokenPair(user *entities.User) (*TokenPair, error)
 		UserID: user.ID,
 		Email:  user.Email,
 		Tier:   user.Tier,
+		Role:   user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.accessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,155 @@
+package clients
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-tracker/pkg/logger"
+)
+
+// csvPriceProvider reads daily OHLC bars from one CSV file per ticker, named after the ticker (AAPL.csv).
+// Each file has a header row with at least a Date column, as YYYY-MM-DD, and a Close column; other
+// columns such as Open, High, Low and Volume are ignored. Files are read once and kept in memory.
+type csvPriceProvider struct {
+	dir    string
+	logger logger.Logger
+
+	mu     sync.Mutex
+	closes map[string][]DailyClose
+}
+
+// NewCSVPriceProvider creates a PriceProvider reading the OHLC files in dir
+func NewCSVPriceProvider(dir string, logger logger.Logger) (PriceProvider, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open price directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	return &csvPriceProvider{
+		dir:    dir,
+		logger: logger,
+		closes: make(map[string][]DailyClose),
+	}, nil
+}
+
+func (p *csvPriceProvider) Name() string {
+	return "csv:" + p.dir
+}
+
+// DailyCloses returns the closes of ticker within [from, to] from its file
+func (p *csvPriceProvider) DailyCloses(ctx context.Context, ticker string, from, to time.Time) ([]DailyClose, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	closes, err := p.load(ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to = tradingDay(from), tradingDay(to)
+	start := sort.Search(len(closes), func(i int) bool { return !closes[i].Date.Before(from) })
+	end := sort.Search(len(closes), func(i int) bool { return closes[i].Date.After(to) })
+	if start >= end {
+		return nil, nil
+	}
+	return closes[start:end], nil
+}
+
+// load returns the closes of ticker sorted by date, reading its file the first time
+func (p *csvPriceProvider) load(ticker string) ([]DailyClose, error) {
+	ticker = strings.ToUpper(ticker)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if closes, ok := p.closes[ticker]; ok {
+		return closes, nil
+	}
+
+	closes, err := readOHLCFile(filepath.Join(p.dir, ticker+".csv"))
+	if errors.Is(err, os.ErrNotExist) {
+		p.logger.Info("No price file for ticker", "ticker", ticker, "dir", p.dir)
+		closes, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.closes[ticker] = closes
+	return closes, nil
+}
+
+func readOHLCFile(path string) ([]DailyClose, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header of %s: %w", path, err)
+	}
+
+	dateColumn, closeColumn := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF"))) {
+		case "date":
+			dateColumn = i
+		case "close":
+			closeColumn = i
+		}
+	}
+	if dateColumn < 0 || closeColumn < 0 {
+		return nil, fmt.Errorf("%s needs a Date and a Close column", path)
+	}
+
+	var closes []DailyClose
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if len(record) <= dateColumn || len(record) <= closeColumn {
+			return nil, fmt.Errorf("%s line %d: missing Date or Close", path, line)
+		}
+
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(record[dateColumn]))
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid date %q", path, line, record[dateColumn])
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[closeColumn]), 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("%s line %d: invalid close %q", path, line, record[closeColumn])
+		}
+
+		closes = append(closes, DailyClose{Date: date, Close: value})
+	}
+
+	sort.Slice(closes, func(i, j int) bool { return closes[i].Date.Before(closes[j].Date) })
+	return closes, nil
+}
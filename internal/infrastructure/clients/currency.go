@@ -0,0 +1,115 @@
+package clients
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// currencySymbols maps the symbols brokers write targets with to ISO 4217 codes. Prefixed dollar signs come
+// before the bare one so "HK$" is not read as "$".
+var currencySymbols = []struct {
+	symbol string
+	code   string
+}{
+	{"US$", "USD"}, {"CA$", "CAD"}, {"C$", "CAD"}, {"AU$", "AUD"}, {"A$", "AUD"}, {"NZ$", "NZD"},
+	{"HK$", "HKD"}, {"S$", "SGD"}, {"MX$", "MXN"}, {"R$", "BRL"}, {"$", "USD"},
+	{"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"₹", "INR"}, {"₩", "KRW"}, {"₣", "CHF"},
+}
+
+// minorUnits maps the codes of currencies quoted in their minor unit, like London listings in pence,
+// to their major currency and the number of minor units in one major unit
+var minorUnits = map[string]struct {
+	code    string
+	divisor float64
+}{
+	"GBp": {"GBP", 100}, "GBX": {"GBP", 100},
+	"ZAc": {"ZAR", 100}, "ZAC": {"ZAR", 100},
+	"ILA": {"ILS", 100},
+}
+
+// parseMoney parses a target such as "$45.00", "€45", "45.00 EUR" or "GBp 1,250", telling whether the text
+// held a price. The currency is the ISO 4217 code of the symbol or code found, or empty when there is none;
+// prices quoted in minor units are converted into their major currency.
+func parseMoney(text string) (float64, string, bool) {
+	cleaned := strings.TrimSpace(text)
+	if cleaned == "" {
+		return 0, "", false
+	}
+
+	var currency string
+	code, rest := splitCurrencyCode(cleaned)
+	if code != "" {
+		currency, cleaned = code, rest
+	}
+	for _, s := range currencySymbols {
+		if trimmed, ok := trimAffix(cleaned, s.symbol); ok {
+			cleaned = trimmed
+			if currency == "" {
+				currency = s.code
+			}
+			break
+		}
+	}
+
+	cleaned = strings.TrimSpace(strings.ReplaceAll(cleaned, ",", ""))
+
+	var price float64
+	if _, err := fmt.Sscanf(cleaned, "%f", &price); err != nil {
+		return 0, "", false
+	}
+
+	if minor, ok := minorUnits[currency]; ok {
+		return price / minor.divisor, minor.code, true
+	}
+	return price, entities.NormalizeCurrency(currency), true
+}
+
+// splitCurrencyCode takes a three-letter currency code off the start or end of text, returning the
+// code and what is left of text. Minor-unit codes keep their case, since "GBp" is not "GBP".
+func splitCurrencyCode(text string) (string, string) {
+	runes := []rune(text)
+	if len(runes) < 4 {
+		return "", text
+	}
+
+	candidates := []struct {
+		code, rest string
+		boundary   rune
+	}{
+		{string(runes[:3]), string(runes[3:]), runes[3]},
+		{string(runes[len(runes)-3:]), string(runes[:len(runes)-3]), runes[len(runes)-4]},
+	}
+	for _, c := range candidates {
+		if unicode.IsLetter(c.boundary) || !isLetters(c.code) {
+			continue
+		}
+		if _, ok := minorUnits[c.code]; ok {
+			return c.code, c.rest
+		}
+		return strings.ToUpper(c.code), c.rest
+	}
+	return "", text
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// trimAffix removes symbol from the start or end of text
+func trimAffix(text, symbol string) (string, bool) {
+	if strings.HasPrefix(text, symbol) {
+		return strings.TrimPrefix(text, symbol), true
+	}
+	if strings.HasSuffix(text, symbol) {
+		return strings.TrimSuffix(text, symbol), true
+	}
+	return text, false
+}
@@ -0,0 +1,362 @@
+package clients
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"stock-tracker/pkg/logger"
+)
+
+// FileFormat is the record layout of a file source
+type FileFormat string
+
+const (
+	FileFormatCSV    FileFormat = "csv"
+	FileFormatNDJSON FileFormat = "ndjson"
+)
+
+// stockItemFields are the StockAPIItem fields by their JSON name, in declaration order
+var stockItemFields = []string{
+	"ticker", "target_from", "target_to", "company", "action", "brokerage", "rating_from", "rating_to", "time",
+}
+
+// ColumnMapping maps each StockAPIItem field, by its JSON name, to the CSV column or NDJSON key holding it
+type ColumnMapping map[string]string
+
+// DefaultColumnMapping expects every field under its own JSON name
+func DefaultColumnMapping() ColumnMapping {
+	mapping := make(ColumnMapping, len(stockItemFields))
+	for _, field := range stockItemFields {
+		mapping[field] = field
+	}
+	return mapping
+}
+
+// ParseColumnMapping applies overrides written as field=column pairs separated by commas on top of the defaults
+func ParseColumnMapping(spec string) (ColumnMapping, error) {
+	mapping := DefaultColumnMapping()
+	if strings.TrimSpace(spec) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected field=column", pair)
+		}
+		if _, known := mapping[field]; !known {
+			return nil, fmt.Errorf("unknown field %q in column mapping", field)
+		}
+		mapping[field] = column
+	}
+
+	return mapping, nil
+}
+
+// DetectFileFormat infers the format from the file extension, ignoring a trailing .gz
+func DetectFileFormat(path string) (FileFormat, error) {
+	switch filepath.Ext(strings.TrimSuffix(strings.ToLower(path), ".gz")) {
+	case ".csv":
+		return FileFormatCSV, nil
+	case ".ndjson", ".jsonl":
+		return FileFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("cannot infer the format of %s, expected a .csv, .ndjson or .jsonl file", path)
+	}
+}
+
+// fileSource serves the records of a local CSV or NDJSON file, optionally gzip-compressed, as pages.
+// The cursor is the number of records already served, so an interrupted import resumes where it stopped.
+type fileSource struct {
+	path     string
+	format   FileFormat
+	mapping  ColumnMapping
+	pageSize int
+	logger   logger.Logger
+
+	file    *os.File
+	reader  recordReader
+	offset  int
+	pending map[string]string
+}
+
+// NewFileSource creates a StockSource reading path page by page
+func NewFileSource(path string, format FileFormat, mapping ColumnMapping, pageSize int, logger logger.Logger) (StockSource, error) {
+	if format != FileFormatCSV && format != FileFormatNDJSON {
+		return nil, fmt.Errorf("unsupported file format %q", format)
+	}
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("page size must be positive, got %d", pageSize)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return nil, fmt.Errorf("failed to open source file: %w", err)
+	}
+
+	return &fileSource{
+		path:     absPath,
+		format:   format,
+		mapping:  mapping,
+		pageSize: pageSize,
+		logger:   logger,
+	}, nil
+}
+
+// Name identifies the source by its absolute path, so each file keeps its own checkpoints
+func (s *fileSource) Name() string {
+	return "file:" + s.path
+}
+
+// NewestFirst reports that file records carry no ordering guarantee
+func (s *fileSource) NewestFirst() bool {
+	return false
+}
+
+// FetchRawPage reads up to pageSize records starting at the record offset in nextPage
+func (s *fileSource) FetchRawPage(ctx context.Context, nextPage string) (*StockAPIResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if nextPage != "" {
+		var err error
+		if start, err = strconv.Atoi(nextPage); err != nil || start < 0 {
+			return nil, fmt.Errorf("invalid file cursor %q", nextPage)
+		}
+	}
+
+	if err := s.seek(start); err != nil {
+		return nil, err
+	}
+
+	response := &StockAPIResponse{Items: make([]StockAPIItem, 0, s.pageSize)}
+	for len(response.Items) < s.pageSize {
+		record, err := s.next()
+		if errors.Is(err, io.EOF) {
+			s.finish()
+			return response, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		response.Items = append(response.Items, s.toItem(record))
+	}
+
+	// Peek ahead so the last page is reported as such instead of being followed by an empty one
+	record, err := s.next()
+	if errors.Is(err, io.EOF) {
+		s.finish()
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.pending = record
+	s.offset--
+
+	response.NextPage = strconv.Itoa(s.offset)
+	return response, nil
+}
+
+// seek positions the reader at record offset start, reopening the file unless reading sequentially
+func (s *fileSource) seek(start int) error {
+	if s.reader != nil && s.offset == start {
+		return nil
+	}
+
+	s.close()
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	for s.offset < start {
+		if _, err := s.next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("file cursor %d is past the end of %s", start, s.path)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fileSource) open() error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to open source file: %w", err)
+	}
+
+	var input io.Reader = file
+	if strings.HasSuffix(strings.ToLower(s.path), ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to decompress %s: %w", s.path, err)
+		}
+		input = gz
+	}
+
+	if s.format == FileFormatCSV {
+		s.reader, err = newCSVRecordReader(input)
+	} else {
+		s.reader = newNDJSONRecordReader(input)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	s.file = file
+	s.offset = 0
+	s.pending = nil
+	return nil
+}
+
+func (s *fileSource) finish() {
+	s.logger.Info("Reached the end of source file", "path", s.path, "records", s.offset)
+	s.close()
+}
+
+func (s *fileSource) close() {
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = nil
+	s.reader = nil
+	s.pending = nil
+}
+
+// next returns the record at offset and moves past it
+func (s *fileSource) next() (map[string]string, error) {
+	if s.pending != nil {
+		record := s.pending
+		s.pending = nil
+		s.offset++
+		return record, nil
+	}
+
+	record, err := s.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read record %d of %s: %w", s.offset+1, s.path, err)
+	}
+
+	s.offset++
+	return record, nil
+}
+
+func (s *fileSource) toItem(record map[string]string) StockAPIItem {
+	field := func(name string) string {
+		return strings.TrimSpace(record[s.mapping[name]])
+	}
+
+	return StockAPIItem{
+		Ticker:     field("ticker"),
+		TargetFrom: field("target_from"),
+		TargetTo:   field("target_to"),
+		Company:    field("company"),
+		Action:     field("action"),
+		Brokerage:  field("brokerage"),
+		RatingFrom: field("rating_from"),
+		RatingTo:   field("rating_to"),
+		Time:       field("time"),
+	}
+}
+
+// recordReader yields the records of a file as column or key to value maps, and io.EOF after the last one
+type recordReader interface {
+	Read() (map[string]string, error)
+}
+
+type csvRecordReader struct {
+	reader *csv.Reader
+	header []string
+}
+
+// newCSVRecordReader reads the header row; rows with missing columns leave those fields empty
+func newCSVRecordReader(input io.Reader) (*csvRecordReader, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	return &csvRecordReader{reader: reader, header: header}, nil
+}
+
+func (r *csvRecordReader) Read() (map[string]string, error) {
+	row, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	record := make(map[string]string, len(r.header))
+	for i, column := range r.header {
+		if i < len(row) {
+			record[column] = row[i]
+		}
+	}
+	return record, nil
+}
+
+type ndjsonRecordReader struct {
+	reader *bufio.Reader
+}
+
+func newNDJSONRecordReader(input io.Reader) *ndjsonRecordReader {
+	return &ndjsonRecordReader{reader: bufio.NewReader(input)}
+}
+
+// Read decodes the next non-blank line; numbers and booleans are kept in their JSON text form
+func (r *ndjsonRecordReader) Read() (map[string]string, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var values map[string]json.RawMessage
+		if decodeErr := json.Unmarshal(line, &values); decodeErr != nil {
+			return nil, fmt.Errorf("invalid JSON line: %w", decodeErr)
+		}
+
+		record := make(map[string]string, len(values))
+		for key, raw := range values {
+			var text string
+			if json.Unmarshal(raw, &text) != nil && string(raw) != "null" {
+				text = string(raw)
+			}
+			record[key] = text
+		}
+		return record, nil
+	}
+}
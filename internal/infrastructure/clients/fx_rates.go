@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// LoadFXRates reads exchange rates from CSV files into one table. Each file has a header row with Base,
+// Quote and Rate columns, where Rate is the amount of Quote one unit of Base buys (USD,EUR,0.92); other
+// columns are ignored. Files are read in order, so a later file overrides the rates of an earlier one.
+func LoadFXRates(paths []string) (*entities.FXTable, error) {
+	table := entities.NewFXTable()
+	for _, path := range paths {
+		if err := readFXFile(path, table); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+func readFXFile(path string, table *entities.FXTable) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open exchange rates: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read header of %s: %w", path, err)
+	}
+
+	baseColumn, quoteColumn, rateColumn := -1, -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF"))) {
+		case "base":
+			baseColumn = i
+		case "quote":
+			quoteColumn = i
+		case "rate":
+			rateColumn = i
+		}
+	}
+	if baseColumn < 0 || quoteColumn < 0 || rateColumn < 0 {
+		return fmt.Errorf("%s needs a Base, a Quote and a Rate column", path)
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if len(record) <= baseColumn || len(record) <= quoteColumn || len(record) <= rateColumn {
+			return fmt.Errorf("%s line %d: missing Base, Quote or Rate", path, line)
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[rateColumn]), 64)
+		if err != nil {
+			return fmt.Errorf("%s line %d: invalid rate %q", path, line, record[rateColumn])
+		}
+		if err := table.Set(record[baseColumn], record[quoteColumn], rate); err != nil {
+			return fmt.Errorf("%s line %d: %w", path, line, err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"stock-tracker/pkg/logger"
+)
+
+// httpPriceProvider is a starting point for a hosted price vendor. It requests
+// GET <baseURL>/<TICKER>/daily?from=YYYY-MM-DD&to=YYYY-MM-DD with the API key as a bearer token and
+// expects {"closes": [{"date": "YYYY-MM-DD", "close": 123.45}]}; adapt request and dailyClosesResponse
+// to the vendor's actual API when one is chosen.
+type httpPriceProvider struct {
+	name    string
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	logger  logger.Logger
+}
+
+type dailyClosesResponse struct {
+	Closes []struct {
+		Date  string  `json:"date"`
+		Close float64 `json:"close"`
+	} `json:"closes"`
+}
+
+// NewHTTPPriceProvider creates a PriceProvider for the vendor at baseURL
+func NewHTTPPriceProvider(name, baseURL, apiKey string, logger logger.Logger) PriceProvider {
+	return &httpPriceProvider{
+		name:    name,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		logger:  logger,
+	}
+}
+
+func (p *httpPriceProvider) Name() string {
+	return p.name
+}
+
+// DailyCloses requests the closes of ticker within [from, to]. A ticker the vendor does not know has no closes.
+func (p *httpPriceProvider) DailyCloses(ctx context.Context, ticker string, from, to time.Time) ([]DailyClose, error) {
+	query := url.Values{}
+	query.Set("from", from.Format(time.DateOnly))
+	query.Set("to", to.Format(time.DateOnly))
+	endpoint := fmt.Sprintf("%s/%s/daily?%s", p.baseURL, url.PathEscape(strings.ToUpper(ticker)), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request prices: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, time.Now())
+	}
+
+	var body dailyClosesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode prices: %w", err)
+	}
+
+	closes := make([]DailyClose, 0, len(body.Closes))
+	for _, item := range body.Closes {
+		date, err := time.Parse(time.DateOnly, item.Date)
+		if err != nil || item.Close <= 0 {
+			p.logger.Warn("Skipping invalid daily close", "ticker", ticker, "date", item.Date)
+			continue
+		}
+		closes = append(closes, DailyClose{Date: date, Close: item.Close})
+	}
+
+	return closes, nil
+}
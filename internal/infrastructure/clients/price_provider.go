@@ -0,0 +1,27 @@
+package clients
+
+import (
+	"context"
+	"time"
+)
+
+// DailyClose is the closing price of a ticker on one trading day
+type DailyClose struct {
+	// Date is the trading day, at midnight UTC
+	Date  time.Time
+	Close float64
+}
+
+// PriceProvider serves historical daily closing prices
+type PriceProvider interface {
+	// Name identifies the provider in logs
+	Name() string
+	// DailyCloses returns the closes of ticker on the trading days within [from, to], both at midnight UTC.
+	// Days the market was closed have no close; a ticker the provider does not know has none at all.
+	DailyCloses(ctx context.Context, ticker string, from, to time.Time) ([]DailyClose, error)
+}
+
+// tradingDay truncates a date to midnight UTC, the form DailyClose dates are compared in
+func tradingDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,26 @@
+package clients
+
+import (
+	"fmt"
+	"os"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// LoadQualityRules reads the data-quality rules a QUALITY_RULES setting names: "off" disables the checks and
+// returns nil, "default" or an empty setting selects the built-in rules, and anything else is the path of a
+// rules file.
+func LoadQualityRules(setting string) (*entities.QualityRuleSet, error) {
+	switch setting {
+	case "off":
+		return nil, nil
+	case "default", "":
+		return entities.NewQualityRuleSet(entities.DefaultQualityRules())
+	}
+
+	data, err := os.ReadFile(setting)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quality rules: %w", err)
+	}
+	return entities.ParseQualityRules(data)
+}
@@ -0,0 +1,324 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	// ErrUnauthorized is returned when the upstream rejects the API key; retrying cannot help
+	ErrUnauthorized = errors.New("upstream rejected the API credentials")
+	// ErrRateLimited is returned when the upstream kept answering 429 after every retry
+	ErrRateLimited = errors.New("upstream rate limit exceeded")
+	// ErrUpstreamUnavailable is returned when the upstream kept failing, or the circuit breaker is open
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+)
+
+// StatusError reports an upstream response other than 200. It matches ErrUnauthorized, ErrRateLimited or
+// ErrUpstreamUnavailable with errors.Is when the status falls in one of those classes.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	kind       error
+}
+
+func (e *StatusError) Error() string {
+	if e.kind == nil {
+		return fmt.Sprintf("API returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API returned status %d: %v", e.StatusCode, e.kind)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.kind
+}
+
+// retryable reports if the same request may succeed later
+func (e *StatusError) retryable() bool {
+	return errors.Is(e.kind, ErrRateLimited) || errors.Is(e.kind, ErrUpstreamUnavailable)
+}
+
+func newStatusError(resp *http.Response, now time.Time) *StatusError {
+	err := &StatusError{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header, now)}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		err.kind = ErrUnauthorized
+	case resp.StatusCode == http.StatusTooManyRequests:
+		err.kind = ErrRateLimited
+	case resp.StatusCode >= http.StatusInternalServerError:
+		err.kind = ErrUpstreamUnavailable
+	}
+	return err
+}
+
+// RequestPolicy tunes how the client paces requests, retries them and stops calling a failing upstream
+type RequestPolicy struct {
+	// MinInterval spaces requests while the upstream sends no rate-limit headers
+	MinInterval time.Duration
+	// MaxRetries is the number of retries after the first attempt of a request
+	MaxRetries int
+	// MinBackoff and MaxBackoff bound the exponential backoff between retries
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// BreakerThreshold consecutive 5xx responses open the circuit for BreakerCooldown
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// DefaultRequestPolicy returns the policy used by NewStockAPIClient
+func DefaultRequestPolicy() RequestPolicy {
+	return RequestPolicy{
+		MinInterval:      100 * time.Millisecond,
+		MaxRetries:       3,
+		MinBackoff:       1 * time.Second,
+		MaxBackoff:       30 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// backoff returns the delay before retry attempt (counted from 1): a Retry-After the upstream asked for
+// is honored as is, otherwise the delay is drawn uniformly up to an exponentially growing cap.
+func (p RequestPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	ceiling := p.MinBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > p.MaxBackoff {
+		ceiling = p.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// pacer is a token bucket whose rate follows the rate-limit headers of the upstream. It starts at one
+// request per MinInterval and is slowed down to spread the remaining quota over the rest of the window.
+type pacer struct {
+	limiter *rate.Limiter
+	maxRate rate.Limit
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+func newPacer(minInterval time.Duration) *pacer {
+	maxRate := rate.Inf
+	if minInterval > 0 {
+		maxRate = rate.Every(minInterval)
+	}
+	return &pacer{limiter: rate.NewLimiter(maxRate, 1), maxRate: maxRate}
+}
+
+// wait blocks until the next request may be sent
+func (p *pacer) wait(ctx context.Context) error {
+	p.mu.Lock()
+	paused := time.Until(p.pausedUntil)
+	p.mu.Unlock()
+
+	if err := sleep(ctx, paused); err != nil {
+		return err
+	}
+
+	reservation := p.limiter.Reserve()
+	if err := sleep(ctx, reservation.Delay()); err != nil {
+		reservation.Cancel()
+		return err
+	}
+	return nil
+}
+
+// pause holds every request until the given time
+func (p *pacer) pause(until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if until.After(p.pausedUntil) {
+		p.pausedUntil = until
+	}
+}
+
+// observe adapts the rate to the remaining quota and reset time advertised by a response, if any
+func (p *pacer) observe(header http.Header, now time.Time) {
+	remaining, ok := headerInt(header, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	if !ok {
+		return
+	}
+	reset, ok := parseRateLimitReset(header, now)
+	if !ok {
+		return
+	}
+
+	if remaining <= 0 {
+		p.pause(reset)
+		return
+	}
+
+	window := reset.Sub(now)
+	if window <= 0 {
+		p.limiter.SetLimit(p.maxRate)
+		return
+	}
+
+	limit := rate.Limit(float64(remaining) / window.Seconds())
+	if limit > p.maxRate {
+		limit = p.maxRate
+	}
+	p.limiter.SetLimit(limit)
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker stops calling an upstream that keeps failing. After threshold consecutive failures it
+// opens and rejects requests for cooldown; then it lets a single trial request through, which closes it
+// again on any answer but a 5xx and reopens it on a 5xx or transport error. A trial abandoned before the
+// upstream could answer leaves the circuit open but lets the next request try again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports if a request may be sent, failing with ErrUpstreamUnavailable while the circuit is open.
+// It returns true when the request is the trial of a half-open circuit, which must be resolved by
+// success, failure or abandon.
+func (b *circuitBreaker) allow(now time.Time) (bool, error) {
+	if b.threshold <= 0 {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		reopensAt := b.openedAt.Add(b.cooldown)
+		if now.Before(reopensAt) {
+			return false, fmt.Errorf("%w: circuit breaker open until %s", ErrUpstreamUnavailable, reopensAt.Format(time.RFC3339))
+		}
+		b.state = breakerHalfOpen
+		return true, nil
+	case breakerHalfOpen:
+		return false, fmt.Errorf("%w: circuit breaker waiting for a trial request", ErrUpstreamUnavailable)
+	default:
+		return false, nil
+	}
+}
+
+// abandon reopens the circuit after a trial that never got an answer from the upstream, keeping the time
+// it opened so the next request becomes the trial
+func (b *circuitBreaker) abandon(trial bool) {
+	if !trial {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+// success records an answer from the upstream, closing the circuit
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+// failure counts a failed request, returning true if it opened the circuit
+func (b *circuitBreaker) failure(now time.Time) bool {
+	if b.threshold <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= b.threshold) {
+		b.state = breakerOpen
+		b.openedAt = now
+		return true
+	}
+	return false
+}
+
+// sleep waits for d unless ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// parseRateLimitReset reads when the rate-limit window resets. Vendors send either the seconds left
+// in the window or a Unix timestamp; values too large to be a delay are taken as timestamps.
+func parseRateLimitReset(header http.Header, now time.Time) (time.Time, bool) {
+	value, ok := headerInt(header, "X-RateLimit-Reset", "RateLimit-Reset")
+	if !ok || value < 0 {
+		return time.Time{}, false
+	}
+	if value > 1_000_000_000 {
+		return time.Unix(int64(value), 0), true
+	}
+	return now.Add(time.Duration(value) * time.Second), true
+}
+
+func headerInt(header http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		if value := header.Get(name); value != "" {
+			number, err := strconv.Atoi(value)
+			return number, err == nil
+		}
+	}
+	return 0, false
+}
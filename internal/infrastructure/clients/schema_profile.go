@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// ProfilePage adds the items of a page to profile. Keys and nulls are read from the raw items when the page
+// kept them, as in StockAPIResponse.RawItems; items read from files are profiled as decoded, which only tells
+// empty fields apart.
+func ProfilePage(profile *entities.SchemaProfile, items []StockAPIItem, rawItems []json.RawMessage) {
+	for i, item := range items {
+		profile.Records++
+
+		if i < len(rawItems) {
+			profileRawItem(profile, rawItems[i])
+		} else {
+			values := item.fieldValues()
+			for _, field := range stockItemFields {
+				if values[field] == "" {
+					profile.RecordMissing(field)
+				}
+			}
+		}
+
+		if item.Time != "" {
+			_, err := time.Parse(time.RFC3339, item.Time)
+			profile.RecordTime(err == nil)
+		}
+		for _, price := range []string{item.TargetFrom, item.TargetTo} {
+			if price != "" {
+				_, _, parsed := parseMoney(price)
+				profile.RecordPrice(parsed)
+			}
+		}
+	}
+}
+
+// profileRawItem records the unknown keys of an item as received, and the known fields it leaves absent, null or empty
+func profileRawItem(profile *entities.SchemaProfile, raw json.RawMessage) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return
+	}
+
+	known := make(map[string]bool, len(stockItemFields))
+	for _, field := range stockItemFields {
+		known[field] = true
+
+		value, present := values[field]
+		value = bytes.TrimSpace(value)
+		if !present || bytes.Equal(value, []byte("null")) || bytes.Equal(value, []byte(`""`)) {
+			profile.RecordMissing(field)
+		}
+	}
+
+	for key := range values {
+		if !known[key] {
+			profile.RecordUnknownKey(key)
+		}
+	}
+}
+
+// fieldValues returns the fields of the item by their JSON name
+func (item StockAPIItem) fieldValues() map[string]string {
+	return map[string]string{
+		"ticker":      item.Ticker,
+		"target_from": item.TargetFrom,
+		"target_to":   item.TargetTo,
+		"company":     item.Company,
+		"action":      item.Action,
+		"brokerage":   item.Brokerage,
+		"rating_from": item.RatingFrom,
+		"rating_to":   item.RatingTo,
+		"time":        item.Time,
+	}
+}
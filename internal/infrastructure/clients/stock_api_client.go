@@ -3,21 +3,46 @@ package clients
 import (
 	"context"
 	"encoding/json"
-
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
+	"net/url"
 	"time"
 
 	"stock-tracker/internal/domain/entities"
 	"stock-tracker/pkg/logger"
-
-	"github.com/hashicorp/go-retryablehttp"
 )
 
 type StockAPIResponse struct {
 	Items    []StockAPIItem `json:"items"`
 	NextPage string         `json:"next_page"`
+
+	// RawItems holds each item as received, when the page was decoded from JSON, so its shape can be profiled
+	RawItems []json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a page like the default decoding, keeping each item as received in RawItems
+func (r *StockAPIResponse) UnmarshalJSON(data []byte) error {
+	var page struct {
+		Items    []json.RawMessage `json:"items"`
+		NextPage string            `json:"next_page"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return err
+	}
+
+	var items []StockAPIItem
+	if page.Items != nil {
+		items = make([]StockAPIItem, len(page.Items))
+		for i, raw := range page.Items {
+			if err := json.Unmarshal(raw, &items[i]); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+	}
+
+	r.Items, r.NextPage, r.RawItems = items, page.NextPage, page.Items
+	return nil
 }
 
 type StockAPIItem struct {
@@ -33,34 +58,55 @@ type StockAPIItem struct {
 }
 
 type StockAPIClient interface {
+	StockSource
 	FetchAllStocks(ctx context.Context) ([]*entities.Stock, error)
 	FetchPage(ctx context.Context, nextPage string) ([]*entities.Stock, string, error)
 }
 
 type stockAPIClient struct {
-	client    *retryablehttp.Client
-	baseURL   string
-	apiKey    string
-	logger    logger.Logger
-	rateLimit time.Duration
+	name    string
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	logger  logger.Logger
+	policy  RequestPolicy
+	pacer   *pacer
+	breaker *circuitBreaker
 }
 
 func NewStockAPIClient(baseURL, apiKey string, logger logger.Logger) StockAPIClient {
-	retryClient := retryablehttp.NewClient()
-	retryClient.RetryMax = 3
-	retryClient.RetryWaitMin = 1 * time.Second
-	retryClient.RetryWaitMax = 5 * time.Second
-	retryClient.Logger = nil // Disable logging for retryablehttp, because we have our own logger
+	return NewVendorStockAPIClient("api", baseURL, apiKey, logger)
+}
 
+// NewVendorStockAPIClient creates a client for one of several vendors serving the same API, identified by name
+func NewVendorStockAPIClient(name, baseURL, apiKey string, logger logger.Logger) StockAPIClient {
+	return NewStockAPIClientWithPolicy(name, baseURL, apiKey, DefaultRequestPolicy(), logger)
+}
+
+// NewStockAPIClientWithPolicy creates a vendor client that paces, retries and trips its circuit breaker per policy
+func NewStockAPIClientWithPolicy(name, baseURL, apiKey string, policy RequestPolicy, logger logger.Logger) StockAPIClient {
 	return &stockAPIClient{
-		client:    retryClient,
-		baseURL:   baseURL,
-		apiKey:    apiKey,
-		logger:    logger,
-		rateLimit: 100 * time.Millisecond,
+		name:    name,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		logger:  logger,
+		policy:  policy,
+		pacer:   newPacer(policy.MinInterval),
+		breaker: newCircuitBreaker(policy.BreakerThreshold, policy.BreakerCooldown),
 	}
 }
 
+// Name identifies the HTTP vendor as a source
+func (c *stockAPIClient) Name() string {
+	return c.name
+}
+
+// NewestFirst reports that the vendor pages events from newest to oldest
+func (c *stockAPIClient) NewestFirst() bool {
+	return true
+}
+
 func (c *stockAPIClient) FetchAllStocks(ctx context.Context) ([]*entities.Stock, error) {
 	var allStocks []*entities.Stock
 	nextPage := ""
@@ -89,10 +135,6 @@ func (c *stockAPIClient) FetchAllStocks(ctx context.Context) ([]*entities.Stock,
 			break
 		}
 		nextPage = next
-
-		//Rate limit
-		time.Sleep(c.rateLimit)
-
 	}
 
 	c.logger.Info("Completed stock data ingestion", "total_stocks", len(allStocks), "pages", pageCount)
@@ -100,14 +142,73 @@ func (c *stockAPIClient) FetchAllStocks(ctx context.Context) ([]*entities.Stock,
 }
 
 func (c *stockAPIClient) FetchPage(ctx context.Context, nextPage string) ([]*entities.Stock, string, error) {
-	url := c.baseURL
+	apiResponse, err := c.FetchRawPage(ctx, nextPage)
+	if err != nil {
+		return nil, "", err
+	}
+
+	stocks := make([]*entities.Stock, 0, len(apiResponse.Items))
+	for _, item := range apiResponse.Items {
+		stock, err := ConvertAPIItem(item)
+		if err != nil {
+			c.logger.Warn("Failed to convert API item to stock", "ticker", item.Ticker, "error", err)
+			continue
+		}
+		stocks = append(stocks, stock)
+	}
+	return stocks, apiResponse.NextPage, nil
+}
+
+// FetchRawPage fetches a single page without converting its items, leaving conversion to the caller.
+// Transport errors, 429 and 5xx responses are retried with backoff; the error returned once retries run
+// out, or for a response that cannot succeed, matches one of the typed errors of this package.
+func (c *stockAPIClient) FetchRawPage(ctx context.Context, nextPage string) (*StockAPIResponse, error) {
+	requestURL := c.baseURL
 	if nextPage != "" {
-		url += "?next_page=" + nextPage
+		// The cursor is opaque and may hold characters with a meaning in a query string
+		requestURL += "?" + url.Values{"next_page": {nextPage}}.Encode()
 	}
 
-	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	for attempt := 0; ; attempt++ {
+		response, retryAfter, err := c.fetchOnce(ctx, requestURL)
+		if err == nil || retryAfter < 0 || ctx.Err() != nil {
+			return response, err
+		}
+
+		if attempt >= c.policy.MaxRetries {
+			return nil, err
+		}
+		// Waiting longer than the backoff allows is left to the next run, which resumes from the checkpoint
+		if retryAfter > c.policy.MaxBackoff {
+			return nil, err
+		}
+
+		delay := c.policy.backoff(attempt+1, retryAfter)
+		c.logger.Warn("Retrying upstream request", "attempt", attempt+1, "error", err)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// fetchOnce sends a single paced request. On failure it also returns the Retry-After the upstream asked
+// for, zero if none, or a negative duration if the request must not be retried.
+func (c *stockAPIClient) fetchOnce(ctx context.Context, url string) (*StockAPIResponse, time.Duration, error) {
+	trial, err := c.breaker.allow(time.Now())
+	if err != nil {
+		return nil, -1, err
+	}
+
+	//Rate limit
+	if err := c.pacer.wait(ctx); err != nil {
+		c.breaker.abandon(trial)
+		return nil, -1, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+		c.breaker.abandon(trial)
+		return nil, -1, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
@@ -116,33 +217,63 @@ func (c *stockAPIClient) FetchPage(ctx context.Context, nextPage string) ([]*ent
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to make request: %w", err)
+		if ctx.Err() != nil {
+			c.breaker.abandon(trial)
+			return nil, -1, fmt.Errorf("failed to make request: %w", err)
+		}
+		c.upstreamFailed(time.Now())
+		return nil, 0, fmt.Errorf("failed to make request: %w: %w", ErrUpstreamUnavailable, err)
 	}
 
 	defer resp.Body.Close()
 
+	now := time.Now()
+	c.pacer.observe(resp.Header, now)
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("API returned status %d", resp.StatusCode)
+		statusErr := newStatusError(resp, now)
+
+		switch {
+		case errors.Is(statusErr, ErrUpstreamUnavailable):
+			c.upstreamFailed(now)
+		case errors.Is(statusErr, ErrRateLimited):
+			// Every request waits out the limit, not only the retry of this one
+			if statusErr.RetryAfter > 0 {
+				c.pacer.pause(now.Add(statusErr.RetryAfter))
+			}
+			c.breaker.success()
+		default:
+			// The upstream answered, so it is up even if it turned this request down
+			c.breaker.success()
+		}
+
+		if !statusErr.retryable() {
+			return nil, -1, statusErr
+		}
+		return nil, statusErr.RetryAfter, statusErr
 	}
 
+	c.breaker.success()
+
 	var apiResponse StockAPIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, -1, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	stocks := make([]*entities.Stock, 0, len(apiResponse.Items))
-	for _, item := range apiResponse.Items {
-		stock, err := c.convertAPIItemToStock(item)
-		if err != nil {
-			c.logger.Warn("Failed to convert API item to stock", "ticker", item.Ticker, "error", err)
-		}
-		stocks = append(stocks, stock)
-	}
-	return stocks, apiResponse.NextPage, nil
+	return &apiResponse, 0, nil
 }
 
-func (c *stockAPIClient) convertAPIItemToStock(item StockAPIItem) (*entities.Stock, error) {
+// upstreamFailed counts a transport error or 5xx response towards opening the circuit breaker
+func (c *stockAPIClient) upstreamFailed(now time.Time) {
+	if c.breaker.failure(now) {
+		c.logger.Warn("Opened circuit breaker for upstream", "source", c.name, "cooldown", c.policy.BreakerCooldown)
+	}
+}
 
+// ConvertAPIItem maps a raw upstream item to a stock, failing if its event time cannot be parsed or its
+// targets are quoted in different currencies. Targets without a currency take that of the other target,
+// or DefaultCurrency when neither has one.
+func ConvertAPIItem(item StockAPIItem) (*entities.Stock, error) {
 	eventTime, err := time.Parse(time.RFC3339, item.Time)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse event time %s: %w", item.Time, err)
@@ -153,30 +284,22 @@ func (c *stockAPIClient) convertAPIItemToStock(item StockAPIItem) (*entities.Sto
 	stock.RatingTo = item.RatingTo
 
 	// Parse the target prices
-	if targetFrom := c.parsePrice(item.TargetFrom); targetFrom > 0 {
+	targetFrom, fromCurrency, _ := parseMoney(item.TargetFrom)
+	targetTo, toCurrency, _ := parseMoney(item.TargetTo)
+	if fromCurrency != "" && toCurrency != "" && fromCurrency != toCurrency {
+		return nil, fmt.Errorf("targets %q and %q are quoted in different currencies", item.TargetFrom, item.TargetTo)
+	}
+	if targetFrom > 0 {
 		stock.TargetFrom = targetFrom
 	}
-	if targetTo := c.parsePrice(item.TargetTo); targetTo > 0 {
+	if targetTo > 0 {
 		stock.TargetTo = targetTo
 	}
-
-	return stock, nil
-}
-
-func (c *stockAPIClient) parsePrice(priceStr string) float64 {
-	if priceStr == "" {
-		return 0
+	if toCurrency != "" {
+		stock.Currency = toCurrency
+	} else if fromCurrency != "" {
+		stock.Currency = fromCurrency
 	}
 
-	// Remove currency symbols and spaces
-	cleaned := strings.ReplaceAll(priceStr, "$", "")
-	cleaned = strings.ReplaceAll(cleaned, ",", "")
-	cleaned = strings.TrimSpace(cleaned)
-
-	var price float64
-	if _, err := fmt.Sscanf(cleaned, "%f", &price); err != nil {
-		return 0
-	}
-
-	return price
+	return stock, nil
 }
@@ -0,0 +1,18 @@
+package clients
+
+import "context"
+
+// StockSource is a paginated feed of raw analyst events that the ingestion pipeline can read.
+// Pages are addressed by an opaque cursor: the empty cursor is the first page, and a page whose
+// NextPage is empty is the last one.
+type StockSource interface {
+	// Name identifies the source; each source keeps its own ingestion checkpoints
+	Name() string
+
+	// NewestFirst reports whether events are served from newest to oldest, which lets
+	// ingestion stop as soon as it reaches events that were already stored
+	NewestFirst() bool
+
+	// FetchRawPage fetches the page at cursor without converting its items
+	FetchRawPage(ctx context.Context, nextPage string) (*StockAPIResponse, error)
+}
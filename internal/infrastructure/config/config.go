@@ -1,11 +1,20 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// StockAPIVendor is one upstream vendor of analyst events
+type StockAPIVendor struct {
+	Name   string
+	URL    string
+	APIKey string
+}
+
 type Config struct {
 	// Database
 	DatabaseURL string
@@ -14,6 +23,45 @@ type Config struct {
 	StockAPIURL string
 	StockAPIKey string
 
+	// Multi-vendor ingestion
+	StockAPIVendors     []StockAPIVendor
+	StockSourcePriority []string
+	StockDedupWindow    time.Duration
+
+	// Ingestion schedule, as a standard five-field cron expression
+	IngestionSchedule string
+
+	// Leader election between ingestor replicas
+	LeaderElectionEnabled bool
+	LeaderLeaseTTL        time.Duration
+
+	// Close-price enrichment: PriceProvider is "csv", "http" or empty to disable it
+	PriceProvider  string
+	PriceCSVDir    string
+	PriceAPIURL    string
+	PriceAPIKey    string
+	MarketTimezone string
+
+	// Exchange rates used to show targets in a display currency: CSV files of Base, Quote and Rate columns
+	FXRatesFiles []string
+
+	// Upstream schema drift: SchemaDriftMode is "off", "warn" to flag drifting runs or "fail" to stop them.
+	// Rates are shares between 0 and 1.
+	SchemaDriftMode             string
+	SchemaDriftMinRecords       int
+	SchemaDriftUnknownKeyRate   float64
+	SchemaDriftMissingRate      float64
+	SchemaDriftParseFailureRate float64
+
+	// Data-quality rules checked on ingested and re-submitted stocks: "off", "default" for the built-in rules,
+	// or the path of a JSON file holding the rules
+	QualityRules string
+
+	// Delivery of the domain events in the outbox: how often it is polled, and how many deliveries
+	// an event gets before it is marked failed
+	OutboxPollInterval time.Duration
+	OutboxMaxAttempts  int
+
 	// Server
 	LogLevel string
 	Port     string
@@ -33,13 +81,62 @@ type Config struct {
 }
 
 func LoadConfig() (*Config, error) {
+	stockAPIURL := getEnv("STOCK_API_URL", "https://api.example.com/stocks")
+	stockAPIKey := getEnv("STOCK_API_KEY", "")
+
+	vendors, err := loadStockAPIVendors(stockAPIURL, stockAPIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	vendorNames := make([]string, len(vendors))
+	for i, vendor := range vendors {
+		vendorNames[i] = vendor.Name
+	}
+
 	return &Config{
 		// Database
 		DatabaseURL: getEnv("DATABASE_URL", "postgres://localhost:5432/stock_system"),
 
 		// External APIs
-		StockAPIURL: getEnv("STOCK_API_URL", "https://api.example.com/stocks"),
-		StockAPIKey: getEnv("STOCK_API_KEY", ""),
+		StockAPIURL: stockAPIURL,
+		StockAPIKey: stockAPIKey,
+
+		// Multi-vendor ingestion
+		StockAPIVendors:     vendors,
+		StockSourcePriority: getListEnv("STOCK_SOURCE_PRIORITY", vendorNames),
+		StockDedupWindow:    getDurationEnv("STOCK_DEDUP_WINDOW", 6*time.Hour),
+
+		// Ingestion schedule
+		IngestionSchedule: getEnv("INGESTION_SCHEDULE", "0 * * * *"),
+
+		// Leader election
+		LeaderElectionEnabled: getBoolEnv("LEADER_ELECTION_ENABLED", true),
+		LeaderLeaseTTL:        getDurationEnv("LEADER_LEASE_TTL", 30*time.Second),
+
+		// Close-price enrichment
+		PriceProvider:  getEnv("PRICE_PROVIDER", ""),
+		PriceCSVDir:    getEnv("PRICE_CSV_DIR", "./data/prices"),
+		PriceAPIURL:    getEnv("PRICE_API_URL", ""),
+		PriceAPIKey:    getEnv("PRICE_API_KEY", ""),
+		MarketTimezone: getEnv("MARKET_TIMEZONE", "America/New_York"),
+
+		// Exchange rates
+		FXRatesFiles: getListEnv("FX_RATES_FILES", nil),
+
+		// Upstream schema drift
+		SchemaDriftMode:             getEnv("SCHEMA_DRIFT_MODE", "warn"),
+		SchemaDriftMinRecords:       getIntEnv("SCHEMA_DRIFT_MIN_RECORDS", 50),
+		SchemaDriftUnknownKeyRate:   getFloatEnv("SCHEMA_DRIFT_UNKNOWN_KEY_RATE", 0),
+		SchemaDriftMissingRate:      getFloatEnv("SCHEMA_DRIFT_MISSING_RATE", 0.2),
+		SchemaDriftParseFailureRate: getFloatEnv("SCHEMA_DRIFT_PARSE_FAILURE_RATE", 0.05),
+
+		// Data-quality rules
+		QualityRules: getEnv("QUALITY_RULES", "default"),
+
+		// Domain event delivery
+		OutboxPollInterval: getDurationEnv("OUTBOX_POLL_INTERVAL", 2*time.Second),
+		OutboxMaxAttempts:  getIntEnv("OUTBOX_MAX_ATTEMPTS", 10),
 
 		// Server
 		LogLevel: getEnv("LOG_LEVEL", "info"),
@@ -60,6 +157,49 @@ func LoadConfig() (*Config, error) {
 	}, nil
 }
 
+// loadStockAPIVendors reads the vendors named in STOCK_API_VENDORS, each configured through
+// STOCK_API_<NAME>_URL and STOCK_API_<NAME>_KEY, where NAME is the vendor name in upper case with
+// every character other than a letter or digit replaced by an underscore. Without it, the single
+// vendor is named "api".
+func loadStockAPIVendors(defaultURL, defaultKey string) ([]StockAPIVendor, error) {
+	names := getListEnv("STOCK_API_VENDORS", nil)
+	if len(names) == 0 {
+		return []StockAPIVendor{{Name: "api", URL: defaultURL, APIKey: defaultKey}}, nil
+	}
+
+	vendors := make([]StockAPIVendor, 0, len(names))
+	prefixes := make(map[string]string, len(names))
+	for _, name := range names {
+		prefix := "STOCK_API_" + vendorEnvName(name)
+		if other, ok := prefixes[prefix]; ok {
+			return nil, fmt.Errorf("stock API vendors %s and %s are both configured through %s_URL", other, name, prefix)
+		}
+		prefixes[prefix] = name
+
+		url := os.Getenv(prefix + "_URL")
+		if url == "" {
+			return nil, fmt.Errorf("%s_URL is required for stock API vendor %s", prefix, name)
+		}
+		vendors = append(vendors, StockAPIVendor{Name: name, URL: url, APIKey: os.Getenv(prefix + "_KEY")})
+	}
+
+	return vendors, nil
+}
+
+// vendorEnvName turns a vendor name into the part of an environment variable name that identifies it
+func vendorEnvName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -85,9 +225,34 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		return value == "true" || value == "1"
 	}
 	return defaultValue
 }
+
+// getListEnv reads a comma-separated list, ignoring blank entries
+func getListEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,11 +2,13 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"stock-tracker/internal/domain/entities"
 	"stock-tracker/internal/domain/repositories"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -48,6 +50,9 @@ func (r *BrokerRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*enti
 		&broker.CreatedAt, &broker.UpdatedAt,
 	)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("broker %s: %w", id, repositories.ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get broker by ID: %w", err)
 	}
@@ -67,6 +72,9 @@ func (r *BrokerRepositoryImpl) GetByName(ctx context.Context, name string) (*ent
 		&broker.CreatedAt, &broker.UpdatedAt,
 	)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("broker %s: %w", name, repositories.ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get broker by name: %w", err)
 	}
@@ -151,3 +159,158 @@ func (r *BrokerRepositoryImpl) UpsertByName(ctx context.Context, broker *entitie
 
 	return nil
 }
+
+func (r *BrokerRepositoryImpl) ListAliases(ctx context.Context) ([]*entities.BrokerAlias, error) {
+	query := `
+		SELECT id, broker_id, alias, created_at
+		FROM broker_aliases ORDER BY alias
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broker aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var aliases []*entities.BrokerAlias
+	for rows.Next() {
+		alias := &entities.BrokerAlias{}
+		if err := rows.Scan(&alias.ID, &alias.BrokerID, &alias.Alias, &alias.CreatedAt); err != nil {
+			continue
+		}
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, nil
+}
+
+func (r *BrokerRepositoryImpl) CreateAlias(ctx context.Context, alias *entities.BrokerAlias) error {
+	query := `
+		INSERT INTO broker_aliases (id, broker_id, alias, alias_key, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		alias.ID, alias.BrokerID, alias.Alias, entities.BrokerKey(alias.Alias), alias.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create broker alias: %w", translateUniqueViolation(err))
+	}
+
+	return nil
+}
+
+func (r *BrokerRepositoryImpl) DeleteAlias(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM broker_aliases WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete broker alias: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("broker alias %s: %w", id, repositories.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *BrokerRepositoryImpl) Merge(ctx context.Context, merge *entities.BrokerMerge) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the merged broker so events ingested meanwhile cannot be attributed to it
+	var mergedName string
+	err = tx.QueryRow(ctx, `SELECT name FROM brokers WHERE id = $1 FOR UPDATE`, merge.MergedID).Scan(&mergedName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("broker %s: %w", merge.MergedID, repositories.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock merged broker: %w", err)
+	}
+
+	result, err := tx.Exec(ctx,
+		`UPDATE stocks SET broker_id = $1, updated_at = $3 WHERE broker_id = $2`,
+		merge.SurvivorID, merge.MergedID, merge.MergedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to re-point stocks: %w", err)
+	}
+	merge.StocksMoved = int(result.RowsAffected())
+
+	if _, err := tx.Exec(ctx, `UPDATE stock_revisions SET broker_id = $1 WHERE broker_id = $2`, merge.SurvivorID, merge.MergedID); err != nil {
+		return fmt.Errorf("failed to re-point stock revisions: %w", err)
+	}
+
+	result, err = tx.Exec(ctx, `UPDATE broker_aliases SET broker_id = $1 WHERE broker_id = $2`, merge.SurvivorID, merge.MergedID)
+	if err != nil {
+		return fmt.Errorf("failed to move broker aliases: %w", err)
+	}
+	merge.AliasesMoved = int(result.RowsAffected())
+
+	// The merged name becomes an alias so later events published under it resolve to the survivor
+	keepName := `
+		INSERT INTO broker_aliases (id, broker_id, alias, alias_key, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (alias_key) DO UPDATE SET broker_id = EXCLUDED.broker_id
+	`
+	if _, err := tx.Exec(ctx, keepName, uuid.New(), merge.SurvivorID, mergedName, entities.BrokerKey(mergedName), merge.MergedAt); err != nil {
+		return fmt.Errorf("failed to keep merged broker name as alias: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM brokers WHERE id = $1`, merge.MergedID); err != nil {
+		return fmt.Errorf("failed to delete merged broker: %w", err)
+	}
+
+	audit := `
+		INSERT INTO broker_merges (id, survivor_id, survivor_name, merged_id, merged_name, merged_credibility_score,
+			stocks_moved, aliases_moved, reason, merged_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err = tx.Exec(ctx, audit,
+		merge.ID, merge.SurvivorID, merge.SurvivorName, merge.MergedID, merge.MergedName, merge.MergedCredibilityScore,
+		merge.StocksMoved, merge.AliasesMoved, merge.Reason, merge.MergedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store broker merge: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *BrokerRepositoryImpl) ListMerges(ctx context.Context, limit int) ([]*entities.BrokerMerge, error) {
+	query := `
+		SELECT id, survivor_id, survivor_name, merged_id, merged_name, COALESCE(merged_credibility_score, 0),
+			stocks_moved, aliases_moved, reason, merged_at
+		FROM broker_merges
+		ORDER BY merged_at DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broker merges: %w", err)
+	}
+	defer rows.Close()
+
+	var merges []*entities.BrokerMerge
+	for rows.Next() {
+		merge := &entities.BrokerMerge{}
+		err := rows.Scan(
+			&merge.ID, &merge.SurvivorID, &merge.SurvivorName, &merge.MergedID, &merge.MergedName,
+			&merge.MergedCredibilityScore, &merge.StocksMoved, &merge.AliasesMoved, &merge.Reason, &merge.MergedAt,
+		)
+		if err != nil {
+			continue
+		}
+		merges = append(merges, merge)
+	}
+
+	return merges, nil
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+type deadLetterRepository struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+// NewDeadLetterRepository creates a new instance of deadLetterRepository implementing repositories.DeadLetterRepository.
+func NewDeadLetterRepository(db *pgxpool.Pool, logger logger.Logger) repositories.DeadLetterRepository {
+	return &deadLetterRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const deadLetterColumns = `id, batch_id, source, payload, reason, status, attempts, created_at, updated_at, resolved_at`
+
+// Create inserts a newly quarantined record.
+func (r *deadLetterRepository) Create(ctx context.Context, record *entities.DeadLetterRecord) error {
+	query := `
+        INSERT INTO dead_letter_records (` + deadLetterColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `
+
+	_, err := r.db.Exec(ctx, query,
+		record.ID, record.BatchID, record.Source, []byte(record.Payload), record.Reason, record.Status,
+		record.Attempts, record.CreatedAt, record.UpdatedAt, record.ResolvedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create dead letter record", "error", err, "batch_id", record.BatchID)
+		return fmt.Errorf("failed to create dead letter record: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a quarantined record by its ID.
+func (r *deadLetterRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error) {
+	query := `
+        SELECT ` + deadLetterColumns + `
+        FROM dead_letter_records
+        WHERE id = $1
+    `
+
+	record, err := scanDeadLetter(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letter record: %w", err)
+	}
+
+	return record, nil
+}
+
+// List retrieves quarantined records from newest to oldest, optionally restricted to one status.
+func (r *deadLetterRepository) List(ctx context.Context, status entities.DeadLetterStatus, limit, offset int) ([]*entities.DeadLetterRecord, int, error) {
+	whereClause := ""
+	args := []interface{}{}
+	if status != "" {
+		whereClause = "WHERE status = $1"
+		args = append(args, status)
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM dead_letter_records ` + whereClause
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count dead letter records: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT `+deadLetterColumns+`
+        FROM dead_letter_records
+        %s
+        ORDER BY created_at DESC
+        LIMIT $%d OFFSET $%d
+    `, whereClause, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query dead letter records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*entities.DeadLetterRecord
+	for rows.Next() {
+		record, err := scanDeadLetter(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan dead letter row", "error", err)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, total, nil
+}
+
+// Update persists the payload, reason, status and attempts of a record that is still pending.
+func (r *deadLetterRepository) Update(ctx context.Context, record *entities.DeadLetterRecord) error {
+	query := `
+        UPDATE dead_letter_records
+        SET payload = $2, reason = $3, status = $4, attempts = $5, updated_at = $6, resolved_at = $7
+        WHERE id = $1 AND status = 'pending'
+    `
+
+	result, err := r.db.Exec(ctx, query,
+		record.ID, []byte(record.Payload), record.Reason, record.Status,
+		record.Attempts, record.UpdatedAt, record.ResolvedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update dead letter record", "error", err, "id", record.ID)
+		return fmt.Errorf("failed to update dead letter record: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("dead letter record %s: %w", record.ID, repositories.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanDeadLetter maps a single dead_letter_records row, translating a missing row into repositories.ErrNotFound.
+func scanDeadLetter(row pgx.Row) (*entities.DeadLetterRecord, error) {
+	record := &entities.DeadLetterRecord{}
+	var payload []byte
+
+	err := row.Scan(
+		&record.ID, &record.BatchID, &record.Source, &payload, &record.Reason, &record.Status,
+		&record.Attempts, &record.CreatedAt, &record.UpdatedAt, &record.ResolvedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record.Payload = payload
+	return record, nil
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+type ingestionCheckpointRepository struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+// NewIngestionCheckpointRepository creates a new instance of ingestionCheckpointRepository implementing repositories.IngestionCheckpointRepository.
+func NewIngestionCheckpointRepository(db *pgxpool.Pool, logger logger.Logger) repositories.IngestionCheckpointRepository {
+	return &ingestionCheckpointRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save inserts the checkpoint or overwrites the progress of an existing one.
+func (r *ingestionCheckpointRepository) Save(ctx context.Context, checkpoint *entities.IngestionCheckpoint) error {
+	query := `
+        INSERT INTO ingestion_checkpoints (id, batch_id, source, next_page, pages_fetched, records_fetched,
+                                           watermark, status, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (id) DO UPDATE SET
+            batch_id = excluded.batch_id,
+            next_page = excluded.next_page,
+            pages_fetched = excluded.pages_fetched,
+            records_fetched = excluded.records_fetched,
+            status = excluded.status,
+            updated_at = excluded.updated_at
+    `
+
+	_, err := r.db.Exec(ctx, query,
+		checkpoint.ID, checkpoint.BatchID, checkpoint.Source, checkpoint.NextPage, checkpoint.PagesFetched, checkpoint.RecordsFetched,
+		checkpoint.Watermark, checkpoint.Status, checkpoint.CreatedAt, checkpoint.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save ingestion checkpoint", "error", err, "batch_id", checkpoint.BatchID)
+		return fmt.Errorf("failed to save ingestion checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatest retrieves the most recently opened checkpoint of a source.
+func (r *ingestionCheckpointRepository) GetLatest(ctx context.Context, source string) (*entities.IngestionCheckpoint, error) {
+	query := `
+        SELECT id, batch_id, source, next_page, pages_fetched, records_fetched,
+               watermark, status, created_at, updated_at
+        FROM ingestion_checkpoints
+        WHERE source = $1
+        ORDER BY created_at DESC
+        LIMIT 1
+    `
+
+	checkpoint := &entities.IngestionCheckpoint{}
+	err := r.db.QueryRow(ctx, query, source).Scan(
+		&checkpoint.ID, &checkpoint.BatchID, &checkpoint.Source, &checkpoint.NextPage, &checkpoint.PagesFetched, &checkpoint.RecordsFetched,
+		&checkpoint.Watermark, &checkpoint.Status, &checkpoint.CreatedAt, &checkpoint.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest ingestion checkpoint: %w", err)
+	}
+
+	return checkpoint, nil
+}
@@ -0,0 +1,197 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+type ingestionLogRepository struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+// NewIngestionLogRepository creates a new instance of ingestionLogRepository implementing repositories.IngestionLogRepository.
+func NewIngestionLogRepository(db *pgxpool.Pool, logger logger.Logger) repositories.IngestionLogRepository {
+	return &ingestionLogRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const ingestionLogColumns = `id, batch_id, total_records, successful_records, failed_records,
+               inserted_records, updated_records, duplicate_records,
+               status, error_details, started_at, completed_at`
+
+// Create inserts a new ingestion run.
+func (r *ingestionLogRepository) Create(ctx context.Context, log *entities.IngestionLog) error {
+	errorDetails, err := marshalErrorDetails(log.ErrorDetails)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO ingestion_logs (` + ingestionLogColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+    `
+
+	_, err = r.db.Exec(ctx, query,
+		log.ID, log.BatchID, log.TotalRecords, log.SuccessfulRecords, log.FailedRecords,
+		log.InsertedRecords, log.UpdatedRecords, log.DuplicateRecords,
+		log.Status, errorDetails, log.CreatedAt, log.CompletedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create ingestion log", "error", err, "batch_id", log.BatchID)
+		return fmt.Errorf("failed to create ingestion log: %w", err)
+	}
+
+	return nil
+}
+
+// Update persists the counters, status, error details and completion time of a run.
+func (r *ingestionLogRepository) Update(ctx context.Context, log *entities.IngestionLog) error {
+	errorDetails, err := marshalErrorDetails(log.ErrorDetails)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        UPDATE ingestion_logs
+        SET total_records = $2, successful_records = $3, failed_records = $4,
+            inserted_records = $5, updated_records = $6, duplicate_records = $7,
+            status = $8, error_details = $9, completed_at = $10
+        WHERE id = $1
+    `
+
+	result, err := r.db.Exec(ctx, query,
+		log.ID, log.TotalRecords, log.SuccessfulRecords, log.FailedRecords,
+		log.InsertedRecords, log.UpdatedRecords, log.DuplicateRecords,
+		log.Status, errorDetails, log.CompletedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update ingestion log", "error", err, "batch_id", log.BatchID)
+		return fmt.Errorf("failed to update ingestion log: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("ingestion log %s: %w", log.BatchID, repositories.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetByBatchID retrieves a run by its batch ID.
+func (r *ingestionLogRepository) GetByBatchID(ctx context.Context, batchID string) (*entities.IngestionLog, error) {
+	query := `
+        SELECT ` + ingestionLogColumns + `
+        FROM ingestion_logs
+        WHERE batch_id = $1
+        ORDER BY started_at DESC
+        LIMIT 1
+    `
+
+	log, err := scanIngestionLog(r.db.QueryRow(ctx, query, batchID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingestion log by batch ID: %w", err)
+	}
+
+	return log, nil
+}
+
+// List retrieves runs from newest to oldest along with the total number of runs.
+func (r *ingestionLogRepository) List(ctx context.Context, limit, offset int) ([]*entities.IngestionLog, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM ingestion_logs`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count ingestion logs: %w", err)
+	}
+
+	query := `
+        SELECT ` + ingestionLogColumns + `
+        FROM ingestion_logs
+        ORDER BY started_at DESC
+        LIMIT $1 OFFSET $2
+    `
+
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query ingestion logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []*entities.IngestionLog
+	for rows.Next() {
+		log, err := scanIngestionLog(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan ingestion log row", "error", err)
+			continue
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, total, nil
+}
+
+// GetLastSuccessful retrieves the most recent completed run.
+func (r *ingestionLogRepository) GetLastSuccessful(ctx context.Context) (*entities.IngestionLog, error) {
+	query := `
+        SELECT ` + ingestionLogColumns + `
+        FROM ingestion_logs
+        WHERE status = $1
+        ORDER BY completed_at DESC
+        LIMIT 1
+    `
+
+	log, err := scanIngestionLog(r.db.QueryRow(ctx, query, entities.IngestionStatusCompleted))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last successful ingestion log: %w", err)
+	}
+
+	return log, nil
+}
+
+// scanIngestionLog maps a single ingestion_logs row, translating a missing row into repositories.ErrNotFound.
+func scanIngestionLog(row pgx.Row) (*entities.IngestionLog, error) {
+	log := &entities.IngestionLog{}
+	var errorDetails []byte
+
+	err := row.Scan(
+		&log.ID, &log.BatchID, &log.TotalRecords, &log.SuccessfulRecords, &log.FailedRecords,
+		&log.InsertedRecords, &log.UpdatedRecords, &log.DuplicateRecords,
+		&log.Status, &errorDetails, &log.CreatedAt, &log.CompletedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(errorDetails) > 0 {
+		if err := json.Unmarshal(errorDetails, &log.ErrorDetails); err != nil {
+			return nil, fmt.Errorf("failed to decode error details: %w", err)
+		}
+	}
+
+	return log, nil
+}
+
+func marshalErrorDetails(details map[string]interface{}) ([]byte, error) {
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode error details: %w", err)
+	}
+
+	return encoded, nil
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+// Leases are judged against the database clock, so replicas with skewed clocks still agree on expiry.
+
+type leaseRepository struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+// NewLeaseRepository creates a new instance of leaseRepository implementing repositories.LeaseRepository.
+func NewLeaseRepository(db *pgxpool.Pool, logger logger.Logger) repositories.LeaseRepository {
+	return &leaseRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// leaseInterval renders ttl as an interval literal both CockroachDB and Postgres accept
+func leaseInterval(ttl time.Duration) string {
+	return fmt.Sprintf("%d milliseconds", ttl.Milliseconds())
+}
+
+// TryAcquire inserts the lease or takes over an expired one in a single statement, so two
+// replicas racing for it cannot both succeed.
+func (r *leaseRepository) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (*entities.Lease, error) {
+	query := `
+        INSERT INTO job_leases (name, holder, token, expires_at, acquired_at, renewed_at)
+        VALUES ($1, $2, 1, now() + $3::INTERVAL, now(), now())
+        ON CONFLICT (name) DO UPDATE SET
+            holder = excluded.holder,
+            token = job_leases.token + 1,
+            expires_at = excluded.expires_at,
+            acquired_at = excluded.acquired_at,
+            renewed_at = excluded.renewed_at
+        WHERE job_leases.expires_at <= now()
+        RETURNING name, holder, token, expires_at, acquired_at, renewed_at
+    `
+
+	lease := &entities.Lease{}
+	err := r.db.QueryRow(ctx, query, name, holder, leaseInterval(ttl)).Scan(
+		&lease.Name, &lease.Holder, &lease.Token, &lease.ExpiresAt, &lease.AcquiredAt, &lease.RenewedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repositories.ErrLeaseHeld
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+
+	return lease, nil
+}
+
+// Renew extends the lease only while it is unexpired and still carries the same fencing token
+func (r *leaseRepository) Renew(ctx context.Context, lease *entities.Lease, ttl time.Duration) error {
+	query := `
+        UPDATE job_leases
+        SET expires_at = now() + $4::INTERVAL, renewed_at = now()
+        WHERE name = $1 AND holder = $2 AND token = $3 AND expires_at > now()
+        RETURNING expires_at, renewed_at
+    `
+
+	err := r.db.QueryRow(ctx, query, lease.Name, lease.Holder, lease.Token, leaseInterval(ttl)).Scan(&lease.ExpiresAt, &lease.RenewedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repositories.ErrLeaseLost
+	}
+	if err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+
+	return nil
+}
+
+// Release expires the lease rather than deleting it, so the next grant still gets a greater fencing token
+func (r *leaseRepository) Release(ctx context.Context, lease *entities.Lease) error {
+	query := `
+        UPDATE job_leases
+        SET expires_at = now()
+        WHERE name = $1 AND holder = $2 AND token = $3 AND expires_at > now()
+    `
+
+	if _, err := r.db.Exec(ctx, query, lease.Name, lease.Holder, lease.Token); err != nil {
+		r.logger.Error("Failed to release lease", "error", err, "name", lease.Name)
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+
+	return nil
+}
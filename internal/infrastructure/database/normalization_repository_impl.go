@@ -0,0 +1,234 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+// uniqueViolation is the SQLSTATE reported when an insert or update breaks a unique index
+const uniqueViolation = "23505"
+
+type normalizationRepository struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+// NewNormalizationRepository creates a new instance of normalizationRepository implementing repositories.NormalizationRepository.
+func NewNormalizationRepository(db *pgxpool.Pool, logger logger.Logger) repositories.NormalizationRepository {
+	return &normalizationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const normalizationRuleColumns = `id, kind, brokerage, pattern, value, created_at, updated_at`
+
+// ListRules retrieves the rules of one kind, or of every kind when kind is empty.
+func (r *normalizationRepository) ListRules(ctx context.Context, kind entities.NormalizationKind) ([]*entities.NormalizationRule, error) {
+	whereClause := ""
+	args := []interface{}{}
+	if kind != "" {
+		whereClause = "WHERE kind = $1"
+		args = append(args, kind)
+	}
+
+	query := `
+        SELECT ` + normalizationRuleColumns + `
+        FROM normalization_rules
+        ` + whereClause + `
+        ORDER BY kind, brokerage, pattern
+    `
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query normalization rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*entities.NormalizationRule
+	for rows.Next() {
+		rule, err := scanNormalizationRule(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan normalization rule row", "error", err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// GetRule retrieves a rule by its ID.
+func (r *normalizationRepository) GetRule(ctx context.Context, id uuid.UUID) (*entities.NormalizationRule, error) {
+	query := `
+        SELECT ` + normalizationRuleColumns + `
+        FROM normalization_rules
+        WHERE id = $1
+    `
+
+	rule, err := scanNormalizationRule(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get normalization rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// CreateRule inserts a new rule.
+func (r *normalizationRepository) CreateRule(ctx context.Context, rule *entities.NormalizationRule) error {
+	query := `
+        INSERT INTO normalization_rules (` + normalizationRuleColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `
+
+	_, err := r.db.Exec(ctx, query,
+		rule.ID, rule.Kind, rule.Brokerage, rule.Pattern, rule.Value, rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create normalization rule: %w", translateUniqueViolation(err))
+	}
+
+	return nil
+}
+
+// UpdateRule persists the brokerage, pattern and value of a rule.
+func (r *normalizationRepository) UpdateRule(ctx context.Context, rule *entities.NormalizationRule) error {
+	query := `
+        UPDATE normalization_rules
+        SET brokerage = $2, pattern = $3, value = $4, updated_at = $5
+        WHERE id = $1
+    `
+
+	result, err := r.db.Exec(ctx, query, rule.ID, rule.Brokerage, rule.Pattern, rule.Value, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update normalization rule: %w", translateUniqueViolation(err))
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("normalization rule %s: %w", rule.ID, repositories.ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteRule removes a rule by its ID.
+func (r *normalizationRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM normalization_rules WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete normalization rule", "error", err, "id", id)
+		return fmt.Errorf("failed to delete normalization rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("normalization rule %s: %w", id, repositories.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ListStockLabels retrieves the distinct raw labels of stocks with their normalized values and how many stocks share them.
+func (r *normalizationRepository) ListStockLabels(ctx context.Context) ([]repositories.StockLabels, error) {
+	query := `
+        SELECT COALESCE(b.name, ''), s.action, COALESCE(s.rating_from, ''), COALESCE(s.rating_to, ''),
+               s.action_type, s.rating_from_tier, s.rating_to_tier, COUNT(*)
+        FROM stocks s
+        LEFT JOIN brokers b ON s.broker_id = b.id
+        GROUP BY 1, 2, 3, 4, 5, 6, 7
+        ORDER BY 8 DESC
+    `
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock labels: %w", err)
+	}
+	defer rows.Close()
+
+	var labels []repositories.StockLabels
+	for rows.Next() {
+		var label repositories.StockLabels
+		err := rows.Scan(
+			&label.Brokerage, &label.Action, &label.RatingFrom, &label.RatingTo,
+			&label.ActionType, &label.RatingFromTier, &label.RatingToTier, &label.Stocks,
+		)
+		if err != nil {
+			r.logger.Error("Failed to scan stock labels row", "error", err)
+			continue
+		}
+		labels = append(labels, label)
+	}
+
+	return labels, nil
+}
+
+// UpdateStockLabels stores the normalized values of each label combination on the stocks sharing it, in a single transaction.
+func (r *normalizationRepository) UpdateStockLabels(ctx context.Context, labels []repositories.StockLabels) (int, error) {
+	if len(labels) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        UPDATE stocks s
+        SET action_type = $5, rating_from_tier = $6, rating_to_tier = $7
+        WHERE COALESCE((SELECT b.name FROM brokers b WHERE b.id = s.broker_id), '') = $1
+          AND s.action = $2 AND COALESCE(s.rating_from, '') = $3 AND COALESCE(s.rating_to, '') = $4
+    `
+
+	updated := 0
+	for _, label := range labels {
+		result, err := tx.Exec(ctx, query,
+			label.Brokerage, label.Action, label.RatingFrom, label.RatingTo,
+			label.ActionType, label.RatingFromTier, label.RatingToTier,
+		)
+		if err != nil {
+			r.logger.Error("Failed to update stock labels", "error", err, "brokerage", label.Brokerage)
+			return 0, fmt.Errorf("failed to update stock labels: %w", err)
+		}
+		updated += int(result.RowsAffected())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return updated, nil
+}
+
+// scanNormalizationRule maps a single normalization_rules row, translating a missing row into repositories.ErrNotFound.
+func scanNormalizationRule(row pgx.Row) (*entities.NormalizationRule, error) {
+	rule := &entities.NormalizationRule{}
+
+	err := row.Scan(&rule.ID, &rule.Kind, &rule.Brokerage, &rule.Pattern, &rule.Value, &rule.CreatedAt, &rule.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// translateUniqueViolation reports a broken unique index as repositories.ErrDuplicate
+func translateUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return repositories.ErrDuplicate
+	}
+	return err
+}
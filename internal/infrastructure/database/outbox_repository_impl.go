@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+type outboxRepository struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+// NewOutboxRepository creates a new instance of outboxRepository implementing repositories.OutboxRepository.
+func NewOutboxRepository(db *pgxpool.Pool, logger logger.Logger) repositories.OutboxRepository {
+	return &outboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+var outboxColumns = []string{"id", "event_type", "aggregate_id", "payload", "occurred_at"}
+
+// appendOutbox writes events to the outbox inside tx, so they are committed or rolled back with the
+// change they record
+func appendOutbox(ctx context.Context, tx pgx.Tx, events []*entities.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([][]interface{}, len(events))
+	for i, event := range events {
+		rows[i] = []interface{}{event.ID, event.Type, event.AggregateID, []byte(event.Payload), event.OccurredAt}
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"outbox_events"}, outboxColumns, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("failed to write domain events to the outbox: %w", err)
+	}
+	return nil
+}
+
+// Claim takes the oldest due pending events, pushing their next attempt past the claim.
+func (r *outboxRepository) Claim(ctx context.Context, limit int, claimFor time.Duration) ([]*entities.OutboxEvent, error) {
+	now := time.Now()
+	rows, err := r.db.Query(ctx, `
+        UPDATE outbox_events
+        SET attempts = attempts + 1, next_attempt_at = $2
+        WHERE id IN (
+            SELECT id FROM outbox_events
+            WHERE status = 'pending' AND next_attempt_at <= $1
+            ORDER BY occurred_at
+            LIMIT $3
+        ) AND status = 'pending' AND next_attempt_at <= $1
+        RETURNING id, event_type, aggregate_id, payload, occurred_at,
+                  status, attempts, next_attempt_at, COALESCE(last_error, ''), delivered_at
+    `, now, now.Add(claimFor), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entities.OutboxEvent
+	for rows.Next() {
+		event := &entities.OutboxEvent{}
+		err := rows.Scan(
+			&event.ID, &event.Type, &event.AggregateID, &event.Payload, &event.OccurredAt,
+			&event.Status, &event.Attempts, &event.NextAttemptAt, &event.LastError, &event.DeliveredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].OccurredAt.Before(events[j].OccurredAt) })
+	return events, nil
+}
+
+// MarkDelivered records that every subscriber handled the event.
+func (r *outboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+        UPDATE outbox_events
+        SET status = 'delivered', delivered_at = now(), last_error = NULL
+        WHERE id = $1
+    `, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event %s delivered: %w", id, err)
+	}
+	return nil
+}
+
+// Reschedule records a failed delivery and makes the event due again at the given time.
+func (r *outboxRepository) Reschedule(ctx context.Context, id uuid.UUID, lastError string, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+        UPDATE outbox_events
+        SET next_attempt_at = $2, last_error = $3
+        WHERE id = $1 AND status = 'pending'
+    `, id, at, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule outbox event %s: %w", id, err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery and gives up on the event.
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	_, err := r.db.Exec(ctx, `
+        UPDATE outbox_events
+        SET status = 'failed', last_error = $2
+        WHERE id = $1 AND status = 'pending'
+    `, id, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event %s failed: %w", id, err)
+	}
+	return nil
+}
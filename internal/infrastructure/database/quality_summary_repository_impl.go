@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+type qualitySummaryRepository struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+// NewQualitySummaryRepository creates a new instance of qualitySummaryRepository implementing repositories.QualitySummaryRepository.
+func NewQualitySummaryRepository(db *pgxpool.Pool, logger logger.Logger) repositories.QualitySummaryRepository {
+	return &qualitySummaryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const qualitySummaryColumns = `id, batch_id, source, checked, passed, warned, quarantined, rejected, rules, created_at`
+
+// Save inserts the summary of a run.
+func (r *qualitySummaryRepository) Save(ctx context.Context, summary *entities.QualitySummary) error {
+	rules, err := json.Marshal(summary.Rules)
+	if err != nil {
+		return fmt.Errorf("failed to encode quality summary: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, `
+        INSERT INTO quality_summaries (`+qualitySummaryColumns+`)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `,
+		summary.ID, summary.BatchID, summary.Source, summary.Checked, summary.Passed,
+		summary.Warned, summary.Quarantined, summary.Rejected, rules, summary.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save quality summary", "error", err, "batch_id", summary.BatchID)
+		return fmt.Errorf("failed to save quality summary: %w", err)
+	}
+
+	return nil
+}
+
+// GetByBatchID retrieves the summary of a run.
+func (r *qualitySummaryRepository) GetByBatchID(ctx context.Context, batchID string) (*entities.QualitySummary, error) {
+	summary, err := scanQualitySummary(r.db.QueryRow(ctx, `
+        SELECT `+qualitySummaryColumns+`
+        FROM quality_summaries
+        WHERE batch_id = $1
+    `, batchID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("quality summary of %s: %w", batchID, repositories.ErrNotFound)
+	}
+	return summary, err
+}
+
+// List retrieves summaries from newest to oldest along with the total number of summaries.
+func (r *qualitySummaryRepository) List(ctx context.Context, limit, offset int) ([]*entities.QualitySummary, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM quality_summaries`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count quality summaries: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT `+qualitySummaryColumns+`
+        FROM quality_summaries
+        ORDER BY created_at DESC
+        LIMIT $1 OFFSET $2
+    `, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query quality summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*entities.QualitySummary
+	for rows.Next() {
+		summary, err := scanQualitySummary(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan quality summary row", "error", err)
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, total, nil
+}
+
+// scanQualitySummary maps a row selected with qualitySummaryColumns
+func scanQualitySummary(row pgx.Row) (*entities.QualitySummary, error) {
+	summary := &entities.QualitySummary{}
+	var rules []byte
+	err := row.Scan(&summary.ID, &summary.BatchID, &summary.Source, &summary.Checked, &summary.Passed,
+		&summary.Warned, &summary.Quarantined, &summary.Rejected, &rules, &summary.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(rules, &summary.Rules); err != nil {
+		return nil, fmt.Errorf("failed to decode quality summary: %w", err)
+	}
+	if summary.Rules == nil {
+		summary.Rules = make(map[string]*entities.QualityRuleCount)
+	}
+	return summary, nil
+}
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+type schemaProfileRepository struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+// NewSchemaProfileRepository creates a new instance of schemaProfileRepository implementing repositories.SchemaProfileRepository.
+func NewSchemaProfileRepository(db *pgxpool.Pool, logger logger.Logger) repositories.SchemaProfileRepository {
+	return &schemaProfileRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// schemaProfileCounts is the counts column: the observations rates are derived from
+type schemaProfileCounts struct {
+	UnknownKeys   map[string]int `json:"unknown_keys"`
+	MissingFields map[string]int `json:"missing_fields"`
+	TimeValues    int            `json:"time_values"`
+	TimeFailures  int            `json:"time_failures"`
+	PriceValues   int            `json:"price_values"`
+	PriceFailures int            `json:"price_failures"`
+}
+
+const schemaProfileColumns = `id, batch_id, source, records, counts, drift, is_baseline, created_at`
+
+// Save inserts the profile, clearing the previous baseline of its source first when it is a baseline.
+func (r *schemaProfileRepository) Save(ctx context.Context, profile *entities.SchemaProfile) error {
+	counts, err := json.Marshal(schemaProfileCounts{
+		UnknownKeys:   profile.UnknownKeys,
+		MissingFields: profile.MissingFields,
+		TimeValues:    profile.TimeValues,
+		TimeFailures:  profile.TimeFailures,
+		PriceValues:   profile.PriceValues,
+		PriceFailures: profile.PriceFailures,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode schema profile: %w", err)
+	}
+	drift := profile.Drift
+	if drift == nil {
+		drift = []entities.SchemaDrift{}
+	}
+	driftJSON, err := json.Marshal(drift)
+	if err != nil {
+		return fmt.Errorf("failed to encode schema drift: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if profile.IsBaseline {
+		if _, err := tx.Exec(ctx, `UPDATE schema_profiles SET is_baseline = false WHERE source = $1 AND is_baseline`, profile.Source); err != nil {
+			return fmt.Errorf("failed to clear schema baseline: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO schema_profiles (`+schemaProfileColumns+`)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    `,
+		profile.ID, profile.BatchID, profile.Source, profile.Records, counts, driftJSON, profile.IsBaseline, profile.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save schema profile", "error", err, "batch_id", profile.BatchID)
+		return fmt.Errorf("failed to save schema profile: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetBaseline retrieves the baseline profile of a source.
+func (r *schemaProfileRepository) GetBaseline(ctx context.Context, source string) (*entities.SchemaProfile, error) {
+	profile, err := scanSchemaProfile(r.db.QueryRow(ctx, `
+        SELECT `+schemaProfileColumns+`
+        FROM schema_profiles
+        WHERE source = $1 AND is_baseline
+    `, source))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("schema baseline of %s: %w", source, repositories.ErrNotFound)
+	}
+	return profile, err
+}
+
+// GetLatest retrieves the most recent profile of a source.
+func (r *schemaProfileRepository) GetLatest(ctx context.Context, source string) (*entities.SchemaProfile, error) {
+	profile, err := scanSchemaProfile(r.db.QueryRow(ctx, `
+        SELECT `+schemaProfileColumns+`
+        FROM schema_profiles
+        WHERE source = $1
+        ORDER BY created_at DESC
+        LIMIT 1
+    `, source))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("schema profile of %s: %w", source, repositories.ErrNotFound)
+	}
+	return profile, err
+}
+
+// SetBaseline moves the baseline of the profile's source to the profile.
+func (r *schemaProfileRepository) SetBaseline(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var source string
+	err = tx.QueryRow(ctx, `SELECT source FROM schema_profiles WHERE id = $1 FOR UPDATE`, id).Scan(&source)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("schema profile %s: %w", id, repositories.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get schema profile: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE schema_profiles SET is_baseline = false WHERE source = $1 AND is_baseline`, source); err != nil {
+		return fmt.Errorf("failed to clear schema baseline: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE schema_profiles SET is_baseline = true WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to set schema baseline: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// scanSchemaProfile maps a row selected with schemaProfileColumns
+func scanSchemaProfile(row pgx.Row) (*entities.SchemaProfile, error) {
+	profile := &entities.SchemaProfile{}
+	var counts, drift []byte
+	err := row.Scan(&profile.ID, &profile.BatchID, &profile.Source, &profile.Records, &counts, &drift,
+		&profile.IsBaseline, &profile.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded schemaProfileCounts
+	if err := json.Unmarshal(counts, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode schema profile: %w", err)
+	}
+	if err := json.Unmarshal(drift, &profile.Drift); err != nil {
+		return nil, fmt.Errorf("failed to decode schema drift: %w", err)
+	}
+
+	profile.UnknownKeys, profile.MissingFields = decoded.UnknownKeys, decoded.MissingFields
+	if profile.UnknownKeys == nil {
+		profile.UnknownKeys = make(map[string]int)
+	}
+	if profile.MissingFields == nil {
+		profile.MissingFields = make(map[string]int)
+	}
+	profile.TimeValues, profile.TimeFailures = decoded.TimeValues, decoded.TimeFailures
+	profile.PriceValues, profile.PriceFailures = decoded.PriceValues, decoded.PriceFailures
+	return profile, nil
+}
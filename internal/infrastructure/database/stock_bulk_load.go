@@ -0,0 +1,323 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+)
+
+// Batches are loaded with COPY into a session-scoped staging table and merged into stocks
+// with set-based statements, which costs a handful of round trips per batch instead of one per row.
+
+const stockStagingTable = "stocks_staging"
+
+// position keeps the upstream order, so the last occurrence of a repeated event wins
+var stockStagingColumns = []string{
+	"position", "id", "ticker", "company", "broker_id", "action", "rating_from", "rating_to",
+	"target_from", "target_to", "event_time", "price_close", "price_close_date", "created_at", "updated_at", "source",
+	"action_type", "rating_from_tier", "rating_to_tier", "currency",
+	"payload_batch_id", "payload_source", "payload_fetched_at", "payload",
+}
+
+const createStockStagingTable = `
+    CREATE TEMP TABLE IF NOT EXISTS stocks_staging (
+        position INT NOT NULL,
+        id UUID NOT NULL,
+        ticker STRING NOT NULL,
+        company STRING NOT NULL,
+        broker_id UUID,
+        action STRING NOT NULL,
+        rating_from STRING,
+        rating_to STRING,
+        target_from DECIMAL(10,2),
+        target_to DECIMAL(10,2),
+        event_time TIMESTAMPTZ NOT NULL,
+        price_close DECIMAL(10,2),
+        price_close_date DATE,
+        created_at TIMESTAMPTZ,
+        updated_at TIMESTAMPTZ,
+        source STRING NOT NULL,
+        action_type STRING NOT NULL,
+        rating_from_tier STRING NOT NULL,
+        rating_to_tier STRING NOT NULL,
+        currency STRING NOT NULL,
+        payload_batch_id STRING,
+        payload_source STRING,
+        payload_fetched_at TIMESTAMPTZ,
+        payload JSONB
+    )
+`
+
+// stagedStocks holds one row per event in the staging table, keeping the last one the batch carried
+const stagedStocks = `
+    staged AS (
+        SELECT DISTINCT ON (ticker, event_time) *
+        FROM stocks_staging
+        ORDER BY ticker, event_time, position DESC
+    )
+`
+
+// stagedRevision matches stored stocks whose analyst fields the staged copy corrects.
+// Targets are DECIMAL(10,2) on both sides, so they are compared to the cent like Stock.HasRevisedFields.
+const stagedRevision = `
+    s.company IS DISTINCT FROM st.company OR
+    s.broker_id IS DISTINCT FROM st.broker_id OR
+    s.action IS DISTINCT FROM st.action OR
+    s.rating_from IS DISTINCT FROM st.rating_from OR
+    s.rating_to IS DISTINCT FROM st.rating_to OR
+    s.target_from IS DISTINCT FROM st.target_from OR
+    s.target_to IS DISTINCT FROM st.target_to OR
+    s.currency IS DISTINCT FROM st.currency
+`
+
+// mergeStagedPayloads stores the raw payloads staged with the stocks against the stored events, whether
+// they were just inserted or already existed. A stored payload is only replaced when the item changed.
+const mergeStagedPayloads = `
+    INSERT INTO stock_payloads (stock_id, batch_id, source, fetched_at, payload)
+    SELECT s.id, st.payload_batch_id, st.payload_source, st.payload_fetched_at, st.payload
+    FROM staged st
+    JOIN stocks s ON s.ticker = st.ticker AND s.event_time = st.event_time
+    WHERE st.payload IS NOT NULL
+    ON CONFLICT (stock_id) DO UPDATE
+    SET batch_id = excluded.batch_id, source = excluded.source,
+        fetched_at = excluded.fetched_at, payload = excluded.payload
+    WHERE stock_payloads.payload IS DISTINCT FROM excluded.payload
+`
+
+// stageStocks replaces the contents of the staging table with stocks using COPY
+func (r *stockRepository) stageStocks(ctx context.Context, tx pgx.Tx, stocks []*entities.Stock) error {
+	if _, err := tx.Exec(ctx, `SET LOCAL experimental_enable_temp_tables = 'on'`); err != nil {
+		return fmt.Errorf("failed to enable temporary tables: %w", err)
+	}
+	if _, err := tx.Exec(ctx, createStockStagingTable); err != nil {
+		return fmt.Errorf("failed to create staging table: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM `+stockStagingTable); err != nil {
+		return fmt.Errorf("failed to clear staging table: %w", err)
+	}
+
+	rows := make([][]interface{}, len(stocks))
+	for i, stock := range stocks {
+		var payloadBatchID, payloadSource, payloadFetchedAt, payload interface{}
+		if stock.Payload != nil {
+			payloadBatchID, payloadSource = stock.Payload.BatchID, stock.Payload.Source
+			payloadFetchedAt, payload = stock.Payload.FetchedAt, []byte(stock.Payload.Payload)
+		}
+
+		rows[i] = []interface{}{
+			i, stock.ID, stock.Ticker, stock.Company, stock.BrokerID, stock.Action,
+			stock.RatingFrom, stock.RatingTo, stock.TargetFrom, stock.TargetTo,
+			stock.EventTime, stock.PriceClose, stock.PriceCloseDate, stock.CreatedAt, stock.UpdatedAt, stock.Source,
+			stock.ActionType, stock.RatingFromTier, stock.RatingToTier, stock.Currency,
+			payloadBatchID, payloadSource, payloadFetchedAt, payload,
+		}
+	}
+
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{stockStagingTable}, stockStagingColumns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return fmt.Errorf("failed to copy stocks into staging table: %w", err)
+	}
+	if int(copied) != len(stocks) {
+		return fmt.Errorf("copied %d of %d stocks into staging table", copied, len(stocks))
+	}
+
+	return nil
+}
+
+// bulkCreateStaged inserts the staged stocks, skipping events that are already stored
+func (r *stockRepository) bulkCreateStaged(ctx context.Context, stocks []*entities.Stock) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.stageStocks(ctx, tx, stocks); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+        WITH `+stagedStocks+`
+        INSERT INTO stocks (id, ticker, company, broker_id, action, rating_from, rating_to,
+                           target_from, target_to, event_time, price_close, created_at, updated_at, source,
+                           action_type, rating_from_tier, rating_to_tier, currency)
+        SELECT id, ticker, company, broker_id, action, rating_from, rating_to,
+               target_from, target_to, event_time, price_close, created_at, updated_at, source,
+               action_type, rating_from_tier, rating_to_tier, currency
+        FROM staged
+        ON CONFLICT (ticker, event_time) DO NOTHING
+    `)
+	if err != nil {
+		return fmt.Errorf("failed to merge staged stocks: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Info("Successfully inserted stocks batch", "count", tag.RowsAffected())
+	return nil
+}
+
+// bulkUpdateStaged overwrites the stored stocks matching the staged IDs
+func (r *stockRepository) bulkUpdateStaged(ctx context.Context, stocks []*entities.Stock) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.stageStocks(ctx, tx, stocks); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+        UPDATE stocks s
+        SET ticker = st.ticker, company = st.company, broker_id = st.broker_id, action = st.action,
+            rating_from = st.rating_from, rating_to = st.rating_to,
+            target_from = st.target_from, target_to = st.target_to,
+            event_time = st.event_time, price_close = st.price_close, updated_at = st.updated_at,
+            action_type = st.action_type, rating_from_tier = st.rating_from_tier, rating_to_tier = st.rating_to_tier,
+            price_close_date = st.price_close_date, currency = st.currency
+        FROM stocks_staging st
+        WHERE s.id = st.id
+    `)
+	if err != nil {
+		return fmt.Errorf("failed to merge staged stocks: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Info("Successfully updated stocks batch", "count", tag.RowsAffected())
+	return nil
+}
+
+// bulkUpsertStaged merges the staged stocks into stocks in three set-based steps: prior values of
+// corrected events go to stock_revisions, corrected events are updated and new events are inserted.
+// The raw payloads of the batch are then stored against the events they belong to, and the domain
+// events of the stocks written go to the outbox.
+// Events that match the stored row exactly, or repeat within the batch, are counted as duplicates.
+func (r *stockRepository) bulkUpsertStaged(ctx context.Context, stocks []*entities.Stock) (*repositories.UpsertResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.stageStocks(ctx, tx, stocks); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	_, err = tx.Exec(ctx, `
+        WITH `+stagedStocks+`
+        INSERT INTO stock_revisions (stock_id, company, broker_id, action, rating_from, rating_to,
+                                     target_from, target_to, valid_from, valid_to,
+                                     action_type, rating_from_tier, rating_to_tier, currency)
+        SELECT s.id, s.company, s.broker_id, s.action, s.rating_from, s.rating_to,
+               s.target_from, s.target_to, s.updated_at, $1,
+               s.action_type, s.rating_from_tier, s.rating_to_tier, s.currency
+        FROM stocks s
+        JOIN staged st ON s.ticker = st.ticker AND s.event_time = st.event_time
+        WHERE `+stagedRevision, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record revisions: %w", err)
+	}
+
+	updated, err := queryEventStocks(ctx, tx, `
+        WITH `+stagedStocks+`
+        UPDATE stocks s
+        SET company = st.company, broker_id = st.broker_id, action = st.action,
+            rating_from = st.rating_from, rating_to = st.rating_to,
+            target_from = st.target_from, target_to = st.target_to, updated_at = $1, source = st.source,
+            action_type = st.action_type, rating_from_tier = st.rating_from_tier, rating_to_tier = st.rating_to_tier,
+            currency = st.currency
+        FROM staged st
+        WHERE s.ticker = st.ticker AND s.event_time = st.event_time AND (`+stagedRevision+`)
+        RETURNING `+eventStockColumns, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply corrections: %w", err)
+	}
+
+	inserted, err := queryEventStocks(ctx, tx, `
+        WITH `+stagedStocks+`
+        INSERT INTO stocks AS s (id, ticker, company, broker_id, action, rating_from, rating_to,
+                                target_from, target_to, event_time, price_close, created_at, updated_at, source,
+                                action_type, rating_from_tier, rating_to_tier, currency)
+        SELECT id, ticker, company, broker_id, action, rating_from, rating_to,
+               target_from, target_to, event_time, price_close, created_at, updated_at, source,
+               action_type, rating_from_tier, rating_to_tier, currency
+        FROM staged
+        ON CONFLICT (ticker, event_time) DO NOTHING
+        RETURNING `+eventStockColumns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert new stocks: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `WITH `+stagedStocks+mergeStagedPayloads); err != nil {
+		return nil, fmt.Errorf("failed to store raw payloads: %w", err)
+	}
+
+	var events []*entities.DomainEvent
+	for _, stock := range updated {
+		events = append(events, entities.RevisedStockEvent(stock))
+	}
+	for _, stock := range inserted {
+		events = append(events, entities.IngestedStockEvents(stock)...)
+	}
+	if err := appendOutbox(ctx, tx, events); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	result := &repositories.UpsertResult{
+		Inserted: len(inserted),
+		Updated:  len(updated),
+	}
+	result.Duplicates = len(stocks) - result.Inserted - result.Updated
+
+	r.logger.Info("Successfully upserted stocks batch", "inserted", result.Inserted, "updated", result.Updated, "duplicates", result.Duplicates)
+	return result, nil
+}
+
+// eventStockColumns is returned by the merges of staged stocks, in the order queryEventStocks reads them
+const eventStockColumns = `s.id, s.ticker, s.company, s.broker_id, s.action, s.rating_from, s.rating_to,
+            s.target_from, s.target_to, s.event_time, s.source,
+            s.action_type, s.rating_from_tier, s.rating_to_tier, s.currency`
+
+// queryEventStocks runs a merge returning eventStockColumns and reads the stocks it wrote, which
+// carry what their domain events need
+func queryEventStocks(ctx context.Context, tx pgx.Tx, query string, args ...interface{}) ([]*entities.Stock, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stocks []*entities.Stock
+	for rows.Next() {
+		stock := &entities.Stock{}
+		err := rows.Scan(
+			&stock.ID, &stock.Ticker, &stock.Company, &stock.BrokerID, &stock.Action,
+			&stock.RatingFrom, &stock.RatingTo, &stock.TargetFrom, &stock.TargetTo,
+			&stock.EventTime, &stock.Source,
+			&stock.ActionType, &stock.RatingFromTier, &stock.RatingToTier, &stock.Currency,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, stock)
+	}
+	return stocks, rows.Err()
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/pkg/logger"
+)
+
+type stockChangeRepository struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+// NewStockChangeRepository creates a new instance of stockChangeRepository implementing repositories.StockChangeRepository.
+func NewStockChangeRepository(db *pgxpool.Pool, logger logger.Logger) repositories.StockChangeRepository {
+	return &stockChangeRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const stockChangeColumns = `id, stock_id, kind, changed_by, fields, before, after, changed_at`
+
+// insertStockChange stores the record of a change inside tx, the transaction making the change.
+func insertStockChange(ctx context.Context, tx pgx.Tx, change *entities.StockChange) error {
+	before, err := encodeStockSnapshot(change.Before)
+	if err != nil {
+		return err
+	}
+	after, err := encodeStockSnapshot(change.After)
+	if err != nil {
+		return err
+	}
+
+	fields := change.Fields
+	if fields == nil {
+		fields = []string{}
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO stock_changes (`+stockChangeColumns+`)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    `, change.ID, change.StockID, change.Kind, change.ChangedBy, fields, before, after, change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save stock change: %w", err)
+	}
+
+	return nil
+}
+
+// ListByStock retrieves the changes made to a stock event, newest first.
+func (r *stockChangeRepository) ListByStock(ctx context.Context, stockID uuid.UUID) ([]*entities.StockChange, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT `+stockChangeColumns+`
+        FROM stock_changes
+        WHERE stock_id = $1
+        ORDER BY changed_at DESC
+    `, stockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*entities.StockChange
+	for rows.Next() {
+		change, err := scanStockChange(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan stock change row", "error", err)
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// encodeStockSnapshot encodes a side of a change, leaving a missing side NULL
+func encodeStockSnapshot(stock *entities.Stock) ([]byte, error) {
+	if stock == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(stock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode stock change: %w", err)
+	}
+	return data, nil
+}
+
+// scanStockChange maps a row selected with stockChangeColumns
+func scanStockChange(row pgx.Row) (*entities.StockChange, error) {
+	change := &entities.StockChange{}
+	var before, after []byte
+	err := row.Scan(&change.ID, &change.StockID, &change.Kind, &change.ChangedBy, &change.Fields,
+		&before, &after, &change.ChangedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if before != nil {
+		if err := json.Unmarshal(before, &change.Before); err != nil {
+			return nil, fmt.Errorf("failed to decode stock change: %w", err)
+		}
+	}
+	if after != nil {
+		if err := json.Unmarshal(after, &change.After); err != nil {
+			return nil, fmt.Errorf("failed to decode stock change: %w", err)
+		}
+	}
+	return change, nil
+}
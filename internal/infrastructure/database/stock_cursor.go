@@ -0,0 +1,167 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/valueObjects"
+)
+
+// sortExpressions maps the public sort fields onto the SQL they order stocks s joined with brokers b by.
+// Only these expressions ever reach an ORDER BY.
+var sortExpressions = map[string]sortExpression{
+	valueObjects.SortEventTime:         {sql: "s.event_time", sqlType: "TIMESTAMPTZ"},
+	valueObjects.SortTicker:            {sql: "s.ticker", sqlType: "STRING"},
+	valueObjects.SortCompany:           {sql: "s.company", sqlType: "STRING"},
+	valueObjects.SortBrokerage:         {sql: "b.name", sqlType: "STRING", nullable: true},
+	valueObjects.SortAction:            {sql: "s.action", sqlType: "STRING"},
+	valueObjects.SortTargetFrom:        {sql: "s.target_from", sqlType: "DECIMAL"},
+	valueObjects.SortTargetTo:          {sql: "s.target_to", sqlType: "DECIMAL"},
+	valueObjects.SortTargetChange:      {sql: "(CASE WHEN s.target_from > 0 THEN " + targetChangeSQL + " END)", sqlType: "DECIMAL", nullable: true},
+	valueObjects.SortBrokerCredibility: {sql: "b.credibility_score", sqlType: "DECIMAL", nullable: true},
+	valueObjects.SortCreatedAt:         {sql: "s.created_at", sqlType: "TIMESTAMPTZ"},
+}
+
+type sortExpression struct {
+	sql string
+	// sqlType is what a value read back from a cursor is cast to before it is compared with sql
+	sqlType string
+	// nullable expressions sort their NULLs last in either direction
+	nullable bool
+}
+
+// orderByClause builds the ORDER BY of keys, ending with the stock ID so rows that tie on every key
+// keep the same order from one page to the next. Backward reverses every term, to walk towards the
+// start of the listing from a cursor.
+func orderByClause(keys []valueObjects.SortKey, backward bool) (string, error) {
+	terms := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		expression, ok := sortExpressions[key.Field]
+		if !ok {
+			return "", fmt.Errorf("cannot sort stocks by %q", key.Field)
+		}
+		if expression.nullable {
+			terms = append(terms, expression.sql+" IS NULL"+sortDirection(backward))
+		}
+		terms = append(terms, expression.sql+sortDirection(key.Descending != backward))
+	}
+	terms = append(terms, "s.id"+sortDirection(backward))
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+func sortDirection(descending bool) string {
+	if descending {
+		return " DESC"
+	}
+	return " ASC"
+}
+
+// sortValueColumns selects the value of every sort key as text, after stockColumns, so the cursors
+// of a page can be built from its first and last stocks
+func sortValueColumns(keys []valueObjects.SortKey) string {
+	var columns strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&columns, ", (%s)::STRING", sortExpressions[key.Field].sql)
+	}
+	return columns.String()
+}
+
+// stockCursor is the position of a stock in a sorted listing: the values of its sort keys, in the
+// order of Sort, and its ID. A backward cursor pages towards the start of the listing.
+type stockCursor struct {
+	Sort     string    `json:"s"`
+	Values   []*string `json:"v"`
+	ID       uuid.UUID `json:"id"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+// sortSpec renders keys the way a sort query parameter spells them
+func sortSpec(keys []valueObjects.SortKey) string {
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = key.String()
+	}
+	return strings.Join(fields, ",")
+}
+
+// encodeStockCursor builds the opaque cursor of the stock with the given sort values and ID
+func encodeStockCursor(keys []valueObjects.SortKey, values []*string, id uuid.UUID, backward bool) string {
+	data, _ := json.Marshal(stockCursor{Sort: sortSpec(keys), Values: values, ID: id, Backward: backward})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeStockCursor reads a cursor back, rejecting one that is malformed or was issued for a listing
+// sorted by other keys
+func decodeStockCursor(encoded string, keys []valueObjects.SortKey) (*stockCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", repositories.ErrInvalidCursor)
+	}
+
+	var cursor stockCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", repositories.ErrInvalidCursor)
+	}
+	if cursor.Sort != sortSpec(keys) || len(cursor.Values) != len(keys) {
+		return nil, fmt.Errorf("%w: cursor was issued for another sort order", repositories.ErrInvalidCursor)
+	}
+	for i, key := range keys {
+		if cursor.Values[i] == nil && !sortExpressions[key.Field].nullable {
+			return nil, fmt.Errorf("%w: missing %s in cursor", repositories.ErrInvalidCursor, key.Field)
+		}
+	}
+
+	return &cursor, nil
+}
+
+// keysetCondition builds the condition selecting the stocks that come after cursor in the order of
+// keys, or before it for a backward cursor, appending its arguments to args. The condition is a
+// disjunction with one term per ORDER BY term: the rows equal on every earlier term and past the
+// cursor on this one.
+func keysetCondition(keys []valueObjects.SortKey, cursor *stockCursor, args []interface{}) (string, []interface{}) {
+	var disjuncts, equal []string
+	next := func(eq, past string) {
+		if past != "" {
+			disjuncts = append(disjuncts, "("+strings.Join(append(equal[:len(equal):len(equal)], past), " AND ")+")")
+		}
+		equal = append(equal, eq)
+	}
+	after := func(descending bool) string {
+		if descending != cursor.Backward {
+			return "<"
+		}
+		return ">"
+	}
+
+	for i, key := range keys {
+		expression := sortExpressions[key.Field]
+		value := cursor.Values[i]
+
+		if expression.nullable {
+			isNull := "(" + expression.sql + " IS NULL)"
+			args = append(args, value == nil)
+			next(fmt.Sprintf("%s = $%d", isNull, len(args)), fmt.Sprintf("%s %s $%d", isNull, after(false), len(args)))
+			if value == nil {
+				// Every row still tied on this key is NULL too, so the key cannot tell them apart
+				continue
+			}
+		}
+
+		args = append(args, *value)
+		placeholder := fmt.Sprintf("$%d", len(args))
+		if expression.sqlType != "STRING" {
+			placeholder += "::STRING::" + expression.sqlType
+		}
+		next(expression.sql+" = "+placeholder, fmt.Sprintf("%s %s %s", expression.sql, after(key.Descending), placeholder))
+	}
+
+	args = append(args, cursor.ID)
+	next("", fmt.Sprintf("s.id %s $%d", after(false), len(args)))
+
+	return "(" + strings.Join(disjuncts, " OR ") + ")", args
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+)
+
+// savePayload stores the raw payload of the stock stockID inside tx, replacing the stored one only if
+// the item changed, like mergeStagedPayloads. A stock without a payload leaves the stored one as it is.
+func savePayload(ctx context.Context, tx pgx.Tx, stockID uuid.UUID, payload *entities.StockPayload) error {
+	if payload == nil {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `
+        INSERT INTO stock_payloads (stock_id, batch_id, source, fetched_at, payload)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (stock_id) DO UPDATE
+        SET batch_id = excluded.batch_id, source = excluded.source,
+            fetched_at = excluded.fetched_at, payload = excluded.payload
+        WHERE stock_payloads.payload IS DISTINCT FROM excluded.payload
+    `, stockID, payload.BatchID, payload.Source, payload.FetchedAt, []byte(payload.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to store raw payload: %w", err)
+	}
+	return nil
+}
+
+// ListPayloads retrieves a page of raw payloads selected by filter, with the key of their stocks.
+func (r *stockRepository) ListPayloads(ctx context.Context, filter repositories.PayloadFilter) ([]repositories.StoredPayload, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		conditions = append(conditions, fmt.Sprintf("p.source = $%d", len(args)))
+	}
+	if filter.BatchID != "" {
+		args = append(args, filter.BatchID)
+		conditions = append(conditions, fmt.Sprintf("p.batch_id = $%d", len(args)))
+	}
+	if len(filter.Tickers) > 0 {
+		tickers := make([]string, len(filter.Tickers))
+		for i, ticker := range filter.Tickers {
+			tickers[i] = strings.ToUpper(ticker)
+		}
+		args = append(args, tickers)
+		conditions = append(conditions, fmt.Sprintf("s.ticker = ANY($%d)", len(args)))
+	}
+	if filter.After != uuid.Nil {
+		args = append(args, filter.After)
+		conditions = append(conditions, fmt.Sprintf("p.stock_id > $%d", len(args)))
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query := `
+        SELECT p.stock_id, p.batch_id, p.source, p.fetched_at, p.payload, s.ticker, s.event_time
+        FROM stock_payloads p
+        JOIN stocks s ON s.id = p.stock_id
+        ` + whereClause + `
+        ORDER BY p.stock_id
+        LIMIT $` + fmt.Sprint(len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query raw payloads: %w", err)
+	}
+	defer rows.Close()
+
+	var payloads []repositories.StoredPayload
+	for rows.Next() {
+		stored := repositories.StoredPayload{Payload: &entities.StockPayload{}}
+		var raw []byte
+		if err := rows.Scan(&stored.Payload.StockID, &stored.Payload.BatchID, &stored.Payload.Source,
+			&stored.Payload.FetchedAt, &raw, &stored.Ticker, &stored.EventTime); err != nil {
+			return nil, fmt.Errorf("failed to scan raw payload: %w", err)
+		}
+		stored.Payload.Payload = raw
+		payloads = append(payloads, stored)
+	}
+
+	return payloads, rows.Err()
+}
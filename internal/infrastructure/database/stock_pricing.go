@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"stock-tracker/internal/domain/repositories"
+)
+
+// pricingWhereClause filters stocks s by filter, numbering its arguments after args
+func pricingWhereClause(filter repositories.PricingFilter, args []interface{}) (string, []interface{}) {
+	var conditions []string
+
+	if !filter.Overwrite {
+		conditions = append(conditions, "s.price_close IS NULL")
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("s.event_time >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("s.event_time <= $%d", len(args)))
+	}
+	if len(filter.Tickers) > 0 {
+		tickers := make([]string, len(filter.Tickers))
+		for i, ticker := range filter.Tickers {
+			tickers[i] = strings.ToUpper(ticker)
+		}
+		args = append(args, tickers)
+		conditions = append(conditions, fmt.Sprintf("s.ticker = ANY($%d)", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ListPricingTickers retrieves the tickers having stocks selected by filter.
+func (r *stockRepository) ListPricingTickers(ctx context.Context, filter repositories.PricingFilter) ([]string, error) {
+	whereClause, args := pricingWhereClause(filter, nil)
+	query := `
+        SELECT DISTINCT s.ticker
+        FROM stocks s
+        ` + whereClause + `
+        ORDER BY s.ticker
+    `
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tickers to price: %w", err)
+	}
+	defer rows.Close()
+
+	var tickers []string
+	for rows.Next() {
+		var ticker string
+		if err := rows.Scan(&ticker); err != nil {
+			r.logger.Error("Failed to scan ticker row", "error", err)
+			continue
+		}
+		tickers = append(tickers, ticker)
+	}
+
+	return tickers, rows.Err()
+}
+
+// ListPricingCandidates retrieves the stocks of ticker selected by filter, oldest first.
+func (r *stockRepository) ListPricingCandidates(ctx context.Context, ticker string, filter repositories.PricingFilter) ([]repositories.PricingCandidate, error) {
+	filter.Tickers = nil
+	whereClause, args := pricingWhereClause(filter, []interface{}{ticker})
+	if whereClause == "" {
+		whereClause = "WHERE s.ticker = $1"
+	} else {
+		whereClause += " AND s.ticker = $1"
+	}
+
+	query := `
+        SELECT s.id, s.event_time
+        FROM stocks s
+        ` + whereClause + `
+        ORDER BY s.event_time
+    `
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stocks to price: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []repositories.PricingCandidate
+	for rows.Next() {
+		var candidate repositories.PricingCandidate
+		if err := rows.Scan(&candidate.ID, &candidate.EventTime); err != nil {
+			r.logger.Error("Failed to scan stock to price", "error", err)
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+// UpdateClosePrices stores the closing price and trading day of each stock in a single transaction.
+// Prices are enrichment rather than upstream corrections, so neither updated_at nor revisions change.
+func (r *stockRepository) UpdateClosePrices(ctx context.Context, prices []repositories.ClosePrice) (int, error) {
+	if len(prices) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE stocks SET price_close = $2, price_close_date = $3 WHERE id = $1`
+
+	updated := 0
+	for _, price := range prices {
+		result, err := tx.Exec(ctx, query, price.StockID, price.Close, price.Date)
+		if err != nil {
+			r.logger.Error("Failed to store close price", "error", err, "id", price.StockID)
+			return 0, fmt.Errorf("failed to store close price: %w", err)
+		}
+		updated += int(result.RowsAffected())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return updated, nil
+}
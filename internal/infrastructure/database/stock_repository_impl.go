@@ -2,11 +2,14 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"stock-tracker/internal/domain/entities"
@@ -16,11 +19,13 @@ import (
 )
 
 type stockRepository struct {
-	db     *pgxpool.Pool
-	logger logger.Logger
+	db       *pgxpool.Pool
+	logger   logger.Logger
+	rowByRow bool
 }
 
 // NewStockRepository creates a new instance of stockRepository implementing repositories.StockRepository.
+// Batch writes are loaded with COPY through a staging table.
 func NewStockRepository(db *pgxpool.Pool, logger logger.Logger) repositories.StockRepository {
 	return &stockRepository{
 		db:     db,
@@ -28,35 +33,91 @@ func NewStockRepository(db *pgxpool.Pool, logger logger.Logger) repositories.Sto
 	}
 }
 
-// Create inserts a new stock record into the database.
+// NewRowByRowStockRepository creates a stockRepository whose batch writes issue one statement per row.
+// It is kept as a baseline for benchmarking the COPY path.
+func NewRowByRowStockRepository(db *pgxpool.Pool, logger logger.Logger) repositories.StockRepository {
+	return &stockRepository{
+		db:       db,
+		logger:   logger,
+		rowByRow: true,
+	}
+}
+
+// stockColumns selects a stock from stocks s joined with brokers b, in the order scanStock reads them
+const stockColumns = `s.id, s.ticker, s.company, s.action, s.rating_from, s.rating_to,
+               s.target_from, s.target_to, s.event_time, s.price_close, s.price_close_date, s.source, s.created_at, s.updated_at,
+               s.action_type, s.rating_from_tier, s.rating_to_tier, s.currency,
+               b.id as broker_id, b.name as brokerage`
+
+// scanStock maps a row selected with stockColumns, scanning any columns selected after them into extra
+func scanStock(row pgx.Row, extra ...interface{}) (*entities.Stock, error) {
+	stock := &entities.Stock{}
+	dest := []interface{}{
+		&stock.ID, &stock.Ticker, &stock.Company, &stock.Action,
+		&stock.RatingFrom, &stock.RatingTo, &stock.TargetFrom, &stock.TargetTo,
+		&stock.EventTime, &stock.PriceClose, &stock.PriceCloseDate, &stock.Source, &stock.CreatedAt, &stock.UpdatedAt,
+		&stock.ActionType, &stock.RatingFromTier, &stock.RatingToTier, &stock.Currency,
+		&stock.BrokerID, &stock.Brokerage,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	return stock, nil
+}
+
+// Create inserts a new stock record into the database, with the events of ingesting it.
 func (r *stockRepository) Create(ctx context.Context, stock *entities.Stock) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.createStock(ctx, tx, stock); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// createStock inserts stock inside tx, with its domain events
+func (r *stockRepository) createStock(ctx context.Context, tx pgx.Tx, stock *entities.Stock) error {
 	query := `
-        INSERT INTO stocks (id, ticker, company, broker_id, action, rating_from, rating_to, 
-                           target_from, target_to, event_time, price_close, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        INSERT INTO stocks (id, ticker, company, broker_id, action, rating_from, rating_to,
+                           target_from, target_to, event_time, price_close, created_at, updated_at, source,
+                           action_type, rating_from_tier, rating_to_tier, currency)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
     `
 
-	_, err := r.db.Exec(ctx, query,
+	_, err := tx.Exec(ctx, query,
 		stock.ID, stock.Ticker, stock.Company, stock.BrokerID, stock.Action,
 		stock.RatingFrom, stock.RatingTo, stock.TargetFrom, stock.TargetTo,
-		stock.EventTime, stock.PriceClose, stock.CreatedAt, stock.UpdatedAt,
+		stock.EventTime, stock.PriceClose, stock.CreatedAt, stock.UpdatedAt, stock.Source,
+		stock.ActionType, stock.RatingFromTier, stock.RatingToTier, stock.Currency,
 	)
 
 	if err != nil {
 		r.logger.Error("Failed to create stock", "error", err, "ticker", stock.Ticker)
-		return fmt.Errorf("failed to create stock: %w", err)
+		return fmt.Errorf("failed to create stock: %w", translateUniqueViolation(err))
 	}
 
-	return nil
+	return appendOutbox(ctx, tx, entities.IngestedStockEvents(stock))
 }
 
-// BulkCreate inserts multiple stock records in a single transaction.
+// BulkCreate inserts multiple stock records in a single transaction, skipping events that already exist.
 // If any insert fails, the transaction is rolled back.
 func (r *stockRepository) BulkCreate(ctx context.Context, stocks []*entities.Stock) error {
 	if len(stocks) == 0 {
 		return nil
 	}
+	if r.rowByRow {
+		return r.bulkCreateRows(ctx, stocks)
+	}
+	return r.bulkCreateStaged(ctx, stocks)
+}
 
+func (r *stockRepository) bulkCreateRows(ctx context.Context, stocks []*entities.Stock) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -64,9 +125,10 @@ func (r *stockRepository) BulkCreate(ctx context.Context, stocks []*entities.Sto
 	defer tx.Rollback(ctx)
 
 	query := `
-        INSERT INTO stocks (id, ticker, company, broker_id, action, rating_from, rating_to, 
-                           target_from, target_to, event_time, price_close, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        INSERT INTO stocks (id, ticker, company, broker_id, action, rating_from, rating_to,
+                           target_from, target_to, event_time, price_close, created_at, updated_at, source,
+                           action_type, rating_from_tier, rating_to_tier, currency)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
         ON CONFLICT (ticker, event_time) DO NOTHING
     `
 
@@ -74,7 +136,8 @@ func (r *stockRepository) BulkCreate(ctx context.Context, stocks []*entities.Sto
 		_, err := tx.Exec(ctx, query,
 			stock.ID, stock.Ticker, stock.Company, stock.BrokerID, stock.Action,
 			stock.RatingFrom, stock.RatingTo, stock.TargetFrom, stock.TargetTo,
-			stock.EventTime, stock.PriceClose, stock.CreatedAt, stock.UpdatedAt,
+			stock.EventTime, stock.PriceClose, stock.CreatedAt, stock.UpdatedAt, stock.Source,
+			stock.ActionType, stock.RatingFromTier, stock.RatingToTier, stock.Currency,
 		)
 		if err != nil {
 			r.logger.Error("Failed to insert stock in batch", "error", err, "ticker", stock.Ticker)
@@ -93,27 +156,63 @@ func (r *stockRepository) BulkCreate(ctx context.Context, stocks []*entities.Sto
 // GetAll retrieves stocks from the database based on the provided filters and returns paginated results.
 func (r *stockRepository) GetAll(ctx context.Context, filters valueObjects.StockFilters) ([]*entities.Stock, *valueObjects.Pagination, error) {
 	filters.SetDefaults()
+	keys := filters.SortKeys()
 
-	whereClause, args := r.buildWhereClause(filters)
-	countQuery := "SELECT COUNT(*) FROM stocks s LEFT JOIN brokers b ON s.broker_id = b.id" + whereClause
+	var cursor *stockCursor
+	if filters.Cursor != "" {
+		var err error
+		if cursor, err = decodeStockCursor(filters.Cursor, keys); err != nil {
+			return nil, nil, err
+		}
+	}
 
-	r.logger.Info("Counting stocks", "query=%s", countQuery)
-	var totalItems int
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&totalItems)
+	source, args := "stocks s", []interface{}{}
+	if filters.AsOf != nil {
+		source = stocksAsOfSource(1)
+		args = append(args, *filters.AsOf)
+	}
+
+	whereClause, args := r.buildWhereClause(filters, args)
+
+	totalItems := valueObjects.TotalUnknown
+	if cursor == nil || filters.IncludeTotal {
+		countQuery := "SELECT COUNT(*) FROM " + source + " LEFT JOIN brokers b ON s.broker_id = b.id" + whereClause
+
+		r.logger.Info("Counting stocks", "query=%s", countQuery)
+		err := r.db.QueryRow(ctx, countQuery, args...).Scan(&totalItems)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to count stocks: %w", err)
+		}
+	}
+
+	backward := cursor != nil && cursor.Backward
+	orderBy, err := orderByClause(keys, backward)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to count stocks: %w", err)
+		return nil, nil, err
+	}
+
+	var page string
+	if cursor != nil {
+		var condition string
+		condition, args = keysetCondition(keys, cursor, args)
+		if whereClause == "" {
+			whereClause = " WHERE " + condition
+		} else {
+			whereClause += " AND " + condition
+		}
+		// One more stock than the page holds tells whether there is another page
+		page = fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, filters.Limit+1)
+	} else {
+		page = fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, filters.Limit, filters.Offset)
 	}
 
 	query := `
-        SELECT s.id, s.ticker, s.company, s.action, s.rating_from, s.rating_to,
-               s.target_from, s.target_to, s.event_time, s.price_close, s.created_at, s.updated_at,
-               b.id as broker_id, b.name as brokerage
-        FROM stocks s
+        SELECT ` + stockColumns + sortValueColumns(keys) + `
+        FROM ` + source + `
         LEFT JOIN brokers b ON s.broker_id = b.id
-    ` + whereClause + fmt.Sprintf(" ORDER BY s.%s %s LIMIT $%d OFFSET $%d",
-		filters.SortBy, strings.ToUpper(filters.SortOrder), len(args)+1, len(args)+2)
-
-	args = append(args, filters.Limit, filters.Offset)
+    ` + whereClause + orderBy + page
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -122,42 +221,85 @@ func (r *stockRepository) GetAll(ctx context.Context, filters valueObjects.Stock
 	defer rows.Close()
 
 	var stocks []*entities.Stock
+	var sortValues [][]*string
 	for rows.Next() {
-		stock := &entities.Stock{}
-		err := rows.Scan(
-			&stock.ID, &stock.Ticker, &stock.Company, &stock.Action,
-			&stock.RatingFrom, &stock.RatingTo, &stock.TargetFrom, &stock.TargetTo,
-			&stock.EventTime, &stock.PriceClose, &stock.CreatedAt, &stock.UpdatedAt,
-			&stock.BrokerID, &stock.Brokerage,
-		)
+		values := make([]*string, len(keys))
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		stock, err := scanStock(rows, dest...)
 		if err != nil {
 			r.logger.Error("Failed to scan stock row", "error", err)
 			continue
 		}
 		stocks = append(stocks, stock)
+		sortValues = append(sortValues, values)
 	}
 
 	pagination := &valueObjects.Pagination{
-		Page:       (filters.Offset / filters.Limit) + 1,
 		Limit:      filters.Limit,
 		TotalItems: totalItems,
-		TotalPages: (totalItems + filters.Limit - 1) / filters.Limit,
+		TotalPages: valueObjects.TotalUnknown,
+	}
+	if totalItems != valueObjects.TotalUnknown {
+		pagination.TotalPages = (totalItems + filters.Limit - 1) / filters.Limit
+	}
+
+	if cursor != nil {
+		more := len(stocks) > filters.Limit
+		if more {
+			stocks, sortValues = stocks[:filters.Limit], sortValues[:filters.Limit]
+		}
+		if backward {
+			slices.Reverse(stocks)
+			slices.Reverse(sortValues)
+			pagination.HasPrev, pagination.HasNext = more, true
+		} else {
+			pagination.HasNext, pagination.HasPrev = more, true
+		}
+	} else {
+		pagination.Page = (filters.Offset / filters.Limit) + 1
+		pagination.HasNext = pagination.Page < pagination.TotalPages
+		pagination.HasPrev = pagination.Page > 1
+	}
+
+	if len(stocks) > 0 {
+		last := len(stocks) - 1
+		if pagination.HasNext {
+			pagination.NextCursor = encodeStockCursor(keys, sortValues[last], stocks[last].ID, false)
+		}
+		if pagination.HasPrev {
+			pagination.PrevCursor = encodeStockCursor(keys, sortValues[0], stocks[0].ID, true)
+		}
 	}
-	pagination.HasNext = pagination.Page < pagination.TotalPages
-	pagination.HasPrev = pagination.Page > 1
 
 	return stocks, pagination, nil
 }
 
-// buildWhereClause constructs the SQL WHERE clause and its arguments based on the provided filters.
-func (r *stockRepository) buildWhereClause(filters valueObjects.StockFilters) (string, []interface{}) {
+// buildWhereClause constructs the SQL WHERE clause based on the provided filters, appending its arguments to args.
+func (r *stockRepository) buildWhereClause(filters valueObjects.StockFilters, args []interface{}) (string, []interface{}) {
 	var conditions []string
-	var args []interface{}
-	argIndex := 1
+	argIndex := len(args) + 1
 
 	if filters.Ticker != "" {
-		conditions = append(conditions, fmt.Sprintf("s.ticker ILIKE $%d", argIndex))
-		args = append(args, "%"+filters.Ticker+"%")
+		if filters.TickerMatch == valueObjects.TickerMatchExact {
+			conditions = append(conditions, fmt.Sprintf("s.ticker = $%d", argIndex))
+			args = append(args, strings.ToUpper(filters.Ticker))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("s.ticker ILIKE $%d", argIndex))
+			args = append(args, "%"+filters.Ticker+"%")
+		}
+		argIndex++
+	}
+
+	if len(filters.Tickers) > 0 {
+		tickers := make([]string, len(filters.Tickers))
+		for i, ticker := range filters.Tickers {
+			tickers[i] = strings.ToUpper(ticker)
+		}
+		conditions = append(conditions, fmt.Sprintf("s.ticker = ANY($%d)", argIndex))
+		args = append(args, tickers)
 		argIndex++
 	}
 
@@ -173,6 +315,70 @@ func (r *stockRepository) buildWhereClause(filters valueObjects.StockFilters) (s
 		argIndex++
 	}
 
+	if len(filters.Brokerages) > 0 {
+		brokerages := make([]string, len(filters.Brokerages))
+		for i, brokerage := range filters.Brokerages {
+			brokerages[i] = strings.ToLower(brokerage)
+		}
+		conditions = append(conditions, fmt.Sprintf("lower(b.name) = ANY($%d)", argIndex))
+		args = append(args, brokerages)
+		argIndex++
+	}
+
+	if filters.Action != "" {
+		conditions = append(conditions, fmt.Sprintf("s.action ILIKE $%d", argIndex))
+		args = append(args, "%"+filters.Action+"%")
+		argIndex++
+	}
+
+	if filters.ActionType != entities.ActionTypeUnknown {
+		conditions = append(conditions, fmt.Sprintf("s.action_type = $%d", argIndex))
+		args = append(args, filters.ActionType)
+		argIndex++
+	}
+
+	if filters.RatingFrom != "" {
+		conditions = append(conditions, fmt.Sprintf("lower(s.rating_from) = lower($%d)", argIndex))
+		args = append(args, filters.RatingFrom)
+		argIndex++
+	}
+
+	if filters.RatingTo != "" {
+		conditions = append(conditions, fmt.Sprintf("lower(s.rating_to) = lower($%d)", argIndex))
+		args = append(args, filters.RatingTo)
+		argIndex++
+	}
+
+	if filters.RatingDirection != "" {
+		from, to := ratingScoreSQL("s.rating_from_tier"), ratingScoreSQL("s.rating_to_tier")
+		switch filters.RatingDirection {
+		case valueObjects.RatingDirectionUpgrade:
+			conditions = append(conditions, to+" > "+from)
+		case valueObjects.RatingDirectionDowngrade:
+			conditions = append(conditions, to+" < "+from)
+		case valueObjects.RatingDirectionUnchanged:
+			conditions = append(conditions, to+" = "+from)
+		}
+	}
+
+	if filters.MinTargetChange != nil {
+		conditions = append(conditions, fmt.Sprintf("s.target_from > 0 AND %s >= $%d", targetChangeSQL, argIndex))
+		args = append(args, *filters.MinTargetChange)
+		argIndex++
+	}
+
+	if filters.MaxTargetChange != nil {
+		conditions = append(conditions, fmt.Sprintf("s.target_from > 0 AND %s <= $%d", targetChangeSQL, argIndex))
+		args = append(args, *filters.MaxTargetChange)
+		argIndex++
+	}
+
+	if filters.Source != "" {
+		conditions = append(conditions, fmt.Sprintf("s.source = $%d", argIndex))
+		args = append(args, filters.Source)
+		argIndex++
+	}
+
 	if filters.DateFrom != nil {
 		conditions = append(conditions, fmt.Sprintf("s.event_time >= $%d", argIndex))
 		args = append(args, *filters.DateFrom)
@@ -192,12 +398,23 @@ func (r *stockRepository) buildWhereClause(filters valueObjects.StockFilters) (s
 	return " WHERE " + strings.Join(conditions, " AND "), args
 }
 
+// targetChangeSQL is the change from target_from to target_to of a stock s, in percent of target_from
+const targetChangeSQL = "(s.target_to - s.target_from) / s.target_from * 100"
+
+// ratingScoreSQL maps a rating tier column onto the score of the tier, or NULL for a rating no tier covers,
+// so comparisons between two scores only hold when both ratings were normalized
+func ratingScoreSQL(column string) string {
+	var cases strings.Builder
+	for _, tier := range entities.RatingTiers {
+		fmt.Fprintf(&cases, " WHEN '%s' THEN %g", tier, tier.Score())
+	}
+	return "(CASE " + column + cases.String() + " END)"
+}
+
 // GetRecentByTickers retrieves recent stock records for all tickers since the given time.
 func (r *stockRepository) GetRecentByTickers(ctx context.Context, since time.Time) (map[string][]*entities.Stock, error) {
 	query := `
-        SELECT s.id, s.ticker, s.company, s.action, s.rating_from, s.rating_to,
-               s.target_from, s.target_to, s.event_time, s.price_close, s.created_at, s.updated_at,
-               b.id as broker_id, b.name as brokerage
+        SELECT ` + stockColumns + `
         FROM stocks s
         LEFT JOIN brokers b ON s.broker_id = b.id
         WHERE s.event_time >= $1
@@ -213,13 +430,7 @@ func (r *stockRepository) GetRecentByTickers(ctx context.Context, since time.Tim
 	result := make(map[string][]*entities.Stock)
 
 	for rows.Next() {
-		stock := &entities.Stock{}
-		err := rows.Scan(
-			&stock.ID, &stock.Ticker, &stock.Company, &stock.Action,
-			&stock.RatingFrom, &stock.RatingTo, &stock.TargetFrom, &stock.TargetTo,
-			&stock.EventTime, &stock.PriceClose, &stock.CreatedAt, &stock.UpdatedAt,
-			&stock.BrokerID, &stock.Brokerage,
-		)
+		stock, err := scanStock(rows)
 		if err != nil {
 			r.logger.Error("Failed to scan stock row", "error", err)
 			continue
@@ -231,25 +442,52 @@ func (r *stockRepository) GetRecentByTickers(ctx context.Context, since time.Tim
 	return result, nil
 }
 
+// GetNearbyEvents retrieves the stored events of the given tickers whose event time falls between from and to.
+func (r *stockRepository) GetNearbyEvents(ctx context.Context, tickers []string, from, to time.Time) ([]*entities.Stock, error) {
+	if len(tickers) == 0 {
+		return nil, nil
+	}
+
+	query := `
+        SELECT ` + stockColumns + `
+        FROM stocks s
+        LEFT JOIN brokers b ON s.broker_id = b.id
+        WHERE s.ticker = ANY($1) AND s.event_time BETWEEN $2 AND $3
+    `
+
+	rows, err := r.db.Query(ctx, query, tickers, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nearby stocks: %w", err)
+	}
+	defer rows.Close()
+
+	var stocks []*entities.Stock
+	for rows.Next() {
+		stock, err := scanStock(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan stock row", "error", err)
+			continue
+		}
+		stocks = append(stocks, stock)
+	}
+
+	return stocks, nil
+}
+
 // GetByID retrieves a stock by its ID.
 func (r *stockRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Stock, error) {
 	query := `
-        SELECT s.id, s.ticker, s.company, s.action, s.rating_from, s.rating_to,
-               s.target_from, s.target_to, s.event_time, s.price_close, s.created_at, s.updated_at,
-               b.id as broker_id, b.name as brokerage
+        SELECT ` + stockColumns + `
         FROM stocks s
         LEFT JOIN brokers b ON s.broker_id = b.id
         WHERE s.id = $1
     `
 
-	stock := &entities.Stock{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&stock.ID, &stock.Ticker, &stock.Company, &stock.Action,
-		&stock.RatingFrom, &stock.RatingTo, &stock.TargetFrom, &stock.TargetTo,
-		&stock.EventTime, &stock.PriceClose, &stock.CreatedAt, &stock.UpdatedAt,
-		&stock.BrokerID, &stock.Brokerage,
-	)
+	stock, err := scanStock(r.db.QueryRow(ctx, query, id))
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("stock %s: %w", id, repositories.ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stock by ID: %w", err)
 	}
@@ -257,49 +495,131 @@ func (r *stockRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.
 	return stock, nil
 }
 
-// Update updates an existing stock record.
+// Update updates an existing stock record. A change to its analyst fields is recorded as a revision and
+// a StockEventRevised event, as when upstream corrects it.
 func (r *stockRepository) Update(ctx context.Context, stock *entities.Stock) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.updateStock(ctx, tx, stock); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// updateStock overwrites stock inside tx, recording the prior values of revised fields and the domain event
+// of the revision
+func (r *stockRepository) updateStock(ctx context.Context, tx pgx.Tx, stock *entities.Stock) error {
+	existing, err := lockRevisableStock(ctx, tx, `id = $1`, stock.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("stock %s: %w", stock.ID, repositories.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update stock: %w", err)
+	}
+
+	revised := stock.HasRevisedFields(existing)
+	if revised {
+		if err := insertRevision(ctx, tx, entities.NewStockRevision(existing, stock.UpdatedAt)); err != nil {
+			return err
+		}
+	}
+
 	query := `
         UPDATE stocks 
         SET ticker = $2, company = $3, broker_id = $4, action = $5, 
             rating_from = $6, rating_to = $7, target_from = $8, target_to = $9,
-            event_time = $10, price_close = $11, updated_at = $12
+            event_time = $10, price_close = $11, updated_at = $12,
+            action_type = $13, rating_from_tier = $14, rating_to_tier = $15, price_close_date = $16, currency = $17
         WHERE id = $1
     `
 
-	_, err := r.db.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		stock.ID, stock.Ticker, stock.Company, stock.BrokerID, stock.Action,
 		stock.RatingFrom, stock.RatingTo, stock.TargetFrom, stock.TargetTo,
 		stock.EventTime, stock.PriceClose, stock.UpdatedAt,
+		stock.ActionType, stock.RatingFromTier, stock.RatingToTier, stock.PriceCloseDate, stock.Currency,
 	)
 
 	if err != nil {
 		r.logger.Error("Failed to update stock", "error", err, "ticker", stock.Ticker)
-		return fmt.Errorf("failed to update stock: %w", err)
+		return fmt.Errorf("failed to update stock: %w", translateUniqueViolation(err))
 	}
 
+	if revised {
+		return appendOutbox(ctx, tx, []*entities.DomainEvent{entities.RevisedStockEvent(stock)})
+	}
 	return nil
 }
 
-// Delete removes a stock record by ID.
+// Delete removes a stock record by ID, with its revisions and payload.
 func (r *stockRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM stocks WHERE id = $1`
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	_, err := r.db.Exec(ctx, query, id)
+	if err := r.deleteStock(ctx, tx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// deleteStock removes the stock with the given ID inside tx
+func (r *stockRepository) deleteStock(ctx context.Context, tx pgx.Tx, id uuid.UUID) error {
+	result, err := tx.Exec(ctx, `DELETE FROM stocks WHERE id = $1`, id)
 	if err != nil {
 		r.logger.Error("Failed to delete stock", "error", err, "id", id)
 		return fmt.Errorf("failed to delete stock: %w", err)
 	}
 
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("stock %s: %w", id, repositories.ErrNotFound)
+	}
+
 	return nil
 }
 
+// ApplyChange writes an admin's change to a stock event and stores its audit record in the same
+// transaction, so no change is made without a record of it.
+func (r *stockRepository) ApplyChange(ctx context.Context, change *entities.StockChange) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	switch change.Kind {
+	case entities.StockChangeCreated:
+		err = r.createStock(ctx, tx, change.After)
+	case entities.StockChangeUpdated:
+		err = r.updateStock(ctx, tx, change.After)
+	case entities.StockChangeDeleted:
+		err = r.deleteStock(ctx, tx, change.StockID)
+	default:
+		err = fmt.Errorf("unknown stock change kind %q", change.Kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertStockChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 // GetByTicker retrieves all stocks for a specific ticker.
 func (r *stockRepository) GetByTicker(ctx context.Context, ticker string) ([]*entities.Stock, error) {
 	query := `
-        SELECT s.id, s.ticker, s.company, s.action, s.rating_from, s.rating_to,
-               s.target_from, s.target_to, s.event_time, s.price_close, s.created_at, s.updated_at,
-               b.id as broker_id, b.name as brokerage
+        SELECT ` + stockColumns + `
         FROM stocks s
         LEFT JOIN brokers b ON s.broker_id = b.id
         WHERE s.ticker ILIKE $1
@@ -314,13 +634,70 @@ func (r *stockRepository) GetByTicker(ctx context.Context, ticker string) ([]*en
 
 	var stocks []*entities.Stock
 	for rows.Next() {
-		stock := &entities.Stock{}
-		err := rows.Scan(
-			&stock.ID, &stock.Ticker, &stock.Company, &stock.Action,
-			&stock.RatingFrom, &stock.RatingTo, &stock.TargetFrom, &stock.TargetTo,
-			&stock.EventTime, &stock.PriceClose, &stock.CreatedAt, &stock.UpdatedAt,
-			&stock.BrokerID, &stock.Brokerage,
-		)
+		stock, err := scanStock(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan stock row", "error", err)
+			continue
+		}
+		stocks = append(stocks, stock)
+	}
+
+	return stocks, nil
+}
+
+// GetByTickerWithCredibility retrieves all stocks for a specific ticker with the credibility score of their brokers.
+func (r *stockRepository) GetByTickerWithCredibility(ctx context.Context, ticker string) ([]*entities.Stock, map[uuid.UUID]float64, error) {
+	query := `
+        SELECT ` + stockColumns + `, b.credibility_score
+        FROM stocks s
+        LEFT JOIN brokers b ON s.broker_id = b.id
+        WHERE s.ticker ILIKE $1
+        ORDER BY s.event_time DESC
+    `
+
+	rows, err := r.db.Query(ctx, query, ticker)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query stocks by ticker: %w", err)
+	}
+	defer rows.Close()
+
+	var stocks []*entities.Stock
+	credibility := make(map[uuid.UUID]float64)
+	for rows.Next() {
+		var score *float64
+		stock, err := scanStock(rows, &score)
+		if err != nil {
+			r.logger.Error("Failed to scan stock row", "error", err)
+			continue
+		}
+		if score != nil {
+			credibility[stock.BrokerID] = *score
+		}
+		stocks = append(stocks, stock)
+	}
+
+	return stocks, credibility, nil
+}
+
+// GetByTickerAsOf retrieves all stocks for a specific ticker with the values they had at asOf.
+func (r *stockRepository) GetByTickerAsOf(ctx context.Context, ticker string, asOf time.Time) ([]*entities.Stock, error) {
+	query := `
+        SELECT ` + stockColumns + `
+        FROM ` + stocksAsOfSource(2) + `
+        LEFT JOIN brokers b ON s.broker_id = b.id
+        WHERE s.ticker ILIKE $1
+        ORDER BY s.event_time DESC
+    `
+
+	rows, err := r.db.Query(ctx, query, ticker, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stocks by ticker as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	var stocks []*entities.Stock
+	for rows.Next() {
+		stock, err := scanStock(rows)
 		if err != nil {
 			r.logger.Error("Failed to scan stock row", "error", err)
 			continue
@@ -334,9 +711,7 @@ func (r *stockRepository) GetByTicker(ctx context.Context, ticker string) ([]*en
 // GetLatestByTicker retrieves the most recent stock record for a specific ticker.
 func (r *stockRepository) GetLatestByTicker(ctx context.Context, ticker string) (*entities.Stock, error) {
 	query := `
-        SELECT s.id, s.ticker, s.company, s.action, s.rating_from, s.rating_to,
-               s.target_from, s.target_to, s.event_time, s.price_close, s.created_at, s.updated_at,
-               b.id as broker_id, b.name as brokerage
+        SELECT ` + stockColumns + `
         FROM stocks s
         LEFT JOIN brokers b ON s.broker_id = b.id
         WHERE s.ticker ILIKE $1
@@ -344,13 +719,7 @@ func (r *stockRepository) GetLatestByTicker(ctx context.Context, ticker string)
         LIMIT 1
     `
 
-	stock := &entities.Stock{}
-	err := r.db.QueryRow(ctx, query, ticker).Scan(
-		&stock.ID, &stock.Ticker, &stock.Company, &stock.Action,
-		&stock.RatingFrom, &stock.RatingTo, &stock.TargetFrom, &stock.TargetTo,
-		&stock.EventTime, &stock.PriceClose, &stock.CreatedAt, &stock.UpdatedAt,
-		&stock.BrokerID, &stock.Brokerage,
-	)
+	stock, err := scanStock(r.db.QueryRow(ctx, query, ticker))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest stock by ticker: %w", err)
@@ -364,7 +733,13 @@ func (r *stockRepository) BulkUpdate(ctx context.Context, stocks []*entities.Sto
 	if len(stocks) == 0 {
 		return nil
 	}
+	if r.rowByRow {
+		return r.bulkUpdateRows(ctx, stocks)
+	}
+	return r.bulkUpdateStaged(ctx, stocks)
+}
 
+func (r *stockRepository) bulkUpdateRows(ctx context.Context, stocks []*entities.Stock) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -375,7 +750,8 @@ func (r *stockRepository) BulkUpdate(ctx context.Context, stocks []*entities.Sto
         UPDATE stocks 
         SET ticker = $2, company = $3, broker_id = $4, action = $5, 
             rating_from = $6, rating_to = $7, target_from = $8, target_to = $9,
-            event_time = $10, price_close = $11, updated_at = $12
+            event_time = $10, price_close = $11, updated_at = $12,
+            action_type = $13, rating_from_tier = $14, rating_to_tier = $15, price_close_date = $16, currency = $17
         WHERE id = $1
     `
 
@@ -384,6 +760,7 @@ func (r *stockRepository) BulkUpdate(ctx context.Context, stocks []*entities.Sto
 			stock.ID, stock.Ticker, stock.Company, stock.BrokerID, stock.Action,
 			stock.RatingFrom, stock.RatingTo, stock.TargetFrom, stock.TargetTo,
 			stock.EventTime, stock.PriceClose, stock.UpdatedAt,
+			stock.ActionType, stock.RatingFromTier, stock.RatingToTier, stock.PriceCloseDate, stock.Currency,
 		)
 		if err != nil {
 			r.logger.Error("Failed to update stock in batch", "error", err, "ticker", stock.Ticker)
@@ -399,12 +776,241 @@ func (r *stockRepository) BulkUpdate(ctx context.Context, stocks []*entities.Sto
 	return nil
 }
 
-// GetTopMoversByTarget retrieves stocks with the highest target price changes.
+// BulkUpsert inserts new stock events and applies upstream corrections to existing ones in a single transaction.
+// Before a corrected event is updated, its prior values are written to stock_revisions. The batch is merged
+// set-based first; if that fails, it is retried row by row so only the offending stocks are rejected.
+func (r *stockRepository) BulkUpsert(ctx context.Context, stocks []*entities.Stock) (*repositories.UpsertResult, error) {
+	if len(stocks) == 0 {
+		return &repositories.UpsertResult{}, nil
+	}
+
+	if !r.rowByRow {
+		result, err := r.bulkUpsertStaged(ctx, stocks)
+		if err == nil || ctx.Err() != nil {
+			return result, err
+		}
+		r.logger.Warn("Staged upsert failed, retrying row by row", "count", len(stocks), "error", err)
+	}
+
+	return r.bulkUpsertRows(ctx, stocks)
+}
+
+// bulkUpsertRows applies each stock under its own savepoint, so a stock that fails is rolled back
+// and reported without discarding the rest of the batch.
+func (r *stockRepository) bulkUpsertRows(ctx context.Context, stocks []*entities.Stock) (*repositories.UpsertResult, error) {
+	result := &repositories.UpsertResult{}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, stock := range stocks {
+		outcome, err := r.upsertStockIsolated(ctx, tx, stock)
+		if err != nil {
+			r.logger.Error("Failed to upsert stock in batch", "error", err, "ticker", stock.Ticker)
+			result.Failed++
+			result.Failures = append(result.Failures, repositories.UpsertFailure{Stock: stock, Err: err})
+			continue
+		}
+
+		switch outcome {
+		case upsertInserted:
+			result.Inserted++
+		case upsertUpdated:
+			result.Updated++
+		default:
+			result.Duplicates++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Info("Successfully upserted stocks batch", "inserted", result.Inserted, "updated", result.Updated, "duplicates", result.Duplicates, "failed", result.Failed)
+	return result, nil
+}
+
+// upsertStockIsolated applies a single stock event under a savepoint of tx, rolling back to it on failure
+// so the outer transaction stays usable for the rest of the batch.
+func (r *stockRepository) upsertStockIsolated(ctx context.Context, tx pgx.Tx, stock *entities.Stock) (upsertOutcome, error) {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return upsertUnchanged, fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	outcome, err := r.upsertStock(ctx, savepoint, stock)
+	if err != nil {
+		if rollbackErr := savepoint.Rollback(ctx); rollbackErr != nil {
+			return upsertUnchanged, fmt.Errorf("failed to roll back to savepoint: %w", rollbackErr)
+		}
+		return upsertUnchanged, err
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return upsertUnchanged, fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	return outcome, nil
+}
+
+type upsertOutcome int
+
+const (
+	upsertUnchanged upsertOutcome = iota
+	upsertInserted
+	upsertUpdated
+)
+
+// upsertStock applies a single stock event inside tx, locking the existing row while it is compared.
+func (r *stockRepository) upsertStock(ctx context.Context, tx pgx.Tx, stock *entities.Stock) (upsertOutcome, error) {
+	existing, err := lockRevisableStock(ctx, tx, `ticker = $1 AND event_time = $2`, stock.Ticker, stock.EventTime)
+	if errors.Is(err, pgx.ErrNoRows) {
+		tag, err := tx.Exec(ctx, `
+            INSERT INTO stocks (id, ticker, company, broker_id, action, rating_from, rating_to,
+                               target_from, target_to, event_time, price_close, created_at, updated_at, source,
+                               action_type, rating_from_tier, rating_to_tier, currency)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+            ON CONFLICT (ticker, event_time) DO NOTHING
+        `,
+			stock.ID, stock.Ticker, stock.Company, stock.BrokerID, stock.Action,
+			stock.RatingFrom, stock.RatingTo, stock.TargetFrom, stock.TargetTo,
+			stock.EventTime, stock.PriceClose, stock.CreatedAt, stock.UpdatedAt, stock.Source,
+			stock.ActionType, stock.RatingFromTier, stock.RatingToTier, stock.Currency,
+		)
+		if err != nil {
+			return upsertUnchanged, err
+		}
+		if tag.RowsAffected() == 0 {
+			return upsertUnchanged, nil
+		}
+		if err := savePayload(ctx, tx, stock.ID, stock.Payload); err != nil {
+			return upsertUnchanged, err
+		}
+		if err := appendOutbox(ctx, tx, entities.IngestedStockEvents(stock)); err != nil {
+			return upsertUnchanged, err
+		}
+		return upsertInserted, nil
+	}
+	if err != nil {
+		return upsertUnchanged, err
+	}
+
+	if err := savePayload(ctx, tx, existing.ID, stock.Payload); err != nil {
+		return upsertUnchanged, err
+	}
+
+	if !stock.HasRevisedFields(existing) {
+		return upsertUnchanged, nil
+	}
+
+	now := time.Now()
+	if err := insertRevision(ctx, tx, entities.NewStockRevision(existing, now)); err != nil {
+		return upsertUnchanged, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE stocks
+        SET company = $2, broker_id = $3, action = $4, rating_from = $5, rating_to = $6,
+            target_from = $7, target_to = $8, updated_at = $9, source = $10,
+            action_type = $11, rating_from_tier = $12, rating_to_tier = $13, currency = $14
+        WHERE id = $1
+    `,
+		existing.ID, stock.Company, stock.BrokerID, stock.Action, stock.RatingFrom, stock.RatingTo,
+		stock.TargetFrom, stock.TargetTo, now, stock.Source,
+		stock.ActionType, stock.RatingFromTier, stock.RatingToTier, stock.Currency,
+	)
+	if err != nil {
+		return upsertUnchanged, err
+	}
+
+	revised := *stock
+	revised.ID = existing.ID
+	if err := appendOutbox(ctx, tx, []*entities.DomainEvent{entities.RevisedStockEvent(&revised)}); err != nil {
+		return upsertUnchanged, err
+	}
+
+	return upsertUpdated, nil
+}
+
+// lockRevisableStock reads the analyst fields of the stock matching where inside tx, locking its row
+// until tx ends. It returns pgx.ErrNoRows if there is none.
+func lockRevisableStock(ctx context.Context, tx pgx.Tx, where string, args ...interface{}) (*entities.Stock, error) {
+	existing := &entities.Stock{}
+	err := tx.QueryRow(ctx, `
+        SELECT id, company, broker_id, action, rating_from, rating_to,
+               target_from, target_to, created_at, updated_at,
+               action_type, rating_from_tier, rating_to_tier, currency
+        FROM stocks
+        WHERE `+where+`
+        FOR UPDATE
+    `, args...).Scan(
+		&existing.ID, &existing.Company, &existing.BrokerID, &existing.Action,
+		&existing.RatingFrom, &existing.RatingTo, &existing.TargetFrom, &existing.TargetTo,
+		&existing.CreatedAt, &existing.UpdatedAt,
+		&existing.ActionType, &existing.RatingFromTier, &existing.RatingToTier, &existing.Currency,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// insertRevision records the values a stock had before a correction inside tx
+func insertRevision(ctx context.Context, tx pgx.Tx, revision *entities.StockRevision) error {
+	_, err := tx.Exec(ctx, `
+        INSERT INTO stock_revisions (id, stock_id, company, broker_id, action, rating_from, rating_to,
+                                     target_from, target_to, valid_from, valid_to,
+                                     action_type, rating_from_tier, rating_to_tier, currency)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+    `,
+		revision.ID, revision.StockID, revision.Company, revision.BrokerID, revision.Action,
+		revision.RatingFrom, revision.RatingTo, revision.TargetFrom, revision.TargetTo,
+		revision.ValidFrom, revision.ValidTo,
+		revision.ActionType, revision.RatingFromTier, revision.RatingToTier, revision.Currency,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return nil
+}
+
+// stocksAsOfSource returns a FROM source aliased as s with the stocks table's columns, holding the values
+// each stock had at asOf: stocks created after asOf are left out, and stocks corrected since then take
+// their values from the earliest revision that was still current at asOf.
+func stocksAsOfSource(asOfArg int) string {
+	return fmt.Sprintf(`(
+            SELECT st.id, st.ticker, st.event_time, st.price_close, st.price_close_date, st.source, st.created_at,
+                   CASE WHEN rv.id IS NULL THEN st.company ELSE rv.company END AS company,
+                   CASE WHEN rv.id IS NULL THEN st.broker_id ELSE rv.broker_id END AS broker_id,
+                   CASE WHEN rv.id IS NULL THEN st.action ELSE rv.action END AS action,
+                   CASE WHEN rv.id IS NULL THEN st.rating_from ELSE rv.rating_from END AS rating_from,
+                   CASE WHEN rv.id IS NULL THEN st.rating_to ELSE rv.rating_to END AS rating_to,
+                   CASE WHEN rv.id IS NULL THEN st.target_from ELSE rv.target_from END AS target_from,
+                   CASE WHEN rv.id IS NULL THEN st.target_to ELSE rv.target_to END AS target_to,
+                   CASE WHEN rv.id IS NULL THEN st.currency ELSE rv.currency END AS currency,
+                   CASE WHEN rv.id IS NULL THEN st.action_type ELSE rv.action_type END AS action_type,
+                   CASE WHEN rv.id IS NULL THEN st.rating_from_tier ELSE rv.rating_from_tier END AS rating_from_tier,
+                   CASE WHEN rv.id IS NULL THEN st.rating_to_tier ELSE rv.rating_to_tier END AS rating_to_tier,
+                   CASE WHEN rv.id IS NULL THEN st.updated_at ELSE rv.valid_from END AS updated_at
+            FROM stocks st
+            LEFT JOIN LATERAL (
+                SELECT r.* FROM stock_revisions r
+                WHERE r.stock_id = st.id AND r.valid_to > $%[1]d
+                ORDER BY r.valid_to
+                LIMIT 1
+            ) rv ON true
+            WHERE st.created_at <= $%[1]d
+        ) s`, asOfArg)
+}
+
+// GetTopMoversByTarget retrieves stocks with the highest relative target price changes. Both targets of an
+// event are stored in its currency, so the change compares like with like whatever the currency.
 func (r *stockRepository) GetTopMoversByTarget(ctx context.Context, limit int) ([]*entities.Stock, error) {
 	query := `
-        SELECT s.id, s.ticker, s.company, s.action, s.rating_from, s.rating_to,
-               s.target_from, s.target_to, s.event_time, s.price_close, s.created_at, s.updated_at,
-               b.id as broker_id, b.name as brokerage
+        SELECT ` + stockColumns + `
         FROM stocks s
         LEFT JOIN brokers b ON s.broker_id = b.id
         WHERE s.target_from > 0 AND s.target_to > 0
@@ -420,13 +1026,7 @@ func (r *stockRepository) GetTopMoversByTarget(ctx context.Context, limit int) (
 
 	var stocks []*entities.Stock
 	for rows.Next() {
-		stock := &entities.Stock{}
-		err := rows.Scan(
-			&stock.ID, &stock.Ticker, &stock.Company, &stock.Action,
-			&stock.RatingFrom, &stock.RatingTo, &stock.TargetFrom, &stock.TargetTo,
-			&stock.EventTime, &stock.PriceClose, &stock.CreatedAt, &stock.UpdatedAt,
-			&stock.BrokerID, &stock.Brokerage,
-		)
+		stock, err := scanStock(rows)
 		if err != nil {
 			r.logger.Error("Failed to scan stock row", "error", err)
 			continue
@@ -450,6 +1050,19 @@ func (r *stockRepository) GetUniqueTickersCount(ctx context.Context) (int, error
 	return count, nil
 }
 
+// GetLatestEventTime returns the newest event time stored from a source, or nil when it has stored no stocks yet.
+func (r *stockRepository) GetLatestEventTime(ctx context.Context, source string) (*time.Time, error) {
+	query := `SELECT MAX(event_time) FROM stocks WHERE source = $1`
+
+	var latest *time.Time
+	err := r.db.QueryRow(ctx, query, source).Scan(&latest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest event time: %w", err)
+	}
+
+	return latest, nil
+}
+
 // GetBrokerageStats returns statistics for each brokerage.
 func (r *stockRepository) GetBrokerageStats(ctx context.Context) ([]repositories.BrokerageStats, error) {
 	query := `
@@ -483,13 +1096,11 @@ func (r *stockRepository) GetBrokerageStats(ctx context.Context) ([]repositories
 // GetRecentRecommendations gets recent positive recommendations for the recommendation engine
 func (r *stockRepository) GetRecentRecommendations(ctx context.Context, since time.Time, limit int) ([]*entities.Stock, error) {
 	query := `
-		SELECT s.id, s.ticker, s.company, s.action, s.rating_from, s.rating_to,
-		       s.target_from, s.target_to, s.event_time, s.price_close, s.created_at, s.updated_at,
-		       b.id as broker_id, b.name as brokerage
+		SELECT ` + stockColumns + `
 		FROM stocks s
 		LEFT JOIN brokers b ON s.broker_id = b.id
 		WHERE s.event_time >= $1 
-		  AND (s.action ILIKE '%upgraded%' OR s.action ILIKE '%initiated%' OR s.action ILIKE '%reiterated%')
+		  AND s.action_type IN ('upgrade', 'initiate', 'reiterate')
 		ORDER BY s.event_time DESC, b.credibility_score DESC
 		LIMIT $2
 	`
@@ -502,13 +1113,7 @@ func (r *stockRepository) GetRecentRecommendations(ctx context.Context, since ti
 
 	var stocks []*entities.Stock
 	for rows.Next() {
-		stock := &entities.Stock{}
-		err := rows.Scan(
-			&stock.ID, &stock.Ticker, &stock.Company, &stock.Action,
-			&stock.RatingFrom, &stock.RatingTo, &stock.TargetFrom, &stock.TargetTo,
-			&stock.EventTime, &stock.PriceClose, &stock.CreatedAt, &stock.UpdatedAt,
-			&stock.BrokerID, &stock.Brokerage,
-		)
+		stock, err := scanStock(rows)
 		if err != nil {
 			r.logger.Error("Failed to scan stock row", "error", err)
 			continue
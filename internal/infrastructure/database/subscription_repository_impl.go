@@ -31,7 +31,13 @@ func (r *subscriptionRepository) Create(ctx context.Context, subscription *entit
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query,
 		subscription.ID, subscription.UserID, subscription.Plan,
 		subscription.Status, subscription.Price, subscription.Currency,
 		subscription.StartDate, subscription.EndDate, subscription.PaymentReference,
@@ -43,6 +49,14 @@ func (r *subscriptionRepository) Create(ctx context.Context, subscription *entit
 		return fmt.Errorf("failed to create subscription: %w", err)
 	}
 
+	if err := appendOutbox(ctx, tx, []*entities.DomainEvent{entities.SubscriptionCreatedEvent(subscription)}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -151,6 +165,48 @@ func (r *subscriptionRepository) Update(ctx context.Context, subscription *entit
 	return nil
 }
 
+// Activate stores an activated subscription together with the new tier of its user in one transaction,
+// recording SubscriptionActivated with them.
+func (r *subscriptionRepository) Activate(ctx context.Context, subscription *entities.Subscription, user *entities.User) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE subscriptions
+		SET status = $2, payment_reference = $3, updated_at = $4
+		WHERE id = $1
+	`, subscription.ID, subscription.Status, subscription.PaymentReference, subscription.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to activate subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("subscription not found")
+	}
+
+	result, err = tx.Exec(ctx, `
+		UPDATE users SET tier = $2, updated_at = $3 WHERE id = $1
+	`, user.ID, user.Tier, user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update user tier: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	if err := appendOutbox(ctx, tx, []*entities.DomainEvent{entities.SubscriptionActivatedEvent(subscription, user)}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 func (r *subscriptionRepository) GetExpiring(ctx context.Context, within time.Duration) ([]*entities.Subscription, error) {
 	query := `
 		SELECT id, user_id, plan, status, price, currency,
@@ -25,13 +25,24 @@ func NewUserRepository(db *pgxpool.Pool, logger logger.Logger) repositories.User
 
 func (r *userRepository) Create(ctx context.Context, user *entities.User) error {
 	query := `
-        INSERT INTO users (id, email, password_hash, first_name, last_name, tier, is_verified, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        INSERT INTO users (id, email, password_hash, first_name, last_name, tier, role, is_verified, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
     `
 
-	_, err := r.db.Exec(ctx, query,
+	role := user.Role
+	if role == "" {
+		role = entities.ROLE_USER
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query,
 		user.ID, user.Email, user.Password, user.FirstName, user.LastName,
-		user.Tier, user.IsVerified, user.CreatedAt, user.UpdatedAt,
+		user.Tier, role, user.IsVerified, user.CreatedAt, user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -39,19 +50,27 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User) error
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
+	if err := appendOutbox(ctx, tx, []*entities.DomainEvent{entities.UserRegisteredEvent(user)}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
 	query := `
-        SELECT id, email, password_hash, first_name, last_name, tier, is_verified, last_login, created_at, updated_at
+        SELECT id, email, password_hash, first_name, last_name, tier, role, is_verified, last_login, created_at, updated_at
         FROM users WHERE id = $1
     `
 
 	user := &entities.User{}
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.Password, &user.FirstName, &user.LastName,
-		&user.Tier, &user.IsVerified, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt,
+		&user.Tier, &user.Role, &user.IsVerified, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -63,14 +82,14 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.U
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
 	query := `
-        SELECT id, email, password_hash, first_name, last_name, tier, is_verified, last_login, created_at, updated_at
+        SELECT id, email, password_hash, first_name, last_name, tier, role, is_verified, last_login, created_at, updated_at
         FROM users WHERE email = $1
     `
 
 	user := &entities.User{}
 	err := r.db.QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.Email, &user.Password, &user.FirstName, &user.LastName,
-		&user.Tier, &user.IsVerified, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt,
+		&user.Tier, &user.Role, &user.IsVerified, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -163,7 +182,7 @@ func (r *userRepository) GetUserCount(ctx context.Context) (int, error) {
 
 func (r *userRepository) GetUsersByTier(ctx context.Context, tier entities.UserTier) ([]*entities.User, error) {
 	query := `
-        SELECT id, email, password_hash, first_name, last_name, tier, is_verified, last_login, created_at, updated_at
+        SELECT id, email, password_hash, first_name, last_name, tier, role, is_verified, last_login, created_at, updated_at
         FROM users WHERE tier = $1
         ORDER BY created_at DESC
     `
@@ -179,7 +198,7 @@ func (r *userRepository) GetUsersByTier(ctx context.Context, tier entities.UserT
 		user := &entities.User{}
 		err := rows.Scan(
 			&user.ID, &user.Email, &user.Password, &user.FirstName, &user.LastName,
-			&user.Tier, &user.IsVerified, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt,
+			&user.Tier, &user.Role, &user.IsVerified, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			r.logger.Error("Failed to scan user row", "error", err)
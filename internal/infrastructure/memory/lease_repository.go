@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+)
+
+// leaseRepository keeps leases in process memory. It only excludes jobs within one process, which is
+// enough for a single replica and for tests; replicas sharing a database use the database implementation.
+type leaseRepository struct {
+	mu     sync.Mutex
+	leases map[string]*entities.Lease
+	now    func() time.Time
+}
+
+// NewLeaseRepository creates an in-memory repositories.LeaseRepository
+func NewLeaseRepository() repositories.LeaseRepository {
+	return NewLeaseRepositoryWithClock(time.Now)
+}
+
+// NewLeaseRepositoryWithClock creates an in-memory repositories.LeaseRepository judging expiry by now,
+// so tests can expire leases without waiting
+func NewLeaseRepositoryWithClock(now func() time.Time) repositories.LeaseRepository {
+	return &leaseRepository{
+		leases: make(map[string]*entities.Lease),
+		now:    now,
+	}
+}
+
+func (r *leaseRepository) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (*entities.Lease, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	current, exists := r.leases[name]
+	if exists && current.ExpiresAt.After(now) {
+		return nil, repositories.ErrLeaseHeld
+	}
+
+	var token int64 = 1
+	if exists {
+		token = current.Token + 1
+	}
+
+	lease := &entities.Lease{
+		Name:       name,
+		Holder:     holder,
+		Token:      token,
+		ExpiresAt:  now.Add(ttl),
+		AcquiredAt: now,
+		RenewedAt:  now,
+	}
+	r.leases[name] = lease
+
+	granted := *lease
+	return &granted, nil
+}
+
+func (r *leaseRepository) Renew(ctx context.Context, lease *entities.Lease, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	current, held := r.heldLocked(lease, now)
+	if !held {
+		return repositories.ErrLeaseLost
+	}
+
+	current.ExpiresAt = now.Add(ttl)
+	current.RenewedAt = now
+	lease.ExpiresAt = current.ExpiresAt
+	lease.RenewedAt = current.RenewedAt
+	return nil
+}
+
+func (r *leaseRepository) Release(ctx context.Context, lease *entities.Lease) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if current, held := r.heldLocked(lease, now); held {
+		current.ExpiresAt = now
+	}
+	return nil
+}
+
+// heldLocked returns the stored lease if lease is still its unexpired grant
+func (r *leaseRepository) heldLocked(lease *entities.Lease, now time.Time) (*entities.Lease, bool) {
+	current, exists := r.leases[lease.Name]
+	if !exists || current.Holder != lease.Holder || current.Token != lease.Token || !current.ExpiresAt.After(now) {
+		return nil, false
+	}
+	return current, true
+}
@@ -86,6 +86,33 @@ func (m *AuthMiddleware) RequirePremium(next http.Handler) http.Handler {
 	})
 }
 
+// RequireAdmin middleware - requires an authenticated user with the admin role
+func (m *AuthMiddleware) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := m.extractAndValidateToken(r)
+		if err != nil {
+			m.logger.Warn("Authentication failed", "error", err, "path", r.URL.Path)
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, map[string]string{"error": "Authentication required"})
+			return
+		}
+
+		if claims.Role != entities.ROLE_ADMIN {
+			m.logger.Info("Non-admin user attempted to access admin endpoint",
+				"user_id", claims.UserID,
+				"path", r.URL.Path)
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, map[string]string{"error": "Admin access required"})
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), UserIDContextKey, claims.UserID)
+		ctx = context.WithValue(ctx, UserTierContextKey, claims.Tier)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // OptionalAuth middleware - adds user info if token is present
 func (m *AuthMiddleware) OptionalAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
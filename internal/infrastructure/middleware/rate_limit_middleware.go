@@ -95,6 +95,13 @@ func (rl *RateLimiter) getLimiter(identifier string, tier entities.UserTier) *ra
 	return limiter
 }
 
+// Forget drops the limiter of identifier, so its next request gets a limiter for the tier it has then
+func (rl *RateLimiter) Forget(identifier string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	delete(rl.visitors, identifier)
+}
+
 func (rl *RateLimiter) cleanupVisitors() {
 	for {
 		time.Sleep(time.Hour)
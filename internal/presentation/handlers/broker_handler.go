@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type BrokerHandler struct {
+	brokerUC usecases.BrokerUseCase
+	logger   logger.Logger
+}
+
+func NewBrokerHandler(brokerUC usecases.BrokerUseCase, logger logger.Logger) *BrokerHandler {
+	return &BrokerHandler{
+		brokerUC: brokerUC,
+		logger:   logger,
+	}
+}
+
+// BrokerAliasRequest is the body of an alias to create
+type BrokerAliasRequest struct {
+	BrokerID uuid.UUID `json:"broker_id"`
+	Alias    string    `json:"alias"`
+}
+
+// BrokerMergeRequest is the body of a merge of merged_id into survivor_id
+type BrokerMergeRequest struct {
+	SurvivorID uuid.UUID `json:"survivor_id"`
+	MergedID   uuid.UUID `json:"merged_id"`
+	Reason     string    `json:"reason"`
+}
+
+// SuggestDuplicates returns the pairs of brokers likely to be the same broker; min_similarity tunes how alike
+// their names must be, from 0 to 1
+func (h *BrokerHandler) SuggestDuplicates(w http.ResponseWriter, r *http.Request) {
+	minSimilarity := 0.0
+	if value := r.URL.Query().Get("min_similarity"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed > 1 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, map[string]string{"error": "min_similarity must be between 0 and 1"})
+			return
+		}
+		minSimilarity = parsed
+	}
+
+	duplicates, err := h.brokerUC.SuggestDuplicates(r.Context(), minSimilarity)
+	if err != nil {
+		h.logger.Error("Failed to suggest duplicate brokers", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to suggest duplicate brokers"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: duplicates})
+}
+
+// ListAliases returns every broker alias
+func (h *BrokerHandler) ListAliases(w http.ResponseWriter, r *http.Request) {
+	aliases, err := h.brokerUC.ListAliases(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list broker aliases", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to retrieve broker aliases"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: aliases})
+}
+
+// CreateAlias attributes the events published under an alias to a broker
+func (h *BrokerHandler) CreateAlias(w http.ResponseWriter, r *http.Request) {
+	var req BrokerAliasRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	alias, err := h.brokerUC.CreateAlias(r.Context(), req.BrokerID, req.Alias)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, StockResponse{Data: alias, Message: "Broker alias created"})
+}
+
+// DeleteAlias removes an alias
+func (h *BrokerHandler) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid broker alias ID"})
+		return
+	}
+
+	if err := h.brokerUC.DeleteAlias(r.Context(), id); err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Message: "Broker alias deleted"})
+}
+
+// MergeBrokers folds one broker into another and returns the audit record of the merge
+func (h *BrokerHandler) MergeBrokers(w http.ResponseWriter, r *http.Request) {
+	var req BrokerMergeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	merge, err := h.brokerUC.MergeBrokers(r.Context(), req.SurvivorID, req.MergedID, req.Reason)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, StockResponse{Data: merge, Message: "Brokers merged"})
+}
+
+// ListMerges returns the most recent broker merges
+func (h *BrokerHandler) ListMerges(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	merges, err := h.brokerUC.ListMerges(r.Context(), limit)
+	if err != nil {
+		h.logger.Error("Failed to list broker merges", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to retrieve broker merges"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: merges})
+}
+
+func (h *BrokerHandler) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		h.logger.Error("Failed to decode broker request", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		return false
+	}
+	defer r.Body.Close()
+
+	return true
+}
+
+func (h *BrokerHandler) respondWithError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Broker or alias not found"})
+	case errors.Is(err, repositories.ErrDuplicate):
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "This alias is already assigned to a broker"})
+	case errors.Is(err, usecases.ErrBrokerAliasInvalid), errors.Is(err, usecases.ErrBrokerMergeInvalid):
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to process broker request", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to process broker request"})
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type DeadLetterHandler struct {
+	deadLetterUC usecases.DeadLetterUseCase
+	logger       logger.Logger
+}
+
+func NewDeadLetterHandler(deadLetterUC usecases.DeadLetterUseCase, logger logger.Logger) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		deadLetterUC: deadLetterUC,
+		logger:       logger,
+	}
+}
+
+// ListDeadLetters returns quarantined records, newest first, optionally filtered by status
+func (h *DeadLetterHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	status := entities.DeadLetterStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", entities.DeadLetterStatusPending, entities.DeadLetterStatusResubmitted, entities.DeadLetterStatusDiscarded:
+	default:
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid status"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	records, pagination, err := h.deadLetterUC.ListDeadLetters(r.Context(), status, limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to list dead letter records", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to retrieve dead letter records"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{
+		Data:       records,
+		Pagination: pagination,
+	})
+}
+
+// GetDeadLetter returns a single quarantined record
+func (h *DeadLetterHandler) GetDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	record, err := h.deadLetterUC.GetDeadLetter(r.Context(), id)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: record})
+}
+
+// FixDeadLetter replaces the raw payload of a pending record with the corrected item in the request body
+func (h *DeadLetterHandler) FixDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var payload json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.logger.Error("Failed to decode dead letter payload", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		return
+	}
+	defer r.Body.Close()
+
+	record, err := h.deadLetterUC.FixDeadLetter(r.Context(), id, payload)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: record, Message: "Dead letter record updated"})
+}
+
+// ResubmitDeadLetter runs a pending record through ingestion again
+func (h *DeadLetterHandler) ResubmitDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	record, err := h.deadLetterUC.ResubmitDeadLetter(r.Context(), id)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: record, Message: "Dead letter record re-submitted"})
+}
+
+// DiscardDeadLetter resolves a pending record without ingesting it
+func (h *DeadLetterHandler) DiscardDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	record, err := h.deadLetterUC.DiscardDeadLetter(r.Context(), id)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: record, Message: "Dead letter record discarded"})
+}
+
+func (h *DeadLetterHandler) parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid dead letter ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *DeadLetterHandler) respondWithError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Dead letter record not found"})
+	case errors.Is(err, usecases.ErrDeadLetterResolved):
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecases.ErrDeadLetterInvalid):
+		render.Status(r, http.StatusUnprocessableEntity)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to process dead letter record", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to process dead letter record"})
+	}
+}
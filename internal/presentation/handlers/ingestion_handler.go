@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type IngestionHandler struct {
+	ingestionUC usecases.IngestionRunUseCase
+	logger      logger.Logger
+}
+
+func NewIngestionHandler(ingestionUC usecases.IngestionRunUseCase, logger logger.Logger) *IngestionHandler {
+	return &IngestionHandler{
+		ingestionUC: ingestionUC,
+		logger:      logger,
+	}
+}
+
+// ListRuns returns the ingestion run history, newest first
+func (h *IngestionHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	runs, pagination, err := h.ingestionUC.ListRuns(r.Context(), limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to list ingestion runs", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to retrieve ingestion runs"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{
+		Data:       runs,
+		Pagination: pagination,
+	})
+}
+
+// GetRun returns a single ingestion run by its batch ID
+func (h *IngestionHandler) GetRun(w http.ResponseWriter, r *http.Request) {
+	batchID := chi.URLParam(r, "batchID")
+	if batchID == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Batch ID is required"})
+		return
+	}
+
+	run, err := h.ingestionUC.GetRun(r.Context(), batchID)
+	if err != nil {
+		h.respondWithLookupError(w, r, err, "Ingestion run not found")
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: run})
+}
+
+// GetLastSuccessfulRun returns the most recent run that completed successfully
+func (h *IngestionHandler) GetLastSuccessfulRun(w http.ResponseWriter, r *http.Request) {
+	run, err := h.ingestionUC.GetLastSuccessfulRun(r.Context())
+	if err != nil {
+		h.respondWithLookupError(w, r, err, "No successful ingestion run found")
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: run})
+}
+
+// ListQualitySummaries returns the data-quality summaries of runs, newest first
+func (h *IngestionHandler) ListQualitySummaries(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	summaries, pagination, err := h.ingestionUC.ListQualitySummaries(r.Context(), limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to list quality summaries", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to retrieve quality summaries"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{
+		Data:       summaries,
+		Pagination: pagination,
+	})
+}
+
+// GetQualitySummary returns the data-quality summary of a run by its batch ID
+func (h *IngestionHandler) GetQualitySummary(w http.ResponseWriter, r *http.Request) {
+	batchID := chi.URLParam(r, "batchID")
+	if batchID == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Batch ID is required"})
+		return
+	}
+
+	summary, err := h.ingestionUC.GetQualitySummary(r.Context(), batchID)
+	if err != nil {
+		h.respondWithLookupError(w, r, err, "Quality summary not found")
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: summary})
+}
+
+func (h *IngestionHandler) respondWithLookupError(w http.ResponseWriter, r *http.Request, err error, notFoundMessage string) {
+	if errors.Is(err, repositories.ErrNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": notFoundMessage})
+		return
+	}
+
+	h.logger.Error("Failed to get ingestion run", "error", err)
+	render.Status(r, http.StatusInternalServerError)
+	render.JSON(w, r, map[string]string{"error": "Failed to retrieve ingestion run"})
+}
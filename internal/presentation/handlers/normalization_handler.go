@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type NormalizationHandler struct {
+	normalizationUC usecases.NormalizationUseCase
+	logger          logger.Logger
+}
+
+func NewNormalizationHandler(normalizationUC usecases.NormalizationUseCase, logger logger.Logger) *NormalizationHandler {
+	return &NormalizationHandler{
+		normalizationUC: normalizationUC,
+		logger:          logger,
+	}
+}
+
+// NormalizationRuleRequest is the body of a rule to create or update; the kind cannot be changed
+type NormalizationRuleRequest struct {
+	Kind      entities.NormalizationKind `json:"kind"`
+	Brokerage string                     `json:"brokerage"`
+	Pattern   string                     `json:"pattern"`
+	Value     string                     `json:"value"`
+}
+
+// GetVocabulary returns the canonical rating tiers and action types rules can map onto
+func (h *NormalizationHandler) GetVocabulary(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, StockResponse{Data: map[string]interface{}{
+		"rating_tiers": entities.RatingTiers,
+		"action_types": entities.ActionTypes,
+	}})
+}
+
+// ListRules returns the stored rules, optionally filtered by kind
+func (h *NormalizationHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	kind := entities.NormalizationKind(r.URL.Query().Get("kind"))
+	switch kind {
+	case "", entities.NormalizationKindRating, entities.NormalizationKindAction:
+	default:
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid kind"})
+		return
+	}
+
+	rules, err := h.normalizationUC.ListRules(r.Context(), kind)
+	if err != nil {
+		h.logger.Error("Failed to list normalization rules", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to retrieve normalization rules"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: rules})
+}
+
+// CreateRule stores a new rule
+func (h *NormalizationHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := h.normalizationUC.CreateRule(r.Context(), req.Kind, req.Brokerage, req.Pattern, req.Value)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, StockResponse{Data: rule, Message: "Normalization rule created"})
+}
+
+// UpdateRule changes the brokerage, pattern and value of a rule
+func (h *NormalizationHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := h.decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := h.normalizationUC.UpdateRule(r.Context(), id, req.Brokerage, req.Pattern, req.Value)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: rule, Message: "Normalization rule updated"})
+}
+
+// DeleteRule removes a rule
+func (h *NormalizationHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.normalizationUC.DeleteRule(r.Context(), id); err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Message: "Normalization rule deleted"})
+}
+
+// ListLabels returns the raw labels of the stored stocks with their normalized values; unmapped=true
+// restricts them to those no rule maps yet
+func (h *NormalizationHandler) ListLabels(w http.ResponseWriter, r *http.Request) {
+	unmappedOnly := r.URL.Query().Get("unmapped") == "true"
+
+	labels, err := h.normalizationUC.ListLabels(r.Context(), unmappedOnly)
+	if err != nil {
+		h.logger.Error("Failed to list stock labels", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to retrieve stock labels"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: labels})
+}
+
+// Reapply normalizes the stored stocks again with the current rules
+func (h *NormalizationHandler) Reapply(w http.ResponseWriter, r *http.Request) {
+	updated, err := h.normalizationUC.Reapply(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to reapply normalization rules", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to reapply normalization rules"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: map[string]int{"updated": updated}, Message: "Normalization rules reapplied"})
+}
+
+func (h *NormalizationHandler) decodeRule(w http.ResponseWriter, r *http.Request) (*NormalizationRuleRequest, bool) {
+	var req NormalizationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode normalization rule", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		return nil, false
+	}
+	defer r.Body.Close()
+
+	return &req, true
+}
+
+func (h *NormalizationHandler) parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid normalization rule ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *NormalizationHandler) respondWithError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Normalization rule not found"})
+	case errors.Is(err, repositories.ErrDuplicate):
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "A rule for this pattern and brokerage already exists"})
+	case errors.Is(err, usecases.ErrNormalizationRuleInvalid):
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to process normalization rule", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to process normalization rule"})
+	}
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/internal/infrastructure/middleware"
+	"stock-tracker/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+// StockEditHandler serves the admin writes to stock events. Each write is attributed to the admin in the
+// request context.
+type StockEditHandler struct {
+	stockEditUC usecases.StockEditUseCase
+	logger      logger.Logger
+}
+
+func NewStockEditHandler(stockEditUC usecases.StockEditUseCase, logger logger.Logger) *StockEditHandler {
+	return &StockEditHandler{
+		stockEditUC: stockEditUC,
+		logger:      logger,
+	}
+}
+
+// CreateStock stores a new stock event
+func (h *StockEditHandler) CreateStock(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(w, r)
+	if !ok {
+		return
+	}
+	var input usecases.StockInput
+	if !h.decode(w, r, &input) {
+		return
+	}
+
+	stock, err := h.stockEditUC.CreateStock(r.Context(), actor, input)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, StockResponse{Data: stock, Message: "Stock event created"})
+}
+
+// UpdateStock replaces the fields of a stock event
+func (h *StockEditHandler) UpdateStock(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+	var input usecases.StockInput
+	if !h.decode(w, r, &input) {
+		return
+	}
+
+	stock, err := h.stockEditUC.UpdateStock(r.Context(), actor, id, input)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: stock, Message: "Stock event updated"})
+}
+
+// DeleteStock removes a stock event
+func (h *StockEditHandler) DeleteStock(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.actor(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.stockEditUC.DeleteStock(r.Context(), actor, id); err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Message: "Stock event deleted"})
+}
+
+// ListStockChanges returns who changed a stock event and how, newest first
+func (h *StockEditHandler) ListStockChanges(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	changes, err := h.stockEditUC.ListStockChanges(r.Context(), id)
+	if err != nil {
+		h.respondWithError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: changes})
+}
+
+func (h *StockEditHandler) actor(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	actor, ok := r.Context().Value(middleware.UserIDContextKey).(uuid.UUID)
+	if !ok {
+		render.Status(r, http.StatusUnauthorized)
+		render.JSON(w, r, map[string]string{"error": "Authentication required"})
+		return uuid.Nil, false
+	}
+	return actor, true
+}
+
+func (h *StockEditHandler) parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid stock ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *StockEditHandler) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		h.logger.Error("Failed to decode stock request", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		return false
+	}
+	defer r.Body.Close()
+
+	return true
+}
+
+func (h *StockEditHandler) respondWithError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Stock not found"})
+	case errors.Is(err, repositories.ErrDuplicate):
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "A stock event with this ticker and event time already exists"})
+	case errors.Is(err, usecases.ErrStockInvalid):
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to process stock request", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to process stock request"})
+	}
+}
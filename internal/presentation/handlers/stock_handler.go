@@ -1,15 +1,24 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
 	"stock-tracker/internal/domain/usecases"
 	"stock-tracker/internal/domain/valueObjects"
 	"stock-tracker/pkg/logger"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/google/uuid"
 )
 
 type StockHandler struct {
@@ -31,9 +40,27 @@ func NewStockHandler(stockUC usecases.StockUseCase, logger logger.Logger) *Stock
 }
 
 func (h *StockHandler) GetStocks(w http.ResponseWriter, r *http.Request) {
-	filters := h.parseFilters(r)
+	filters, err := h.parseFilters(r)
+	if err == nil {
+		err = filters.Validate()
+	}
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
 
 	stocks, pagination, err := h.stockUC.GetStocks(r.Context(), filters)
+	if errors.Is(err, usecases.ErrUnsupportedCurrency) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrInvalidCursor) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid pagination cursor"})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to get stocks", "error", err)
 		render.Status(r, http.StatusInternalServerError)
@@ -41,6 +68,10 @@ func (h *StockHandler) GetStocks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if links := paginationLinks(r, pagination); links != "" {
+		w.Header().Set("Link", links)
+	}
+
 	response := StockResponse{
 		Data:       stocks,
 		Pagination: pagination,
@@ -57,7 +88,19 @@ func (h *StockHandler) GetStockByTicker(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	stocks, err := h.stockUC.GetStocksByTicker(r.Context(), ticker)
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
+
+	stocks, err := h.stockUC.GetStocksByTicker(r.Context(), ticker, asOf, r.URL.Query().Get("currency"))
+	if errors.Is(err, usecases.ErrUnsupportedCurrency) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to get stocks by ticker", "ticker", ticker, "error", err)
 		render.Status(r, http.StatusInternalServerError)
@@ -88,56 +131,214 @@ func (h *StockHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, response)
 }
 
-func (h *StockHandler) parseFilters(r *http.Request) valueObjects.StockFilters {
+// parseFilters reads the stock filters from the query string, reporting the first malformed value
+func (h *StockHandler) parseFilters(r *http.Request) (valueObjects.StockFilters, error) {
+	query := r.URL.Query()
 	filters := valueObjects.StockFilters{
-		Ticker:    r.URL.Query().Get("ticker"),
-		Company:   r.URL.Query().Get("company"),
-		Brokerage: r.URL.Query().Get("brokerage"),
-		Action:    r.URL.Query().Get("action"),
-		SortBy:    r.URL.Query().Get("sort_by"),
-		SortOrder: r.URL.Query().Get("sort_order"),
+		Ticker:          query.Get("ticker"),
+		TickerMatch:     query.Get("ticker_match"),
+		Tickers:         parseList(query["tickers"]),
+		Company:         query.Get("company"),
+		Brokerage:       query.Get("brokerage"),
+		Brokerages:      parseList(query["brokerages"]),
+		Action:          query.Get("action"),
+		ActionType:      entities.ActionType(query.Get("action_type")),
+		RatingFrom:      query.Get("rating_from"),
+		RatingTo:        query.Get("rating_to"),
+		RatingDirection: query.Get("rating_direction"),
+		Source:          query.Get("source"),
+		Currency:        query.Get("currency"),
+		SortBy:          query.Get("sort_by"),
+		SortOrder:       query.Get("sort_order"),
+		Cursor:          query.Get("cursor"),
 	}
 
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			filters.Limit = limit
+	var err error
+	if spec := query.Get("sort"); spec != "" {
+		if filters.Sort, err = valueObjects.ParseSort(spec); err != nil {
+			return filters, err
 		}
 	}
-
-	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil {
-			filters.Offset = offset
+	if filters.Limit, err = parseInt(query, "limit"); err != nil {
+		return filters, err
+	}
+	if filters.Offset, err = parseInt(query, "offset"); err != nil {
+		return filters, err
+	}
+	if value := query.Get("include_total"); value != "" {
+		if filters.IncludeTotal, err = strconv.ParseBool(value); err != nil {
+			return filters, errors.New("include_total must be true or false")
 		}
 	}
+	if filters.MinTargetChange, err = parseFloat(query, "min_target_change"); err != nil {
+		return filters, err
+	}
+	if filters.MaxTargetChange, err = parseFloat(query, "max_target_change"); err != nil {
+		return filters, err
+	}
+	if filters.DateFrom, err = parseTime(query, "date_from", false); err != nil {
+		return filters, err
+	}
+	if filters.DateTo, err = parseTime(query, "date_to", true); err != nil {
+		return filters, err
+	}
+	if filters.AsOf, err = parseAsOf(r); err != nil {
+		return filters, err
+	}
 
+	// Defaults only fill in what the request left out, so a limit it gave is validated as given
+	limit := filters.Limit
 	filters.SetDefaults()
-	return filters
+	if query.Get("limit") != "" {
+		filters.Limit = limit
+	}
+	return filters, nil
 }
 
-// GetStockByID retrieves a stock by its ID
-func (h *StockHandler) GetStockByID(w http.ResponseWriter, r *http.Request) {
-	// Placeholder implementation
-	render.Status(r, http.StatusNotImplemented)
-	render.JSON(w, r, map[string]string{"error": "Not implemented"})
+// paginationLinks builds an RFC 8288 Link header pointing at the next and previous pages by cursor.
+// The links repeat the request with its cursor replaced and any offset dropped.
+func paginationLinks(r *http.Request, pagination *valueObjects.Pagination) string {
+	if pagination == nil {
+		return ""
+	}
+
+	var links []string
+	link := func(cursor, rel string) {
+		if cursor == "" {
+			return
+		}
+		query := r.URL.Query()
+		query.Del("offset")
+		query.Set("cursor", cursor)
+		target := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel))
+	}
+	link(pagination.NextCursor, "next")
+	link(pagination.PrevCursor, "prev")
+
+	return strings.Join(links, ", ")
+}
+
+// parseAsOf reads the optional as_of query parameter, an RFC 3339 timestamp or a date
+func parseAsOf(r *http.Request) (*time.Time, error) {
+	return parseTime(r.URL.Query(), "as_of", true)
+}
+
+// parseTime reads an optional query parameter holding an RFC 3339 timestamp or a date. A bare date
+// stands for its first instant, or for its last one when endOfDay is set, so it covers the whole day.
+func parseTime(query url.Values, name string, endOfDay bool) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		if endOfDay {
+			parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+		}
+		return &parsed, nil
+	}
+
+	return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
 }
 
-// CreateStock creates a new stock
-func (h *StockHandler) CreateStock(w http.ResponseWriter, r *http.Request) {
-	// Placeholder implementation
-	render.Status(r, http.StatusNotImplemented)
-	render.JSON(w, r, map[string]string{"error": "Not implemented"})
+// parseInt reads an optional integer query parameter, zero when absent
+func parseInt(query url.Values, name string) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+	return parsed, nil
+}
+
+// parseFloat reads an optional number query parameter
+func parseFloat(query url.Values, name string) (*float64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return nil, fmt.Errorf("%s must be a number", name)
+	}
+	return &parsed, nil
 }
 
-// UpdateStock updates an existing stock
-func (h *StockHandler) UpdateStock(w http.ResponseWriter, r *http.Request) {
-	// Placeholder implementation
-	render.Status(r, http.StatusNotImplemented)
-	render.JSON(w, r, map[string]string{"error": "Not implemented"})
+// parseList reads a list query parameter, given either repeated or comma separated
+func parseList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// GetStockByID returns a single stock event, with its targets in the requested display currency
+func (h *StockHandler) GetStockByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid stock ID"})
+		return
+	}
+
+	stock, err := h.stockUC.GetStockByID(r.Context(), id, r.URL.Query().Get("currency"))
+	switch {
+	case errors.Is(err, usecases.ErrUnsupportedCurrency):
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, repositories.ErrNotFound):
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Stock not found"})
+		return
+	case err != nil:
+		h.logger.Error("Failed to get stock by ID", "id", id, "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to retrieve stock"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: stock})
 }
 
-// DeleteStock deletes a stock
-func (h *StockHandler) DeleteStock(w http.ResponseWriter, r *http.Request) {
-	// Placeholder implementation
-	render.Status(r, http.StatusNotImplemented)
-	render.JSON(w, r, map[string]string{"error": "Not implemented"})
+// GetTickerConsensus returns the consensus of the brokers covering a ticker, with its targets in the
+// requested display currency
+func (h *StockHandler) GetTickerConsensus(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Ticker is required"})
+		return
+	}
+
+	consensus, err := h.stockUC.GetTickerConsensus(r.Context(), ticker, r.URL.Query().Get("currency"))
+	switch {
+	case errors.Is(err, usecases.ErrUnsupportedCurrency):
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, repositories.ErrNotFound):
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "No stocks found for ticker"})
+		return
+	case err != nil:
+		h.logger.Error("Failed to get ticker consensus", "ticker", ticker, "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to compute consensus"})
+		return
+	}
+
+	render.JSON(w, r, StockResponse{Data: consensus})
 }
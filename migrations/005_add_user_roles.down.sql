@@ -0,0 +1,2 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS valid_user_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,4 @@
+-- Adds a role column so operators can be granted access to admin endpoints
+-- Promote an account with: UPDATE users SET role = 'admin' WHERE email = '...';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role STRING NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD CONSTRAINT valid_user_role CHECK (role IN ('user', 'admin'));
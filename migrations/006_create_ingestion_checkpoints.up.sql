@@ -0,0 +1,14 @@
+-- Tracks the upstream next_page cursor of each ingestion run so an interrupted run can resume
+CREATE TABLE IF NOT EXISTS ingestion_checkpoints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    batch_id STRING NOT NULL, -- run that last advanced the checkpoint
+    next_page STRING NOT NULL DEFAULT '',
+    pages_fetched INT NOT NULL DEFAULT 0,
+    records_fetched INT NOT NULL DEFAULT 0,
+    watermark TIMESTAMPTZ, -- newest stored event_time when the checkpoint was opened
+    status STRING NOT NULL, -- 'in_progress', 'completed'
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    INDEX idx_checkpoints_created_at (created_at DESC)
+);
@@ -0,0 +1,15 @@
+-- Upstream records that failed conversion or validation, kept for review instead of being dropped
+CREATE TABLE IF NOT EXISTS dead_letter_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    batch_id STRING NOT NULL,
+    payload JSONB NOT NULL, -- raw upstream item, as received or as last fixed
+    reason STRING NOT NULL,
+    status STRING NOT NULL, -- 'pending', 'resubmitted', 'discarded'
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+    resolved_at TIMESTAMPTZ,
+
+    INDEX idx_dead_letters_status (status, created_at DESC),
+    INDEX idx_dead_letters_batch_id (batch_id)
+);
@@ -0,0 +1,17 @@
+-- Prior values of stock events that were corrected upstream after being ingested.
+-- A revision held the stock's values from valid_from until valid_to, when it was superseded.
+CREATE TABLE IF NOT EXISTS stock_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    stock_id UUID NOT NULL REFERENCES stocks(id) ON DELETE CASCADE,
+    company STRING NOT NULL,
+    broker_id UUID,
+    action STRING NOT NULL,
+    rating_from STRING,
+    rating_to STRING,
+    target_from DECIMAL(10,2),
+    target_to DECIMAL(10,2),
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ NOT NULL,
+
+    INDEX idx_stock_revisions_stock_valid_to (stock_id, valid_to)
+);
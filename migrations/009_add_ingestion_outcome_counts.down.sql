@@ -0,0 +1,3 @@
+ALTER TABLE ingestion_logs DROP COLUMN IF EXISTS duplicate_records;
+ALTER TABLE ingestion_logs DROP COLUMN IF EXISTS updated_records;
+ALTER TABLE ingestion_logs DROP COLUMN IF EXISTS inserted_records;
@@ -0,0 +1,4 @@
+-- Breaks the successful records of a run down by how they were applied to the stocks table
+ALTER TABLE ingestion_logs ADD COLUMN IF NOT EXISTS inserted_records INT NOT NULL DEFAULT 0;
+ALTER TABLE ingestion_logs ADD COLUMN IF NOT EXISTS updated_records INT NOT NULL DEFAULT 0;
+ALTER TABLE ingestion_logs ADD COLUMN IF NOT EXISTS duplicate_records INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS ingestion_checkpoints@idx_checkpoints_source_created_at;
+ALTER TABLE ingestion_checkpoints DROP COLUMN IF EXISTS source;
@@ -0,0 +1,4 @@
+-- Keeps checkpoints apart per ingestion source, so a file import never resumes or
+-- watermarks the checkpoint of the API feed
+ALTER TABLE ingestion_checkpoints ADD COLUMN IF NOT EXISTS source STRING NOT NULL DEFAULT 'api';
+CREATE INDEX IF NOT EXISTS idx_checkpoints_source_created_at ON ingestion_checkpoints (source, created_at DESC);
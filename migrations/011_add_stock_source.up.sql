@@ -0,0 +1,4 @@
+-- Records which upstream source each stock event was ingested from.
+-- Rows that predate multi-vendor ingestion all came from the original API vendor.
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS source STRING NOT NULL DEFAULT 'api';
+CREATE INDEX IF NOT EXISTS idx_stocks_source ON stocks (source);
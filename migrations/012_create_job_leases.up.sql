@@ -0,0 +1,9 @@
+-- Time-bound leases so only one ingestor replica runs a given job at a time
+CREATE TABLE IF NOT EXISTS job_leases (
+    name STRING PRIMARY KEY,
+    holder STRING NOT NULL,
+    token INT8 NOT NULL, -- fencing token, incremented every time the lease changes hands
+    expires_at TIMESTAMPTZ NOT NULL,
+    acquired_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    renewed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
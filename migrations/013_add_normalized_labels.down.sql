@@ -0,0 +1,13 @@
+ALTER TABLE stock_revisions
+    DROP COLUMN IF EXISTS action_type,
+    DROP COLUMN IF EXISTS rating_from_tier,
+    DROP COLUMN IF EXISTS rating_to_tier;
+
+DROP INDEX IF EXISTS stocks@idx_stocks_rating_to_tier;
+DROP INDEX IF EXISTS stocks@idx_stocks_action_type;
+ALTER TABLE stocks
+    DROP COLUMN IF EXISTS action_type,
+    DROP COLUMN IF EXISTS rating_from_tier,
+    DROP COLUMN IF EXISTS rating_to_tier;
+
+DROP TABLE IF EXISTS normalization_rules;
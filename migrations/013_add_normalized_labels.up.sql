@@ -0,0 +1,27 @@
+-- Rules mapping the raw ratings and actions brokers publish onto canonical values.
+-- They extend or override the vocabulary built into the application; an empty brokerage applies to every broker.
+CREATE TABLE IF NOT EXISTS normalization_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind STRING NOT NULL CHECK (kind IN ('rating', 'action')),
+    brokerage STRING NOT NULL DEFAULT '',
+    pattern STRING NOT NULL,
+    value STRING NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE INDEX idx_normalization_rules_kind_brokerage_pattern (kind, brokerage, pattern)
+);
+
+-- Canonical action type and rating tiers of each event, empty until a rule maps the raw value.
+-- Rows that predate normalization are filled in by POST /api/v1/admin/normalization/reapply.
+ALTER TABLE stocks
+    ADD COLUMN IF NOT EXISTS action_type STRING NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS rating_from_tier STRING NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS rating_to_tier STRING NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_stocks_action_type ON stocks (action_type, event_time DESC);
+CREATE INDEX IF NOT EXISTS idx_stocks_rating_to_tier ON stocks (rating_to_tier, event_time DESC);
+
+ALTER TABLE stock_revisions
+    ADD COLUMN IF NOT EXISTS action_type STRING NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS rating_from_tier STRING NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS rating_to_tier STRING NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS broker_merges;
+DROP TABLE IF EXISTS broker_aliases;
@@ -0,0 +1,29 @@
+-- Other names brokers publish under. alias_key is the alias as ingestion matches it
+-- (lower case, without punctuation and corporate suffixes), so each name resolves to one broker.
+CREATE TABLE IF NOT EXISTS broker_aliases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    broker_id UUID NOT NULL REFERENCES brokers(id) ON DELETE CASCADE,
+    alias STRING NOT NULL,
+    alias_key STRING NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    UNIQUE INDEX idx_broker_aliases_alias_key (alias_key),
+    INDEX idx_broker_aliases_broker_id (broker_id)
+);
+
+-- Audit trail of brokers folded into another one. The merged broker row is deleted,
+-- so its identity is copied here rather than referenced.
+CREATE TABLE IF NOT EXISTS broker_merges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    survivor_id UUID NOT NULL,
+    survivor_name STRING NOT NULL,
+    merged_id UUID NOT NULL,
+    merged_name STRING NOT NULL,
+    merged_credibility_score DECIMAL(3,2),
+    stocks_moved INT NOT NULL DEFAULT 0,
+    aliases_moved INT NOT NULL DEFAULT 0,
+    reason STRING NOT NULL DEFAULT '',
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    INDEX idx_broker_merges_merged_at (merged_at DESC)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS stocks@idx_stocks_unpriced;
+ALTER TABLE stocks DROP COLUMN IF EXISTS price_close_date;
@@ -0,0 +1,6 @@
+-- The trading day whose close was stored in price_close. It differs from the event's own day
+-- when the event fell on a weekend or holiday and the previous session's close was used.
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS price_close_date DATE;
+
+-- Price enrichment looks up the events of a ticker that have no close yet
+CREATE INDEX IF NOT EXISTS idx_stocks_unpriced ON stocks (ticker, event_time) WHERE price_close IS NULL;
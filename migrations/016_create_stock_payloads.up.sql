@@ -0,0 +1,13 @@
+-- Raw upstream item each stock event was parsed from, with the run and time it was fetched.
+-- A later fetch only replaces it when the item itself changed, so batch_id points at the run
+-- that delivered the stored values. Events can be re-derived from it with 'ingestor reprocess'.
+CREATE TABLE IF NOT EXISTS stock_payloads (
+    stock_id UUID PRIMARY KEY REFERENCES stocks(id) ON DELETE CASCADE,
+    batch_id STRING NOT NULL,
+    source STRING NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL,
+    payload JSONB NOT NULL,
+
+    INDEX idx_stock_payloads_batch_id (batch_id),
+    INDEX idx_stock_payloads_source (source, stock_id)
+);
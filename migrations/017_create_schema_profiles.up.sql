@@ -0,0 +1,15 @@
+-- Shape of the upstream records each run received, and where it drifted from the baseline of its source.
+-- counts holds the unknown keys, missing fields and parse failures; rates are derived from records.
+CREATE TABLE IF NOT EXISTS schema_profiles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    batch_id STRING NOT NULL,
+    source STRING NOT NULL,
+    records INT NOT NULL,
+    counts JSONB NOT NULL,
+    drift JSONB NOT NULL DEFAULT '[]',
+    is_baseline BOOL NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    INDEX idx_schema_profiles_source_created_at (source, created_at DESC),
+    UNIQUE INDEX idx_schema_profiles_baseline (source) WHERE is_baseline
+);
@@ -0,0 +1,17 @@
+-- How the stocks of each run fared against the data-quality rules. Each stock counts once, under the worst
+-- severity it broke; rules holds the violations of every rule broken, keyed by rule name.
+CREATE TABLE IF NOT EXISTS quality_summaries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    batch_id STRING NOT NULL,
+    source STRING NOT NULL,
+    checked INT NOT NULL DEFAULT 0,
+    passed INT NOT NULL DEFAULT 0,
+    warned INT NOT NULL DEFAULT 0,
+    quarantined INT NOT NULL DEFAULT 0,
+    rejected INT NOT NULL DEFAULT 0,
+    rules JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE INDEX idx_quality_summaries_batch_id (batch_id),
+    INDEX idx_quality_summaries_created_at (created_at DESC)
+);
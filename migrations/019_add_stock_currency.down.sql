@@ -0,0 +1,4 @@
+ALTER TABLE stock_revisions DROP COLUMN IF EXISTS currency;
+
+DROP INDEX IF EXISTS stocks@idx_stocks_currency;
+ALTER TABLE stocks DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,6 @@
+-- ISO 4217 currency both targets of each event are quoted in. Rows that predate
+-- currency detection were all parsed as US dollars.
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS currency STRING NOT NULL DEFAULT 'USD';
+CREATE INDEX IF NOT EXISTS idx_stocks_currency ON stocks (currency);
+
+ALTER TABLE stock_revisions ADD COLUMN IF NOT EXISTS currency STRING NOT NULL DEFAULT 'USD';
@@ -0,0 +1,18 @@
+-- Domain events written in the same transaction as the state change they record, until a dispatcher
+-- has delivered them to every subscriber. A claimed event has next_attempt_at pushed past its claim,
+-- so an event whose dispatcher died is claimed again once that passes.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_type STRING NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    status STRING NOT NULL DEFAULT 'pending', -- 'pending', 'delivered', 'failed'
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error STRING,
+    delivered_at TIMESTAMPTZ,
+
+    INDEX idx_outbox_events_due (status, next_attempt_at),
+    INDEX idx_outbox_events_aggregate_id (aggregate_id, occurred_at)
+);
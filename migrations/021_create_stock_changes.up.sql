@@ -0,0 +1,15 @@
+-- Audit trail of stock events created, edited or deleted by admins through the API. There is no foreign
+-- key to stocks, so the trail of a deleted event is kept.
+CREATE TABLE IF NOT EXISTS stock_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    stock_id UUID NOT NULL,
+    kind STRING NOT NULL, -- 'created', 'updated', 'deleted'
+    changed_by UUID NOT NULL,
+    fields STRING[] NOT NULL DEFAULT ARRAY[],
+    before JSONB, -- the event before the change, NULL for a creation
+    after JSONB, -- the event after the change, NULL for a deletion
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    INDEX idx_stock_changes_stock_id (stock_id, changed_at DESC),
+    INDEX idx_stock_changes_changed_by (changed_by, changed_at DESC)
+);
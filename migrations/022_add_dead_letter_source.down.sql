@@ -0,0 +1 @@
+ALTER TABLE dead_letter_records DROP COLUMN IF EXISTS source;
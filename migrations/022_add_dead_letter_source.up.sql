@@ -0,0 +1,3 @@
+-- Records which upstream source each quarantined record came from, so a re-submitted stock keeps it.
+-- Records quarantined before this was tracked have no source.
+ALTER TABLE dead_letter_records ADD COLUMN IF NOT EXISTS source STRING NOT NULL DEFAULT '';
@@ -0,0 +1,46 @@
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/infrastructure/database"
+)
+
+func TestLeaseRepository_ExclusiveGrantsAndTakeover(t *testing.T) {
+	pool := openTestPool(t)
+	repo := database.NewLeaseRepository(pool, quietLogger())
+	ctx := context.Background()
+
+	name := fmt.Sprintf("test-lease-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), `DELETE FROM job_leases WHERE name = $1`, name)
+	})
+
+	first, err := repo.TryAcquire(ctx, name, "replica-1", 500*time.Millisecond)
+	require.NoError(t, err)
+
+	_, err = repo.TryAcquire(ctx, name, "replica-2", time.Minute)
+	assert.ErrorIs(t, err, repositories.ErrLeaseHeld)
+	require.NoError(t, repo.Renew(ctx, first, 500*time.Millisecond))
+
+	// The first holder stops renewing and its lease runs out
+	time.Sleep(time.Second)
+
+	second, err := repo.TryAcquire(ctx, name, "replica-2", time.Minute)
+	require.NoError(t, err)
+	assert.Greater(t, second.Token, first.Token)
+	assert.ErrorIs(t, repo.Renew(ctx, first, time.Minute), repositories.ErrLeaseLost)
+
+	// Releasing keeps the token sequence, so the next grant is still fenced above the last
+	require.NoError(t, repo.Release(ctx, second))
+	third, err := repo.TryAcquire(ctx, name, "replica-1", time.Minute)
+	require.NoError(t, err)
+	assert.Greater(t, third.Token, second.Token)
+}
@@ -0,0 +1,177 @@
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/infrastructure/database"
+	"stock-tracker/tests/mocks"
+)
+
+// These tests run against a migrated database given by TEST_DATABASE_URL and are skipped without one.
+// Run the benchmarks with: go test ./tests/integration -run '^$' -bench BulkUpsert
+
+func openTestPool(tb testing.TB) *pgxpool.Pool {
+	tb.Helper()
+
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		tb.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	conn, err := database.NewConnection(databaseURL)
+	require.NoError(tb, err)
+	tb.Cleanup(func() { conn.Close() })
+
+	return conn.GetPool()
+}
+
+func quietLogger() *mocks.MockLogger {
+	logger := &mocks.MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	logger.On("Warn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return logger
+}
+
+// createTestBroker stores a broker for the stocks of a test and removes both afterwards
+func createTestBroker(tb testing.TB, pool *pgxpool.Pool) *entities.Broker {
+	tb.Helper()
+
+	broker := entities.NewBroker(fmt.Sprintf("Bulk Load %d", time.Now().UnixNano()), 0.60)
+	require.NoError(tb, database.NewBrokerRepository(pool).Create(context.Background(), broker))
+
+	tb.Cleanup(func() {
+		ctx := context.Background()
+		_, _ = pool.Exec(ctx, `DELETE FROM stocks WHERE broker_id = $1`, broker.ID)
+		_, _ = pool.Exec(ctx, `DELETE FROM brokers WHERE id = $1`, broker.ID)
+	})
+
+	return broker
+}
+
+// stockBatch builds size distinct events for broker, all at eventTime
+func stockBatch(broker *entities.Broker, eventTime time.Time, size int) []*entities.Stock {
+	stocks := make([]*entities.Stock, size)
+	for i := range stocks {
+		stock := entities.NewStock(fmt.Sprintf("BL%04d", i), fmt.Sprintf("Company %d", i), broker.Name, "upgraded by", eventTime)
+		stock.BrokerID = broker.ID
+		stock.RatingFrom = "Hold"
+		stock.RatingTo = "Buy"
+		stock.TargetFrom = 100.0
+		stock.TargetTo = 120.0
+		stocks[i] = stock
+	}
+	return stocks
+}
+
+func TestStockRepository_BulkUpsert_CountsOutcomes(t *testing.T) {
+	pool := openTestPool(t)
+	broker := createTestBroker(t, pool)
+	ctx := context.Background()
+	eventTime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+
+	for name, repo := range map[string]repositories.StockRepository{
+		"Copy":     database.NewStockRepository(pool, quietLogger()),
+		"RowByRow": database.NewRowByRowStockRepository(pool, quietLogger()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := pool.Exec(ctx, `DELETE FROM stocks WHERE broker_id = $1`, broker.ID)
+			require.NoError(t, err)
+
+			result, err := repo.BulkUpsert(ctx, stockBatch(broker, eventTime, 3))
+			require.NoError(t, err)
+			assert.Equal(t, repositories.UpsertResult{Inserted: 3}, *result)
+
+			corrected := stockBatch(broker, eventTime, 4)
+			corrected[0].TargetTo = 125.0
+			result, err = repo.BulkUpsert(ctx, corrected)
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.Inserted)
+			assert.Equal(t, 1, result.Updated)
+			assert.Equal(t, 2, result.Duplicates)
+			assert.Equal(t, 0, result.Failed)
+
+			stored, err := repo.GetByTicker(ctx, corrected[0].Ticker)
+			require.NoError(t, err)
+			require.NotEmpty(t, stored)
+			assert.Equal(t, 125.0, stored[0].TargetTo)
+		})
+	}
+}
+
+func TestStockRepository_BulkUpdate_StoresPriceCloseDate(t *testing.T) {
+	pool := openTestPool(t)
+	broker := createTestBroker(t, pool)
+	ctx := context.Background()
+	eventTime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	closeDate := time.Date(eventTime.Year(), eventTime.Month(), eventTime.Day(), 0, 0, 0, 0, time.UTC)
+
+	for name, repo := range map[string]repositories.StockRepository{
+		"Copy":     database.NewStockRepository(pool, quietLogger()),
+		"RowByRow": database.NewRowByRowStockRepository(pool, quietLogger()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := pool.Exec(ctx, `DELETE FROM stocks WHERE broker_id = $1`, broker.ID)
+			require.NoError(t, err)
+
+			stocks := stockBatch(broker, eventTime, 2)
+			_, err = repo.BulkUpsert(ctx, stocks)
+			require.NoError(t, err)
+
+			for _, stock := range stocks {
+				price := 187.5
+				stock.PriceClose, stock.PriceCloseDate = &price, &closeDate
+			}
+			require.NoError(t, repo.BulkUpdate(ctx, stocks))
+
+			stored, err := repo.GetByID(ctx, stocks[0].ID)
+			require.NoError(t, err)
+			require.NotNil(t, stored.PriceClose)
+			assert.Equal(t, 187.5, *stored.PriceClose)
+			require.NotNil(t, stored.PriceCloseDate)
+			assert.True(t, closeDate.Equal(stored.PriceCloseDate.UTC()), "stored %s", stored.PriceCloseDate)
+		})
+	}
+}
+
+func BenchmarkStockRepository_BulkUpsert(b *testing.B) {
+	pool := openTestPool(b)
+	broker := createTestBroker(b, pool)
+	ctx := context.Background()
+	base := time.Now().Add(-365 * 24 * time.Hour).Truncate(time.Second)
+
+	benchmarks := []struct {
+		name string
+		repo repositories.StockRepository
+	}{
+		{"Copy", database.NewStockRepository(pool, quietLogger())},
+		{"RowByRow", database.NewRowByRowStockRepository(pool, quietLogger())},
+	}
+
+	offset := 0
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				offset++
+				stocks := stockBatch(broker, base.Add(time.Duration(offset)*time.Minute), 100)
+				b.StartTimer()
+
+				if _, err := bm.repo.BulkUpsert(ctx, stocks); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
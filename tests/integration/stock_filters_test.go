@@ -0,0 +1,137 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/valueObjects"
+	"stock-tracker/internal/infrastructure/database"
+)
+
+func TestStockRepository_GetAll_Filters(t *testing.T) {
+	pool := openTestPool(t)
+	broker := createTestBroker(t, pool)
+	repo := database.NewStockRepository(pool, quietLogger())
+	ctx := context.Background()
+	eventTime := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+
+	stocks := stockBatch(broker, eventTime, 3)
+	// BL0000 upgrades Hold to Buy and raises its target by 20%
+	stocks[0].ActionType, stocks[0].RatingFromTier, stocks[0].RatingToTier = entities.ActionTypeUpgrade, entities.RatingTierHold, entities.RatingTierBuy
+	// BL0001 downgrades Buy to Sell and cuts its target by 10%
+	stocks[1].Action, stocks[1].RatingFrom, stocks[1].RatingTo, stocks[1].TargetTo = "downgraded by", "Buy", "Sell", 90
+	stocks[1].ActionType, stocks[1].RatingFromTier, stocks[1].RatingToTier = entities.ActionTypeDowngrade, entities.RatingTierBuy, entities.RatingTierSell
+	// BL0002 keeps a rating no tier covers
+	stocks[2].RatingTo = "Top Pick"
+	_, err := repo.BulkUpsert(ctx, stocks)
+	require.NoError(t, err)
+
+	minChange, maxChange := 15.0, 25.0
+	testCases := []struct {
+		name     string
+		filters  valueObjects.StockFilters
+		expected []string
+	}{
+		{name: "Exact ticker", filters: valueObjects.StockFilters{Ticker: "bl0001", TickerMatch: valueObjects.TickerMatchExact}, expected: []string{"BL0001"}},
+		{name: "Tickers", filters: valueObjects.StockFilters{Tickers: []string{"BL0000", "bl0002"}}, expected: []string{"BL0000", "BL0002"}},
+		{name: "Action", filters: valueObjects.StockFilters{Action: "downgraded"}, expected: []string{"BL0001"}},
+		{name: "Action type", filters: valueObjects.StockFilters{ActionType: entities.ActionTypeUpgrade}, expected: []string{"BL0000"}},
+		{name: "Ratings", filters: valueObjects.StockFilters{RatingFrom: "hold", RatingTo: "BUY"}, expected: []string{"BL0000"}},
+		{name: "Upgrades", filters: valueObjects.StockFilters{RatingDirection: valueObjects.RatingDirectionUpgrade}, expected: []string{"BL0000"}},
+		{name: "Downgrades", filters: valueObjects.StockFilters{RatingDirection: valueObjects.RatingDirectionDowngrade}, expected: []string{"BL0001"}},
+		{name: "Target change", filters: valueObjects.StockFilters{MinTargetChange: &minChange, MaxTargetChange: &maxChange}, expected: []string{"BL0000", "BL0002"}},
+		{name: "Sort by target change then ticker", filters: valueObjects.StockFilters{Sort: []valueObjects.SortKey{
+			{Field: valueObjects.SortTargetChange, Descending: true}, {Field: valueObjects.SortTicker, Descending: true},
+		}}, expected: []string{"BL0002", "BL0000", "BL0001"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.filters.Brokerages = []string{broker.Name}
+			tc.filters.SortBy, tc.filters.SortOrder = "ticker", "asc"
+
+			found, _, err := repo.GetAll(ctx, tc.filters)
+			require.NoError(t, err)
+
+			tickers := make([]string, len(found))
+			for i, stock := range found {
+				tickers[i] = stock.Ticker
+			}
+			assert.Equal(t, tc.expected, tickers)
+		})
+	}
+}
+
+func TestStockRepository_GetAll_CursorPagination(t *testing.T) {
+	pool := openTestPool(t)
+	broker := createTestBroker(t, pool)
+	repo := database.NewStockRepository(pool, quietLogger())
+	ctx := context.Background()
+	eventTime := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+
+	// Every stock ties on event time, so only the ID tie-break orders them
+	_, err := repo.BulkUpsert(ctx, stockBatch(broker, eventTime, 5))
+	require.NoError(t, err)
+
+	filters := valueObjects.StockFilters{Brokerages: []string{broker.Name}, Limit: 5}
+	all, _, err := repo.GetAll(ctx, filters)
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+
+	// Walk forward from an offset page
+	filters.Limit = 2
+	var walked []*entities.Stock
+	page, pagination, err := repo.GetAll(ctx, filters)
+	require.NoError(t, err)
+	walked = append(walked, page...)
+	for pagination.HasNext {
+		filters.Cursor = pagination.NextCursor
+		page, pagination, err = repo.GetAll(ctx, filters)
+		require.NoError(t, err)
+		assert.Equal(t, valueObjects.TotalUnknown, pagination.TotalItems)
+		walked = append(walked, page...)
+	}
+	require.Len(t, walked, 5)
+	for i := range all {
+		assert.Equal(t, all[i].ID, walked[i].ID)
+	}
+
+	// Walk back from the last page
+	require.Len(t, page, 1)
+	filters.Cursor, filters.IncludeTotal = pagination.PrevCursor, true
+	page, pagination, err = repo.GetAll(ctx, filters)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, all[2].ID, page[0].ID)
+	assert.Equal(t, all[3].ID, page[1].ID)
+	assert.True(t, pagination.HasNext)
+	assert.True(t, pagination.HasPrev)
+	assert.Equal(t, 5, pagination.TotalItems)
+
+	// A cursor only continues the sort it was issued for
+	filters.Sort = []valueObjects.SortKey{{Field: valueObjects.SortTicker}}
+	_, _, err = repo.GetAll(ctx, filters)
+	assert.ErrorIs(t, err, repositories.ErrInvalidCursor)
+}
+
+func TestStockRepository_GetByTickerWithCredibility(t *testing.T) {
+	pool := openTestPool(t)
+	broker := createTestBroker(t, pool)
+	repo := database.NewStockRepository(pool, quietLogger())
+	ctx := context.Background()
+
+	stocks := stockBatch(broker, time.Now().Add(-48*time.Hour).Truncate(time.Second), 1)
+	_, err := repo.BulkUpsert(ctx, stocks)
+	require.NoError(t, err)
+
+	found, credibility, err := repo.GetByTickerWithCredibility(ctx, stocks[0].Ticker)
+	require.NoError(t, err)
+	require.NotEmpty(t, found)
+	assert.InDelta(t, broker.CredibilityScore, credibility[broker.ID], 1e-9)
+}
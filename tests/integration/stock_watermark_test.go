@@ -0,0 +1,51 @@
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/infrastructure/database"
+)
+
+func TestStockRepository_GetLatestEventTime_PerSource(t *testing.T) {
+	pool := openTestPool(t)
+	broker := createTestBroker(t, pool)
+	repo := database.NewStockRepository(pool, quietLogger())
+	ctx := context.Background()
+
+	suffix := time.Now().UnixNano()
+	leading, lagging := fmt.Sprintf("leading-%d", suffix), fmt.Sprintf("lagging-%d", suffix)
+	leadingTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	laggingTime := leadingTime.Add(-24 * time.Hour)
+
+	stocks := stockBatch(broker, leadingTime, 2)
+	for _, stock := range stocks {
+		stock.Source = leading
+	}
+	laggingStocks := stockBatch(broker, laggingTime, 2)
+	for _, stock := range laggingStocks {
+		stock.Source = lagging
+	}
+	_, err := repo.BulkUpsert(ctx, append(stocks, laggingStocks...))
+	require.NoError(t, err)
+
+	latest, err := repo.GetLatestEventTime(ctx, leading)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.True(t, leadingTime.Equal(*latest))
+
+	// The lagging source keeps its own watermark, behind the newest event of the other
+	latest, err = repo.GetLatestEventTime(ctx, lagging)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.True(t, laggingTime.Equal(*latest))
+
+	latest, err = repo.GetLatestEventTime(ctx, fmt.Sprintf("silent-%d", suffix))
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+}
@@ -149,6 +149,11 @@ func (m *MockSubscriptionRepository) Update(ctx context.Context, subscription *e
 	return args.Error(0)
 }
 
+func (m *MockSubscriptionRepository) Activate(ctx context.Context, subscription *entities.Subscription, user *entities.User) error {
+	args := m.Called(ctx, subscription, user)
+	return args.Error(0)
+}
+
 func (m *MockSubscriptionRepository) GetExpiring(ctx context.Context, within time.Duration) ([]*entities.Subscription, error) {
 	args := m.Called(ctx, within)
 	if args.Get(0) == nil {
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/infrastructure/clients"
 )
 
 // MockStockAPIClient implements clients.StockAPIClient for testing
@@ -13,6 +14,16 @@ type MockStockAPIClient struct {
 	mock.Mock
 }
 
+func (m *MockStockAPIClient) Name() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockStockAPIClient) NewestFirst() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
 func (m *MockStockAPIClient) FetchAllStocks(ctx context.Context) ([]*entities.Stock, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]*entities.Stock), args.Error(1)
@@ -22,3 +33,11 @@ func (m *MockStockAPIClient) FetchPage(ctx context.Context, nextPage string) ([]
 	args := m.Called(ctx, nextPage)
 	return args.Get(0).([]*entities.Stock), args.String(1), args.Error(2)
 }
+
+func (m *MockStockAPIClient) FetchRawPage(ctx context.Context, nextPage string) (*clients.StockAPIResponse, error) {
+	args := m.Called(ctx, nextPage)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*clients.StockAPIResponse), args.Error(1)
+}
@@ -24,6 +24,9 @@ func (m *MockStockRepository) Create(ctx context.Context, stock *entities.Stock)
 
 func (m *MockStockRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Stock, error) {
 	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).(*entities.Stock), args.Error(1)
 }
 
@@ -37,11 +40,26 @@ func (m *MockStockRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockStockRepository) ApplyChange(ctx context.Context, change *entities.StockChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
 func (m *MockStockRepository) GetByTicker(ctx context.Context, ticker string) ([]*entities.Stock, error) {
 	args := m.Called(ctx, ticker)
 	return args.Get(0).([]*entities.Stock), args.Error(1)
 }
 
+func (m *MockStockRepository) GetByTickerWithCredibility(ctx context.Context, ticker string) ([]*entities.Stock, map[uuid.UUID]float64, error) {
+	args := m.Called(ctx, ticker)
+	return args.Get(0).([]*entities.Stock), args.Get(1).(map[uuid.UUID]float64), args.Error(2)
+}
+
+func (m *MockStockRepository) GetByTickerAsOf(ctx context.Context, ticker string, asOf time.Time) ([]*entities.Stock, error) {
+	args := m.Called(ctx, ticker, asOf)
+	return args.Get(0).([]*entities.Stock), args.Error(1)
+}
+
 func (m *MockStockRepository) GetLatestByTicker(ctx context.Context, ticker string) (*entities.Stock, error) {
 	args := m.Called(ctx, ticker)
 	return args.Get(0).(*entities.Stock), args.Error(1)
@@ -67,6 +85,19 @@ func (m *MockStockRepository) BulkUpdate(ctx context.Context, stocks []*entities
 	return args.Error(0)
 }
 
+func (m *MockStockRepository) GetNearbyEvents(ctx context.Context, tickers []string, from, to time.Time) ([]*entities.Stock, error) {
+	args := m.Called(ctx, tickers, from, to)
+	return args.Get(0).([]*entities.Stock), args.Error(1)
+}
+
+func (m *MockStockRepository) BulkUpsert(ctx context.Context, stocks []*entities.Stock) (*repositories.UpsertResult, error) {
+	args := m.Called(ctx, stocks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repositories.UpsertResult), args.Error(1)
+}
+
 func (m *MockStockRepository) GetTopMoversByTarget(ctx context.Context, limit int) ([]*entities.Stock, error) {
 	args := m.Called(ctx, limit)
 	return args.Get(0).([]*entities.Stock), args.Error(1)
@@ -77,11 +108,42 @@ func (m *MockStockRepository) GetUniqueTickersCount(ctx context.Context) (int, e
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockStockRepository) GetLatestEventTime(ctx context.Context, source string) (*time.Time, error) {
+	args := m.Called(ctx, source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
 func (m *MockStockRepository) GetBrokerageStats(ctx context.Context) ([]repositories.BrokerageStats, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]repositories.BrokerageStats), args.Error(1)
 }
 
+func (m *MockStockRepository) ListPricingTickers(ctx context.Context, filter repositories.PricingFilter) ([]string, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockStockRepository) ListPricingCandidates(ctx context.Context, ticker string, filter repositories.PricingFilter) ([]repositories.PricingCandidate, error) {
+	args := m.Called(ctx, ticker, filter)
+	return args.Get(0).([]repositories.PricingCandidate), args.Error(1)
+}
+
+func (m *MockStockRepository) UpdateClosePrices(ctx context.Context, prices []repositories.ClosePrice) (int, error) {
+	args := m.Called(ctx, prices)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStockRepository) ListPayloads(ctx context.Context, filter repositories.PayloadFilter) ([]repositories.StoredPayload, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repositories.StoredPayload), args.Error(1)
+}
+
 // MockBrokerRepository implements repositories.BrokerRepository for testing
 type MockBrokerRepository struct {
 	mock.Mock
@@ -121,3 +183,257 @@ func (m *MockBrokerRepository) UpsertByName(ctx context.Context, broker *entitie
 	args := m.Called(ctx, broker)
 	return args.Error(0)
 }
+
+func (m *MockBrokerRepository) ListAliases(ctx context.Context) ([]*entities.BrokerAlias, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entities.BrokerAlias), args.Error(1)
+}
+
+func (m *MockBrokerRepository) CreateAlias(ctx context.Context, alias *entities.BrokerAlias) error {
+	args := m.Called(ctx, alias)
+	return args.Error(0)
+}
+
+func (m *MockBrokerRepository) DeleteAlias(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBrokerRepository) Merge(ctx context.Context, merge *entities.BrokerMerge) error {
+	args := m.Called(ctx, merge)
+	return args.Error(0)
+}
+
+func (m *MockBrokerRepository) ListMerges(ctx context.Context, limit int) ([]*entities.BrokerMerge, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*entities.BrokerMerge), args.Error(1)
+}
+
+// MockIngestionLogRepository implements repositories.IngestionLogRepository for testing
+type MockIngestionLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockIngestionLogRepository) Create(ctx context.Context, log *entities.IngestionLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockIngestionLogRepository) Update(ctx context.Context, log *entities.IngestionLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockIngestionLogRepository) GetByBatchID(ctx context.Context, batchID string) (*entities.IngestionLog, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.IngestionLog), args.Error(1)
+}
+
+func (m *MockIngestionLogRepository) List(ctx context.Context, limit, offset int) ([]*entities.IngestionLog, int, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*entities.IngestionLog), args.Int(1), args.Error(2)
+}
+
+func (m *MockIngestionLogRepository) GetLastSuccessful(ctx context.Context) (*entities.IngestionLog, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.IngestionLog), args.Error(1)
+}
+
+// MockIngestionCheckpointRepository implements repositories.IngestionCheckpointRepository for testing
+type MockIngestionCheckpointRepository struct {
+	mock.Mock
+}
+
+func (m *MockIngestionCheckpointRepository) Save(ctx context.Context, checkpoint *entities.IngestionCheckpoint) error {
+	args := m.Called(ctx, checkpoint)
+	return args.Error(0)
+}
+
+func (m *MockIngestionCheckpointRepository) GetLatest(ctx context.Context, source string) (*entities.IngestionCheckpoint, error) {
+	args := m.Called(ctx, source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.IngestionCheckpoint), args.Error(1)
+}
+
+// MockSchemaProfileRepository implements repositories.SchemaProfileRepository for testing
+type MockSchemaProfileRepository struct {
+	mock.Mock
+}
+
+func (m *MockSchemaProfileRepository) Save(ctx context.Context, profile *entities.SchemaProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockSchemaProfileRepository) GetBaseline(ctx context.Context, source string) (*entities.SchemaProfile, error) {
+	args := m.Called(ctx, source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.SchemaProfile), args.Error(1)
+}
+
+func (m *MockSchemaProfileRepository) GetLatest(ctx context.Context, source string) (*entities.SchemaProfile, error) {
+	args := m.Called(ctx, source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.SchemaProfile), args.Error(1)
+}
+
+func (m *MockSchemaProfileRepository) SetBaseline(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockQualitySummaryRepository implements repositories.QualitySummaryRepository for testing
+type MockQualitySummaryRepository struct {
+	mock.Mock
+}
+
+func (m *MockQualitySummaryRepository) Save(ctx context.Context, summary *entities.QualitySummary) error {
+	args := m.Called(ctx, summary)
+	return args.Error(0)
+}
+
+func (m *MockQualitySummaryRepository) GetByBatchID(ctx context.Context, batchID string) (*entities.QualitySummary, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.QualitySummary), args.Error(1)
+}
+
+func (m *MockQualitySummaryRepository) List(ctx context.Context, limit, offset int) ([]*entities.QualitySummary, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entities.QualitySummary), args.Int(1), args.Error(2)
+}
+
+// MockOutboxRepository implements repositories.OutboxRepository for testing
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Claim(ctx context.Context, limit int, claimFor time.Duration) ([]*entities.OutboxEvent, error) {
+	args := m.Called(ctx, limit, claimFor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) Reschedule(ctx context.Context, id uuid.UUID, lastError string, at time.Time) error {
+	args := m.Called(ctx, id, lastError, at)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	args := m.Called(ctx, id, lastError)
+	return args.Error(0)
+}
+
+// MockStockChangeRepository implements repositories.StockChangeRepository for testing
+type MockStockChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockChangeRepository) ListByStock(ctx context.Context, stockID uuid.UUID) ([]*entities.StockChange, error) {
+	args := m.Called(ctx, stockID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.StockChange), args.Error(1)
+}
+
+// MockDeadLetterRepository implements repositories.DeadLetterRepository for testing
+type MockDeadLetterRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterRepository) Create(ctx context.Context, record *entities.DeadLetterRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockDeadLetterRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.DeadLetterRecord), args.Error(1)
+}
+
+func (m *MockDeadLetterRepository) List(ctx context.Context, status entities.DeadLetterStatus, limit, offset int) ([]*entities.DeadLetterRecord, int, error) {
+	args := m.Called(ctx, status, limit, offset)
+	return args.Get(0).([]*entities.DeadLetterRecord), args.Int(1), args.Error(2)
+}
+
+func (m *MockDeadLetterRepository) Update(ctx context.Context, record *entities.DeadLetterRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+// MockNormalizationRepository implements repositories.NormalizationRepository for testing
+type MockNormalizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNormalizationRepository) ListRules(ctx context.Context, kind entities.NormalizationKind) ([]*entities.NormalizationRule, error) {
+	args := m.Called(ctx, kind)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.NormalizationRule), args.Error(1)
+}
+
+func (m *MockNormalizationRepository) GetRule(ctx context.Context, id uuid.UUID) (*entities.NormalizationRule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.NormalizationRule), args.Error(1)
+}
+
+func (m *MockNormalizationRepository) CreateRule(ctx context.Context, rule *entities.NormalizationRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockNormalizationRepository) UpdateRule(ctx context.Context, rule *entities.NormalizationRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockNormalizationRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockNormalizationRepository) ListStockLabels(ctx context.Context) ([]repositories.StockLabels, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repositories.StockLabels), args.Error(1)
+}
+
+func (m *MockNormalizationRepository) UpdateStockLabels(ctx context.Context, labels []repositories.StockLabels) (int, error) {
+	args := m.Called(ctx, labels)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,96 @@
+package clients_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/infrastructure/clients"
+)
+
+func TestConvertAPIItem_DetectsCurrency(t *testing.T) {
+	testCases := []struct {
+		name       string
+		from, to   string
+		wantFrom   float64
+		wantTo     float64
+		wantSymbol string
+	}{
+		{name: "dollar sign", from: "$150.00", to: "$180.00", wantFrom: 150, wantTo: 180, wantSymbol: "USD"},
+		{name: "euro symbol", from: "€40", to: "€45", wantFrom: 40, wantTo: 45, wantSymbol: "EUR"},
+		{name: "trailing ISO code", from: "40.00 EUR", to: "45.00 eur", wantFrom: 40, wantTo: 45, wantSymbol: "EUR"},
+		{name: "prefixed dollar", from: "HK$300", to: "HK$320", wantFrom: 300, wantTo: 320, wantSymbol: "HKD"},
+		{name: "pence", from: "GBp 1,100", to: "GBp 1,250", wantFrom: 11, wantTo: 12.5, wantSymbol: "GBP"},
+		{name: "no currency", from: "150", to: "180", wantFrom: 150, wantTo: 180, wantSymbol: "USD"},
+		{name: "only one target", from: "", to: "£12", wantFrom: 0, wantTo: 12, wantSymbol: "GBP"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stock, err := clients.ConvertAPIItem(clients.StockAPIItem{
+				Ticker:     "TEST",
+				TargetFrom: tc.from,
+				TargetTo:   tc.to,
+				Company:    "Test Company",
+				Action:     "target raised by",
+				Brokerage:  "Test Brokerage",
+				Time:       "2024-01-15T10:30:00Z",
+			})
+
+			require.NoError(t, err)
+			assert.InDelta(t, tc.wantFrom, stock.TargetFrom, 1e-9)
+			assert.InDelta(t, tc.wantTo, stock.TargetTo, 1e-9)
+			assert.Equal(t, tc.wantSymbol, stock.Currency)
+		})
+	}
+}
+
+func TestConvertAPIItem_MismatchedCurrencies(t *testing.T) {
+	_, err := clients.ConvertAPIItem(clients.StockAPIItem{
+		Ticker:     "TEST",
+		TargetFrom: "$150.00",
+		TargetTo:   "€180.00",
+		Company:    "Test Company",
+		Action:     "target raised by",
+		Brokerage:  "Test Brokerage",
+		Time:       "2024-01-15T10:30:00Z",
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different currencies")
+}
+
+func TestLoadFXRates(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.csv")
+	override := filepath.Join(dir, "override.csv")
+	require.NoError(t, os.WriteFile(base, []byte("Base,Quote,Rate,Date\nUSD,EUR,0.90,2024-01-15\nUSD,GBP,0.80,2024-01-15\n"), 0o644))
+	require.NoError(t, os.WriteFile(override, []byte("quote,base,rate\nEUR,usd,0.92\n"), 0o644))
+
+	// Act
+	table, err := clients.LoadFXRates([]string{base, override})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "GBP", "USD"}, table.Currencies())
+	rate, ok := table.Rate("USD", "EUR")
+	require.True(t, ok)
+	assert.InDelta(t, 0.92, rate, 1e-9)
+}
+
+func TestLoadFXRates_InvalidRate(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "rates.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Base,Quote,Rate\nUSD,EUR,n/a\n"), 0o644))
+
+	// Act
+	_, err := clients.LoadFXRates([]string{path})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
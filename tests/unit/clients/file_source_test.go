@@ -0,0 +1,166 @@
+package clients_test
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/infrastructure/clients"
+	"stock-tracker/tests/mocks"
+)
+
+func writeSourceFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	if filepath.Ext(name) == ".gz" {
+		gz := gzip.NewWriter(file)
+		_, err = gz.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		return path
+	}
+
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	return path
+}
+
+func quietLogger() *mocks.MockLogger {
+	logger := &mocks.MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return logger
+}
+
+func TestFileSource_CSVWithColumnMapping(t *testing.T) {
+	// Arrange
+	path := writeSourceFile(t, "history.csv", `Symbol,Name,Firm,Action,From,To,Old Target,New Target,Date
+AAPL,Apple Inc.,Goldman Sachs,upgraded by,Hold,Buy,$150.00,$180.00,2021-01-15T10:30:00Z
+MSFT,Microsoft,Morgan Stanley,target raised by,Buy,Buy,$300.00
+`)
+	mapping, err := clients.ParseColumnMapping("ticker=Symbol,company=Name,brokerage=Firm,action=Action," +
+		"rating_from=From,rating_to=To,target_from=Old Target,target_to=New Target,time=Date")
+	require.NoError(t, err)
+
+	source, err := clients.NewFileSource(path, clients.FileFormatCSV, mapping, 10, quietLogger())
+	require.NoError(t, err)
+
+	// Act
+	page, err := source.FetchRawPage(context.Background(), "")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, page.NextPage)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, clients.StockAPIItem{
+		Ticker:     "AAPL",
+		TargetFrom: "$150.00",
+		TargetTo:   "$180.00",
+		Company:    "Apple Inc.",
+		Action:     "upgraded by",
+		Brokerage:  "Goldman Sachs",
+		RatingFrom: "Hold",
+		RatingTo:   "Buy",
+		Time:       "2021-01-15T10:30:00Z",
+	}, page.Items[0])
+	// Missing trailing columns are left empty so validation can quarantine the record
+	assert.Equal(t, "$300.00", page.Items[1].TargetFrom)
+	assert.Empty(t, page.Items[1].Time)
+	assert.False(t, source.NewestFirst())
+	assert.Equal(t, "file:"+path, source.Name())
+}
+
+func TestFileSource_GzippedNDJSONPages(t *testing.T) {
+	// Arrange
+	path := writeSourceFile(t, "history.ndjson.gz", `{"ticker":"AAPL","company":"Apple Inc.","target_to":180.5,"time":"2021-01-15T10:30:00Z"}
+
+{"ticker":"MSFT","company":"Microsoft","target_to":"$300","rating_to":null,"time":"2021-01-16T10:30:00Z"}
+{"ticker":"GOOGL","company":"Alphabet","time":"2021-01-17T10:30:00Z"}`)
+
+	source, err := clients.NewFileSource(path, clients.FileFormatNDJSON, clients.DefaultColumnMapping(), 2, quietLogger())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Act
+	first, err := source.FetchRawPage(ctx, "")
+	require.NoError(t, err)
+	second, err := source.FetchRawPage(ctx, first.NextPage)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, first.Items, 2)
+	assert.Equal(t, "2", first.NextPage)
+	assert.Equal(t, "180.5", first.Items[0].TargetTo)
+	assert.Equal(t, "$300", first.Items[1].TargetTo)
+	assert.Empty(t, first.Items[1].RatingTo)
+
+	require.Len(t, second.Items, 1)
+	assert.Equal(t, "GOOGL", second.Items[0].Ticker)
+	assert.Empty(t, second.NextPage)
+}
+
+func TestFileSource_ResumesFromCursor(t *testing.T) {
+	// Arrange
+	path := writeSourceFile(t, "history.csv", `ticker,company,action,brokerage,time
+AAPL,Apple Inc.,upgraded by,Goldman Sachs,2021-01-15T10:30:00Z
+MSFT,Microsoft,upgraded by,Goldman Sachs,2021-01-16T10:30:00Z
+GOOGL,Alphabet,upgraded by,Goldman Sachs,2021-01-17T10:30:00Z
+`)
+
+	// A fresh source stands in for a restarted ingestor resuming from a checkpoint
+	source, err := clients.NewFileSource(path, clients.FileFormatCSV, clients.DefaultColumnMapping(), 1, quietLogger())
+	require.NoError(t, err)
+
+	// Act
+	page, err := source.FetchRawPage(context.Background(), "2")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "GOOGL", page.Items[0].Ticker)
+	assert.Empty(t, page.NextPage)
+
+	_, err = source.FetchRawPage(context.Background(), "7")
+	assert.Error(t, err)
+}
+
+func TestDetectFileFormat(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected clients.FileFormat
+	}{
+		{"history.csv", clients.FileFormatCSV},
+		{"HISTORY.CSV.GZ", clients.FileFormatCSV},
+		{"events.ndjson", clients.FileFormatNDJSON},
+		{"events.jsonl.gz", clients.FileFormatNDJSON},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			format, err := clients.DetectFileFormat(tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+
+	_, err := clients.DetectFileFormat("events.xml")
+	assert.Error(t, err)
+}
+
+func TestParseColumnMapping_Invalid(t *testing.T) {
+	_, err := clients.ParseColumnMapping("ticker")
+	assert.Error(t, err)
+
+	_, err = clients.ParseColumnMapping("price=Close")
+	assert.Error(t, err)
+}
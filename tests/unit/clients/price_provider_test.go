@@ -0,0 +1,106 @@
+package clients_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/infrastructure/clients"
+)
+
+func date(value string) time.Time {
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		panic(err)
+	}
+	return day
+}
+
+func TestCSVPriceProvider_DailyCloses(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	ohlc := "Date,Open,High,Low,Close,Volume\n" +
+		"2024-01-16,182.16,184.26,180.93,183.63,65603000\n" +
+		"2024-01-11,186.54,187.05,183.62,185.59,49128400\n" +
+		"2024-01-12,186.06,186.74,185.19,185.92,40444700\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "AAPL.csv"), []byte(ohlc), 0o644))
+
+	provider, err := clients.NewCSVPriceProvider(dir, quietLogger())
+	require.NoError(t, err)
+
+	// Act
+	closes, err := provider.DailyCloses(context.Background(), "aapl", date("2024-01-12"), date("2024-01-16"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []clients.DailyClose{
+		{Date: date("2024-01-12"), Close: 185.92},
+		{Date: date("2024-01-16"), Close: 183.63},
+	}, closes)
+}
+
+func TestCSVPriceProvider_UnknownTicker(t *testing.T) {
+	// Arrange
+	provider, err := clients.NewCSVPriceProvider(t.TempDir(), quietLogger())
+	require.NoError(t, err)
+
+	// Act
+	closes, err := provider.DailyCloses(context.Background(), "MSFT", date("2024-01-01"), date("2024-01-31"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, closes)
+}
+
+func TestCSVPriceProvider_InvalidRow(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "AAPL.csv"), []byte("Date,Close\n2024-01-12,n/a\n"), 0o644))
+	provider, err := clients.NewCSVPriceProvider(dir, quietLogger())
+	require.NoError(t, err)
+
+	// Act
+	_, err = provider.DailyCloses(context.Background(), "AAPL", date("2024-01-01"), date("2024-01-31"))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestHTTPPriceProvider_DailyCloses(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/AAPL/daily":
+			assert.Equal(t, "2024-01-12", r.URL.Query().Get("from"))
+			assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+			w.Write([]byte(`{"closes":[{"date":"2024-01-12","close":185.92}]}`))
+		case "/MSFT/daily":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := clients.NewHTTPPriceProvider("vendor", server.URL, "test-key", quietLogger())
+
+	// Act
+	closes, err := provider.DailyCloses(context.Background(), "AAPL", date("2024-01-12"), date("2024-01-16"))
+	unknown, unknownErr := provider.DailyCloses(context.Background(), "ZZZZ", date("2024-01-12"), date("2024-01-16"))
+	_, unauthorizedErr := provider.DailyCloses(context.Background(), "MSFT", date("2024-01-12"), date("2024-01-16"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []clients.DailyClose{{Date: date("2024-01-12"), Close: 185.92}}, closes)
+	require.NoError(t, unknownErr)
+	assert.Empty(t, unknown)
+	assert.ErrorIs(t, unauthorizedErr, clients.ErrUnauthorized)
+}
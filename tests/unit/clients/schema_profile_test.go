@@ -0,0 +1,61 @@
+package clients_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/infrastructure/clients"
+)
+
+func TestStockAPIResponse_KeepsRawItems(t *testing.T) {
+	body := `{"items":[{"ticker":"AAPL","time":"2024-01-15T10:30:00Z","currency":"USD"}],"next_page":"page2"}`
+
+	var page clients.StockAPIResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &page))
+
+	assert.Equal(t, "page2", page.NextPage)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "AAPL", page.Items[0].Ticker)
+	require.Len(t, page.RawItems, 1)
+	assert.JSONEq(t, `{"ticker":"AAPL","time":"2024-01-15T10:30:00Z","currency":"USD"}`, string(page.RawItems[0]))
+}
+
+func TestProfilePage_RawItems(t *testing.T) {
+	body := `{"items":[
+		{"ticker":"AAPL","company":"Apple Inc.","brokerage":"Goldman Sachs","action":"upgraded by",
+		 "rating_from":"Hold","rating_to":"Buy","target_from":"$150.00","target_to":"$180.00","time":"2024-01-15T10:30:00Z"},
+		{"ticker":"MSFT","company":"Microsoft","brokerage":null,"action":"initiated by",
+		 "rating_from":"","rating_to":"Buy","target_to":"one hundred","time":"01/15/2024","price_target":"$400"}
+	]}`
+
+	var page clients.StockAPIResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &page))
+
+	profile := entities.NewSchemaProfile("batch", "api")
+	clients.ProfilePage(profile, page.Items, page.RawItems)
+
+	assert.Equal(t, 2, profile.Records)
+	assert.Equal(t, map[string]int{"price_target": 1}, profile.UnknownKeys)
+	assert.Equal(t, map[string]int{"brokerage": 1, "rating_from": 1, "target_from": 1}, profile.MissingFields)
+	assert.Equal(t, 2, profile.TimeValues)
+	assert.Equal(t, 1, profile.TimeFailures)
+	assert.Equal(t, 3, profile.PriceValues)
+	assert.Equal(t, 1, profile.PriceFailures)
+}
+
+func TestProfilePage_DecodedItems(t *testing.T) {
+	items := []clients.StockAPIItem{{Ticker: "AAPL", Company: "Apple Inc.", TargetTo: "$180", Time: "2024-01-15T10:30:00Z"}}
+
+	profile := entities.NewSchemaProfile("batch", "file")
+	clients.ProfilePage(profile, items, nil)
+
+	assert.Empty(t, profile.UnknownKeys)
+	assert.Equal(t, map[string]int{
+		"target_from": 1, "action": 1, "brokerage": 1, "rating_from": 1, "rating_to": 1,
+	}, profile.MissingFields)
+	assert.Equal(t, 0.0, profile.PriceFailureRate())
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -117,6 +118,25 @@ func TestStockAPIClient_FetchPage_WithNextPage(t *testing.T) {
 	assert.Empty(t, nextPage)
 }
 
+func TestStockAPIClient_FetchPage_EscapesNextPage(t *testing.T) {
+	// Arrange
+	cursor := "ticker=AAPL&after=2024-01-15T10:30:00+00:00"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{cursor}, r.URL.Query()["next_page"])
+		assert.Len(t, r.URL.Query(), 1)
+		emptyPage(w)
+	}))
+	defer server.Close()
+
+	client := clients.NewStockAPIClient(server.URL, "test-api-key", &mocks.MockLogger{})
+
+	// Act
+	_, _, err := client.FetchPage(context.Background(), cursor)
+
+	// Assert
+	require.NoError(t, err)
+}
+
 func TestStockAPIClient_FetchPage_HTTPError(t *testing.T) {
 	// Arrange
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -125,14 +145,16 @@ func TestStockAPIClient_FetchPage_HTTPError(t *testing.T) {
 	defer server.Close()
 
 	logger := &mocks.MockLogger{}
-	client := clients.NewStockAPIClient(server.URL, "test-api-key", logger)
+	logger.On("Warn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := clients.NewStockAPIClientWithPolicy("api", server.URL, "test-api-key", fastPolicy(), logger)
 
 	// Act
 	stocks, nextPage, err := client.FetchPage(context.Background(), "")
 
 	// Assert
 	assert.Error(t, err)
-	assert.Contains(t, strings.ToLower(err.Error()), "failed to make request")
+	assert.ErrorIs(t, err, clients.ErrUpstreamUnavailable)
+	assert.Contains(t, err.Error(), "status 500")
 	assert.Empty(t, nextPage)
 	assert.Nil(t, stocks)
 }
@@ -276,11 +298,12 @@ func TestStockAPIClient_ConvertAPIItemToStock_InvalidTime(t *testing.T) {
 	client := clients.NewStockAPIClient(server.URL, "test-api-key", logger)
 
 	// Act
-	_, nextPage, err := client.FetchPage(context.Background(), "")
+	stocks, nextPage, err := client.FetchPage(context.Background(), "")
 
 	// Assert
 	require.NoError(t, err) // FetchPage should not fail
 	assert.Empty(t, nextPage)
+	assert.Empty(t, stocks) // the unconvertible item is skipped rather than returned as nil
 
 	// Verify warning was logged for invalid time format
 	logger.AssertCalled(t, "Warn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
@@ -469,3 +492,240 @@ func BenchmarkStockAPIClient_ConvertAPIItem(b *testing.B) {
 		client.FetchPage(context.Background(), "")
 	}
 }
+
+// fastPolicy retries without noticeable waits and never opens the circuit breaker
+func fastPolicy() clients.RequestPolicy {
+	policy := clients.DefaultRequestPolicy()
+	policy.MinInterval = 0
+	policy.MinBackoff = time.Millisecond
+	policy.MaxBackoff = 10 * time.Millisecond
+	policy.BreakerThreshold = 0
+	return policy
+}
+
+func emptyPage(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clients.StockAPIResponse{Items: []clients.StockAPIItem{}})
+}
+
+func TestStockAPIClient_RetriesAfterRateLimit(t *testing.T) {
+	// Arrange
+	var requestTimes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestTimes = append(requestTimes, time.Now())
+		if len(requestTimes) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		emptyPage(w)
+	}))
+	defer server.Close()
+
+	// A Retry-After longer than MaxBackoff would be given up on instead of waited for
+	policy := fastPolicy()
+	policy.MaxBackoff = 2 * time.Second
+
+	logger := &mocks.MockLogger{}
+	logger.On("Warn", "Retrying upstream request", "attempt", 1, "error", mock.Anything).Once()
+	client := clients.NewStockAPIClientWithPolicy("api", server.URL, "test-api-key", policy, logger)
+
+	// Act
+	_, err := client.FetchRawPage(context.Background(), "")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, requestTimes, 2)
+	// Retry-After takes precedence over the much shorter backoff
+	assert.GreaterOrEqual(t, requestTimes[1].Sub(requestTimes[0]), time.Second)
+	logger.AssertExpectations(t)
+}
+
+func TestStockAPIClient_RateLimitedAfterRetries(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	logger := &mocks.MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client := clients.NewStockAPIClientWithPolicy("api", server.URL, "test-api-key", fastPolicy(), logger)
+
+	// Act
+	_, err := client.FetchRawPage(context.Background(), "")
+
+	// Assert
+	assert.ErrorIs(t, err, clients.ErrRateLimited)
+	var statusErr *clients.StatusError
+	if assert.ErrorAs(t, err, &statusErr) {
+		assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	}
+	assert.Equal(t, 4, requests)
+}
+
+func TestStockAPIClient_UnauthorizedIsNotRetried(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := clients.NewStockAPIClientWithPolicy("api", server.URL, "wrong-key", fastPolicy(), &mocks.MockLogger{})
+
+	// Act
+	_, err := client.FetchRawPage(context.Background(), "")
+
+	// Assert
+	assert.ErrorIs(t, err, clients.ErrUnauthorized)
+	assert.Equal(t, 1, requests)
+}
+
+func TestStockAPIClient_CircuitBreakerOpensAfterRepeated5xx(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	policy := fastPolicy()
+	policy.MaxRetries = 0
+	policy.BreakerThreshold = 2
+	policy.BreakerCooldown = time.Minute
+
+	logger := &mocks.MockLogger{}
+	logger.On("Warn", "Opened circuit breaker for upstream", "source", "api", "cooldown", time.Minute).Once()
+	client := clients.NewStockAPIClientWithPolicy("api", server.URL, "test-api-key", policy, logger)
+	ctx := context.Background()
+
+	// Act
+	for i := 0; i < 2; i++ {
+		_, err := client.FetchRawPage(ctx, "")
+		assert.ErrorIs(t, err, clients.ErrUpstreamUnavailable)
+	}
+	_, err := client.FetchRawPage(ctx, "")
+
+	// Assert
+	assert.ErrorIs(t, err, clients.ErrUpstreamUnavailable)
+	assert.Contains(t, err.Error(), "circuit breaker open")
+	assert.Equal(t, 2, requests, "an open circuit fails fast without calling upstream")
+	logger.AssertExpectations(t)
+}
+
+func TestStockAPIClient_CircuitBreakerResolvesEveryTrial(t *testing.T) {
+	testCases := []struct {
+		name        string
+		trialStatus int32
+		trialCtx    func() (context.Context, context.CancelFunc)
+		reopens     bool
+	}{
+		{name: "rate limited trial closes", trialStatus: http.StatusTooManyRequests},
+		{name: "client error trial closes", trialStatus: http.StatusNotFound},
+		{name: "server error trial reopens", trialStatus: http.StatusServiceUnavailable, reopens: true},
+		{
+			name:        "trial cancelled while paced",
+			trialStatus: http.StatusOK,
+			trialCtx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+		},
+		{
+			name: "trial cancelled before an answer",
+			// A zero status makes the server hold the request until the client gives up
+			trialStatus: 0,
+			trialCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var status, requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				switch code := int(status.Load()); code {
+				case 0:
+					<-r.Context().Done()
+				case http.StatusOK:
+					emptyPage(w)
+				default:
+					w.WriteHeader(code)
+				}
+			}))
+			defer server.Close()
+
+			policy := fastPolicy()
+			policy.MaxRetries = 0
+			policy.BreakerThreshold = 1
+			policy.BreakerCooldown = 20 * time.Millisecond
+
+			logger := &mocks.MockLogger{}
+			logger.On("Warn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			client := clients.NewStockAPIClientWithPolicy("api", server.URL, "test-api-key", policy, logger)
+
+			status.Store(http.StatusBadGateway)
+			_, err := client.FetchRawPage(context.Background(), "")
+			require.ErrorIs(t, err, clients.ErrUpstreamUnavailable)
+			time.Sleep(2 * policy.BreakerCooldown)
+
+			trialCtx, cancel := context.WithCancel(context.Background())
+			if tc.trialCtx != nil {
+				trialCtx, cancel = tc.trialCtx()
+			}
+			defer cancel()
+			status.Store(tc.trialStatus)
+			_, err = client.FetchRawPage(trialCtx, "")
+			require.Error(t, err)
+
+			// Act
+			status.Store(http.StatusOK)
+			before := requests.Load()
+			_, err = client.FetchRawPage(context.Background(), "")
+
+			// Assert
+			if tc.reopens {
+				assert.ErrorIs(t, err, clients.ErrUpstreamUnavailable)
+				assert.Contains(t, err.Error(), "circuit breaker open")
+				assert.Equal(t, before, requests.Load())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, before+1, requests.Load())
+		})
+	}
+}
+
+func TestStockAPIClient_PausesWhenQuotaIsExhausted(t *testing.T) {
+	// Arrange
+	var requestTimes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestTimes = append(requestTimes, time.Now())
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1")
+		emptyPage(w)
+	}))
+	defer server.Close()
+
+	client := clients.NewStockAPIClientWithPolicy("api", server.URL, "test-api-key", fastPolicy(), &mocks.MockLogger{})
+	ctx := context.Background()
+
+	// Act
+	_, err := client.FetchRawPage(ctx, "")
+	require.NoError(t, err)
+	_, err = client.FetchRawPage(ctx, "")
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, requestTimes, 2)
+	assert.GreaterOrEqual(t, requestTimes[1].Sub(requestTimes[0]), 900*time.Millisecond)
+}
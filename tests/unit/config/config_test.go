@@ -0,0 +1,41 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/infrastructure/config"
+)
+
+func TestLoadConfig_VendorNamesMapOntoEnvironmentVariables(t *testing.T) {
+	// Arrange
+	t.Setenv("STOCK_API_VENDORS", "acme-us,zacks.v2")
+	t.Setenv("STOCK_API_ACME_US_URL", "https://acme.example.com")
+	t.Setenv("STOCK_API_ACME_US_KEY", "acme-key")
+	t.Setenv("STOCK_API_ZACKS_V2_URL", "https://zacks.example.com")
+
+	// Act
+	cfg, err := config.LoadConfig()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []config.StockAPIVendor{
+		{Name: "acme-us", URL: "https://acme.example.com", APIKey: "acme-key"},
+		{Name: "zacks.v2", URL: "https://zacks.example.com"},
+	}, cfg.StockAPIVendors)
+}
+
+func TestLoadConfig_RejectsVendorNamesSharingVariables(t *testing.T) {
+	// Arrange
+	t.Setenv("STOCK_API_VENDORS", "acme-us,acme_us")
+	t.Setenv("STOCK_API_ACME_US_URL", "https://acme.example.com")
+
+	// Act
+	_, err := config.LoadConfig()
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "STOCK_API_ACME_US_URL")
+}
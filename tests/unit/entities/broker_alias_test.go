@@ -0,0 +1,39 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+func TestBrokerKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"Goldman Sachs", "goldman sachs"},
+		{"The Goldman Sachs Group, Inc.", "the goldman sachs"},
+		{"JPMorgan Chase & Co.", "jpmorgan chase"},
+		{"J.P. Morgan", "jp morgan"},
+		{"Raymond James Financial Inc", "raymond james financial"},
+		{"  Group  ", "group"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, entities.BrokerKey(tc.name))
+		})
+	}
+}
+
+func TestBrokerSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, entities.BrokerSimilarity("Goldman Sachs", "Goldman Sachs Group, Inc."))
+	assert.Greater(t, entities.BrokerSimilarity("JP Morgan", "JPMorgan Chase & Co."), 0.7)
+	assert.Greater(t, entities.BrokerSimilarity("JP Morgan", "J.P. Morgan"), 0.99)
+	assert.Less(t, entities.BrokerSimilarity("JP Morgan", "Morgan Stanley"), 0.7)
+	assert.Less(t, entities.BrokerSimilarity("Goldman Sachs", "Barclays"), 0.3)
+	assert.Equal(t, 0.0, entities.BrokerSimilarity("", "Barclays"))
+}
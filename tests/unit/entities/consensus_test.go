@@ -0,0 +1,112 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+func consensusEvent(brokerID uuid.UUID, brokerage, action, ratingTo string, targetTo float64, eventTime time.Time) *entities.Stock {
+	stock := entities.NewStock("AAPL", "Apple Inc.", brokerage, action, eventTime)
+	stock.BrokerID, stock.RatingTo, stock.TargetTo = brokerID, ratingTo, targetTo
+	return stock
+}
+
+func TestNewTickerConsensus(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	goldman, barclays, morgan := uuid.New(), uuid.New(), uuid.New()
+
+	events := []*entities.Stock{
+		// Goldman upgraded to Buy, which replaces its older Hold
+		consensusEvent(goldman, "Goldman Sachs", "upgraded by", "Buy", 200, daysAgo(10)),
+		consensusEvent(goldman, "Goldman Sachs", "reiterated by", "Hold", 150, daysAgo(200)),
+		// Barclays only lowered its target since downgrading to Sell
+		consensusEvent(barclays, "Barclays", "target lowered by", "", 110, daysAgo(5)),
+		consensusEvent(barclays, "Barclays", "downgraded by", "Sell", 120, daysAgo(40)),
+		// A broker with no credibility on record still covers the ticker
+		consensusEvent(uuid.Nil, "Boutique Research", "initiated by", "Top Pick", 180, daysAgo(100)),
+		// Morgan has not published in over a year
+		consensusEvent(morgan, "Morgan Stanley", "upgraded by", "Buy", 300, daysAgo(400)),
+	}
+	credibility := map[uuid.UUID]float64{goldman: 0.9, barclays: 0.6, morgan: 0.8}
+
+	// Act
+	consensus := entities.NewTickerConsensus("aapl", events, credibility, "USD", now)
+
+	// Assert
+	assert.Equal(t, "AAPL", consensus.Ticker)
+	assert.Equal(t, 3, consensus.CoveringBrokers)
+	require.Len(t, consensus.Ratings, 3)
+	assert.Equal(t, "Barclays", consensus.Ratings[0].Brokerage)
+	assert.Equal(t, "Sell", consensus.Ratings[0].Rating)
+	assert.Equal(t, 110.0, *consensus.Ratings[0].Target)
+	assert.Equal(t, daysAgo(5), consensus.Ratings[0].UpdatedAt)
+	assert.Equal(t, "Goldman Sachs", consensus.Ratings[1].Brokerage)
+	assert.Equal(t, entities.RatingTierBuy, consensus.Ratings[1].RatingTier)
+	assert.Equal(t, entities.RatingTierStrongBuy, consensus.Ratings[2].RatingTier)
+
+	// (0.8*0.9 + 0.2*0.6) / 1.5; the broker without credibility carries no weight
+	require.NotNil(t, consensus.Score)
+	assert.InDelta(t, 0.56, *consensus.Score, 1e-9)
+	assert.Equal(t, entities.RatingTierHold, consensus.Rating)
+
+	require.NotNil(t, consensus.Targets)
+	assert.Equal(t, 3, consensus.Targets.Count)
+	assert.InDelta(t, 163.33, consensus.Targets.Mean, 0.01)
+	assert.Equal(t, 180.0, consensus.Targets.Median)
+	assert.Equal(t, 200.0, consensus.Targets.High)
+	assert.Equal(t, 110.0, consensus.Targets.Low)
+	assert.InDelta(t, 38.59, consensus.Targets.StdDev, 0.01)
+	assert.InDelta(t, 0.236, consensus.Targets.Dispersion, 0.001)
+
+	assert.Equal(t, []entities.RatingActivity{
+		{Days: 30, Upgrades: 1, Downgrades: 0},
+		{Days: 90, Upgrades: 1, Downgrades: 1},
+		{Days: 365, Upgrades: 1, Downgrades: 1},
+	}, consensus.Activity)
+}
+
+func TestNewTickerConsensus_EvenWeightsWithoutCredibility(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	events := []*entities.Stock{
+		consensusEvent(uuid.New(), "Goldman Sachs", "upgraded by", "Strong Buy", 0, now.Add(-time.Hour)),
+		consensusEvent(uuid.New(), "Barclays", "reiterated by", "Buy", 0, now.Add(-2*time.Hour)),
+	}
+
+	// Act
+	consensus := entities.NewTickerConsensus("AAPL", events, nil, "USD", now)
+
+	// Assert
+	require.NotNil(t, consensus.Score)
+	assert.InDelta(t, 0.9, *consensus.Score, 1e-9)
+	assert.Equal(t, entities.RatingTierStrongBuy, consensus.Rating)
+	assert.Nil(t, consensus.Targets)
+}
+
+func TestNewTickerConsensus_LeavesOutOtherCurrencies(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	inYen := consensusEvent(uuid.New(), "Nomura", "initiated by", "Neutral", 4500, now.Add(-time.Hour))
+	inYen.Currency = "JPY"
+	events := []*entities.Stock{
+		consensusEvent(uuid.New(), "Goldman Sachs", "upgraded by", "Buy", 200, now.Add(-time.Hour)),
+		inYen,
+	}
+
+	// Act
+	consensus := entities.NewTickerConsensus("AAPL", events, nil, "USD", now)
+
+	// Assert
+	assert.Equal(t, 2, consensus.CoveringBrokers)
+	require.NotNil(t, consensus.Targets)
+	assert.Equal(t, 1, consensus.Targets.Count)
+	assert.Equal(t, 200.0, consensus.Targets.Mean)
+}
@@ -0,0 +1,55 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+func TestNormalizeCurrency(t *testing.T) {
+	assert.Equal(t, "EUR", entities.NormalizeCurrency(" eur "))
+	assert.Equal(t, "", entities.NormalizeCurrency("EURO"))
+	assert.Equal(t, "", entities.NormalizeCurrency("E1R"))
+}
+
+func TestFXTable_Convert(t *testing.T) {
+	table := entities.NewFXTable()
+	require.NoError(t, table.Set("USD", "EUR", 0.9))
+	require.NoError(t, table.Set("USD", "GBP", 0.8))
+	assert.Error(t, table.Set("USD", "JPY", 0))
+	assert.Error(t, table.Set("dollars", "EUR", 1))
+
+	t.Run("same currency", func(t *testing.T) {
+		amount, err := table.Convert(100, "EUR", "eur")
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, amount)
+	})
+
+	t.Run("direct rate", func(t *testing.T) {
+		amount, err := table.Convert(100, "USD", "EUR")
+		require.NoError(t, err)
+		assert.InDelta(t, 90, amount, 1e-9)
+	})
+
+	t.Run("inverted rate", func(t *testing.T) {
+		amount, err := table.Convert(90, "EUR", "USD")
+		require.NoError(t, err)
+		assert.InDelta(t, 100, amount, 1e-9)
+	})
+
+	t.Run("cross rate", func(t *testing.T) {
+		amount, err := table.Convert(90, "EUR", "GBP")
+		require.NoError(t, err)
+		assert.InDelta(t, 80, amount, 1e-9)
+	})
+
+	t.Run("unknown currency", func(t *testing.T) {
+		_, err := table.Convert(100, "USD", "CHF")
+		assert.ErrorIs(t, err, entities.ErrNoFXRate)
+		assert.False(t, table.Knows("CHF"))
+		assert.True(t, table.Knows("gbp"))
+	})
+}
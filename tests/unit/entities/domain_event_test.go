@@ -0,0 +1,88 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+func eventTypes(events []*entities.DomainEvent) []entities.DomainEventType {
+	types := make([]entities.DomainEventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func TestIngestedStockEvents(t *testing.T) {
+	eventTime := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("downgrade", func(t *testing.T) {
+		stock := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "downgraded by", eventTime)
+		stock.RatingFrom, stock.RatingTo = "Buy", "Neutral"
+		stock.RatingFromTier, stock.RatingToTier = entities.RatingTierBuy, entities.RatingTierHold
+
+		events := entities.IngestedStockEvents(stock)
+
+		require.Equal(t, []entities.DomainEventType{entities.EventStockEventIngested, entities.EventRatingChanged}, eventTypes(events))
+		assert.Equal(t, stock.ID, events[1].AggregateID)
+
+		var payload entities.RatingChangedPayload
+		require.NoError(t, events[1].Decode(&payload))
+		assert.Equal(t, "downgrade", payload.Direction)
+		assert.Equal(t, "Neutral", payload.RatingTo)
+		assert.Equal(t, eventTime, payload.EventTime)
+	})
+
+	t.Run("rating kept", func(t *testing.T) {
+		stock := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "reiterated by", eventTime)
+		stock.RatingFrom, stock.RatingTo = "Buy", "buy"
+
+		events := entities.IngestedStockEvents(stock)
+
+		assert.Equal(t, []entities.DomainEventType{entities.EventStockEventIngested}, eventTypes(events))
+		var payload entities.StockEventPayload
+		require.NoError(t, events[0].Decode(&payload))
+		assert.Equal(t, "AAPL", payload.Ticker)
+		assert.Equal(t, "USD", payload.Currency)
+	})
+
+	t.Run("coverage initiated", func(t *testing.T) {
+		stock := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "initiated by", eventTime)
+		stock.RatingTo = "Buy"
+
+		assert.Equal(t, []entities.DomainEventType{entities.EventStockEventIngested}, eventTypes(entities.IngestedStockEvents(stock)))
+	})
+
+	t.Run("unmapped ratings", func(t *testing.T) {
+		stock := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "changed by", eventTime)
+		stock.RatingFrom, stock.RatingTo = "Tactical Long", "Tactical Short"
+
+		events := entities.IngestedStockEvents(stock)
+
+		require.Len(t, events, 2)
+		var payload entities.RatingChangedPayload
+		require.NoError(t, events[1].Decode(&payload))
+		assert.Empty(t, payload.Direction)
+	})
+}
+
+func TestSubscriptionActivatedEvent(t *testing.T) {
+	user := &entities.User{Email: "jane@example.com", Tier: entities.TIER_PREMIUM}
+	subscription := entities.NewSubscription(user.ID, entities.PlanYearly)
+	subscription.Activate("sim_payment_1")
+
+	event := entities.SubscriptionActivatedEvent(subscription, user)
+
+	assert.Equal(t, entities.EventSubscriptionActivated, event.Type)
+	assert.Equal(t, subscription.ID, event.AggregateID)
+	var payload entities.SubscriptionPayload
+	require.NoError(t, event.Decode(&payload))
+	assert.Equal(t, entities.TIER_PREMIUM, payload.Tier)
+	assert.Equal(t, "sim_payment_1", payload.PaymentReference)
+	assert.Equal(t, entities.SUB_STATUS_ACTIVE, payload.Status)
+}
@@ -0,0 +1,127 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// ruleNames lists the rules broken, in the order they were checked
+func ruleNames(violations []entities.QualityViolation) []string {
+	names := make([]string, len(violations))
+	for i, v := range violations {
+		names[i] = v.Rule
+	}
+	return names
+}
+
+func TestQualityRuleSet_DefaultRules(t *testing.T) {
+	rules, err := entities.NewQualityRuleSet(entities.DefaultQualityRules())
+	require.NoError(t, err)
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	clean := func() *entities.Stock {
+		stock := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "upgraded by", now.Add(-time.Hour))
+		stock.RatingFrom, stock.RatingTo = "Neutral", "Buy"
+		stock.TargetFrom, stock.TargetTo = 150, 180
+		stock.ActionType = entities.ActionTypeUpgrade
+		return stock
+	}
+
+	t.Run("clean stock", func(t *testing.T) {
+		assert.Empty(t, rules.Check(clean(), now))
+	})
+
+	t.Run("zero target", func(t *testing.T) {
+		stock := clean()
+		stock.TargetTo = 0
+
+		violations := rules.Check(stock, now)
+		assert.Equal(t, []string{"target_to_positive"}, ruleNames(violations))
+		assert.Equal(t, entities.QualitySeverityWarn, entities.WorstSeverity(violations))
+	})
+
+	t.Run("upgrade keeping the rating", func(t *testing.T) {
+		stock := clean()
+		stock.RatingTo = "neutral"
+
+		assert.Equal(t, []string{"upgrade_changes_rating"}, ruleNames(rules.Check(stock, now)))
+	})
+
+	t.Run("reiteration keeping the rating", func(t *testing.T) {
+		stock := clean()
+		stock.RatingTo = "Neutral"
+		stock.ActionType = entities.ActionTypeReiterate
+
+		assert.Empty(t, rules.Check(stock, now))
+	})
+
+	t.Run("future event and long ticker", func(t *testing.T) {
+		stock := clean()
+		stock.Ticker = "ABCDEFGHIJKL"
+		stock.EventTime = now.Add(2 * time.Hour)
+		stock.ActionType = entities.ActionTypeUnknown
+
+		violations := rules.Check(stock, now)
+		assert.Equal(t, []string{"event_time_not_future", "ticker_length", "action_type_known"}, ruleNames(violations))
+		assert.Equal(t, entities.QualitySeverityQuarantine, entities.WorstSeverity(violations))
+		assert.Contains(t, violations[1].Error(), `quality rule ticker_length: ticker "ABCDEFGHIJKL" is longer than 10 characters`)
+	})
+}
+
+func TestParseQualityRules(t *testing.T) {
+	t.Run("valid rules", func(t *testing.T) {
+		rules, err := entities.ParseQualityRules([]byte(`[
+			{"name": "target_cap", "severity": "reject", "field": "target_to", "max": 10000},
+			{"name": "known_broker", "severity": "warn", "field": "brokerage", "one_of": ["Goldman Sachs"], "when": {"source": "api"}}
+		]`))
+		require.NoError(t, err)
+
+		stock := entities.NewStock("AAPL", "Apple Inc.", "Unknown Capital", "target raised by", time.Now())
+		stock.TargetTo = 20000
+		stock.Source = "api"
+
+		violations := rules.Check(stock, time.Now())
+		assert.Equal(t, []string{"target_cap", "known_broker"}, ruleNames(violations))
+		assert.Equal(t, entities.QualitySeverityReject, entities.WorstSeverity(violations))
+	})
+
+	invalid := map[string]string{
+		"unknown severity":     `[{"name": "r", "severity": "panic", "field": "ticker", "max_length": 5}]`,
+		"unknown field":        `[{"name": "r", "severity": "warn", "field": "price", "min": 0}]`,
+		"no constraint":        `[{"name": "r", "severity": "warn", "field": "ticker"}]`,
+		"mismatched field":     `[{"name": "r", "severity": "warn", "field": "ticker", "min": 0}]`,
+		"bad duration":         `[{"name": "r", "severity": "warn", "field": "event_time", "max_ahead": "soon"}]`,
+		"duplicate name":       `[{"name": "r", "severity": "warn", "field": "ticker", "max_length": 5}, {"name": "r", "severity": "warn", "field": "company", "max_length": 5}]`,
+		"unknown condition":    `[{"name": "r", "severity": "warn", "field": "ticker", "max_length": 5, "when": {"price": "1"}}]`,
+		"unknown compare with": `[{"name": "r", "severity": "warn", "field": "rating_to", "not_equal_to": "rating"}]`,
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := entities.ParseQualityRules([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestQualitySummary_Record(t *testing.T) {
+	summary := entities.NewQualitySummary("batch", "api")
+	warn := entities.QualityViolation{Rule: "target_to_positive", Severity: entities.QualitySeverityWarn, Reason: "target_to 0 is below 0.01"}
+	quarantine := entities.QualityViolation{Rule: "ticker_length", Severity: entities.QualitySeverityQuarantine, Reason: "too long"}
+
+	assert.Equal(t, entities.QualitySeverity(""), summary.Record("AAPL", nil))
+	assert.Equal(t, entities.QualitySeverityWarn, summary.Record("MSFT", []entities.QualityViolation{warn}))
+	assert.Equal(t, entities.QualitySeverityQuarantine, summary.Record("ABCDEFGHIJKL", []entities.QualityViolation{warn, quarantine}))
+
+	assert.Equal(t, 3, summary.Checked)
+	assert.Equal(t, 1, summary.Passed)
+	assert.Equal(t, 1, summary.Warned)
+	assert.Equal(t, 1, summary.Quarantined)
+	assert.Equal(t, 2, summary.Rules["target_to_positive"].Violations)
+	assert.Equal(t, "MSFT: target_to 0 is below 0.01", summary.Rules["target_to_positive"].Example)
+	assert.Equal(t, []string{"target_to_positive", "ticker_length"}, summary.BrokenRules())
+}
@@ -0,0 +1,60 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+// profileOf builds a profile of records items where missing lists the records missing each field
+func profileOf(records int, missing map[string]int, unknown map[string]int) *entities.SchemaProfile {
+	profile := entities.NewSchemaProfile("batch", "api")
+	profile.Records = records
+	for field, count := range missing {
+		profile.MissingFields[field] = count
+	}
+	for key, count := range unknown {
+		profile.UnknownKeys[key] = count
+	}
+	return profile
+}
+
+func TestSchemaProfile_CompareTo(t *testing.T) {
+	thresholds := entities.DriftThresholds{UnknownKeyRate: 0, MissingRate: 0.2, ParseFailureRate: 0.05}
+	baseline := profileOf(100, map[string]int{"rating_from": 30}, map[string]int{"currency": 100})
+	for i := 0; i < 100; i++ {
+		baseline.RecordTime(true)
+		baseline.RecordPrice(i != 0)
+	}
+
+	t.Run("within thresholds", func(t *testing.T) {
+		profile := profileOf(100, map[string]int{"rating_from": 45}, map[string]int{"currency": 100})
+		for i := 0; i < 100; i++ {
+			profile.RecordTime(true)
+			profile.RecordPrice(i > 4)
+		}
+
+		assert.Empty(t, profile.CompareTo(baseline, thresholds))
+	})
+
+	t.Run("beyond thresholds", func(t *testing.T) {
+		profile := profileOf(100, map[string]int{"rating_from": 30, "target_to": 60}, map[string]int{"currency": 100, "price_target": 100})
+		for i := 0; i < 100; i++ {
+			profile.RecordTime(i%2 == 0)
+			profile.RecordPrice(true)
+		}
+
+		assert.Equal(t, []entities.SchemaDrift{
+			{Kind: entities.SchemaDriftMissingField, Field: "target_to", Baseline: 0, Current: 0.6},
+			{Kind: entities.SchemaDriftTimeFormat, Baseline: 0, Current: 0.5},
+			{Kind: entities.SchemaDriftUnknownKey, Field: "price_target", Current: 1},
+		}, profile.CompareTo(baseline, thresholds))
+	})
+}
+
+func TestSchemaDrift_String(t *testing.T) {
+	drift := entities.SchemaDrift{Kind: entities.SchemaDriftMissingField, Field: "target_to", Baseline: 0.02, Current: 0.455}
+	assert.Equal(t, "missing_field target_to: 2.0% -> 45.5%", drift.String())
+}
@@ -144,6 +144,8 @@ func TestStock_GetRatingScore(t *testing.T) {
 		name         string
 		ratingFrom   string
 		ratingTo     string
+		fromTier     entities.RatingTier
+		toTier       entities.RatingTier
 		expectedFrom float64
 		expectedTo   float64
 	}{
@@ -159,14 +161,30 @@ func TestStock_GetRatingScore(t *testing.T) {
 			ratingFrom:   "Hold",
 			ratingTo:     "Outperform",
 			expectedFrom: 0.5,
-			expectedTo:   0.75,
+			expectedTo:   0.8, // outperform is in the buy tier
 		},
 		{
 			name:         "Sell to Neutral",
 			ratingFrom:   "Sell",
 			ratingTo:     "Neutral",
 			expectedFrom: 0.2,
-			expectedTo:   0.4,
+			expectedTo:   0.5, // neutral is in the hold tier
+		},
+		{
+			name:         "Broker-specific wording",
+			ratingFrom:   "Sector Perform",
+			ratingTo:     "Overweight",
+			expectedFrom: 0.5,
+			expectedTo:   0.8,
+		},
+		{
+			name:         "Stored tiers win",
+			ratingFrom:   "Unknown",
+			ratingTo:     "Invalid",
+			fromTier:     entities.RatingTierSell,
+			toTier:       entities.RatingTierStrongBuy,
+			expectedFrom: 0.2,
+			expectedTo:   1.0,
 		},
 		{
 			name:         "Case insensitive",
@@ -187,8 +205,10 @@ func TestStock_GetRatingScore(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			stock := &entities.Stock{
-				RatingFrom: tc.ratingFrom,
-				RatingTo:   tc.ratingTo,
+				RatingFrom:     tc.ratingFrom,
+				RatingTo:       tc.ratingTo,
+				RatingFromTier: tc.fromTier,
+				RatingToTier:   tc.toTier,
 			}
 			fromScore, toScore := stock.GetRatingScore()
 			assert.Equal(t, tc.expectedFrom, fromScore)
@@ -378,3 +398,42 @@ func BenchmarkStock_GetRatingScore(b *testing.B) {
 		stock.GetRatingScore()
 	}
 }
+
+func TestStock_HasRevisedFields(t *testing.T) {
+	previous := &entities.Stock{
+		Company:    "Apple Inc.",
+		Action:     "upgraded by",
+		RatingFrom: "Hold",
+		RatingTo:   "Buy",
+		TargetFrom: 150.00,
+		TargetTo:   180.00,
+	}
+
+	unchanged := *previous
+	unchanged.TargetTo = 180.001
+	assert.False(t, unchanged.HasRevisedFields(previous))
+
+	revisedRating := *previous
+	revisedRating.RatingTo = "Strong-Buy"
+	assert.True(t, revisedRating.HasRevisedFields(previous))
+
+	revisedTarget := *previous
+	revisedTarget.TargetTo = 185.00
+	assert.True(t, revisedTarget.HasRevisedFields(previous))
+}
+
+func TestStock_RevisedFields(t *testing.T) {
+	previous := &entities.Stock{
+		Company:  "Apple Inc.",
+		Action:   "upgraded by",
+		RatingTo: "Buy",
+		TargetTo: 180.00,
+	}
+
+	revised := *previous
+	revised.RatingTo = "Strong-Buy"
+	revised.TargetTo = 185.00
+
+	assert.Equal(t, []string{"rating_to", "target_to"}, revised.RevisedFields(previous))
+	assert.Empty(t, previous.RevisedFields(previous))
+}
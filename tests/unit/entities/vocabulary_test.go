@@ -0,0 +1,99 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"stock-tracker/internal/domain/entities"
+)
+
+func TestVocabulary_ActionType(t *testing.T) {
+	vocabulary := entities.DefaultVocabulary()
+
+	testCases := []struct {
+		action   string
+		expected entities.ActionType
+	}{
+		{"upgraded by", entities.ActionTypeUpgrade},
+		{"Downgraded by", entities.ActionTypeDowngrade},
+		{"initiated by", entities.ActionTypeInitiate},
+		{"reiterated by", entities.ActionTypeReiterate},
+		{"target raised by", entities.ActionTypeTargetRaise},
+		{"target lowered by", entities.ActionTypeTargetLower},
+		{"  TARGET   LOWERED  by ", entities.ActionTypeTargetLower},
+		{"target set by", entities.ActionTypeUnknown},
+		{"", entities.ActionTypeUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.action, func(t *testing.T) {
+			assert.Equal(t, tc.expected, vocabulary.ActionType("Goldman Sachs", tc.action))
+		})
+	}
+}
+
+func TestVocabulary_RatingTier(t *testing.T) {
+	vocabulary := entities.DefaultVocabulary()
+
+	testCases := []struct {
+		rating   string
+		expected entities.RatingTier
+	}{
+		{"Strong-Buy", entities.RatingTierStrongBuy},
+		{"Market Outperform", entities.RatingTierBuy},
+		{"Overweight", entities.RatingTierBuy},
+		{"Sector Perform", entities.RatingTierHold},
+		{"In-Line", entities.RatingTierHold},
+		{"Underweight", entities.RatingTierSell},
+		{"Strong Sell", entities.RatingTierStrongSell},
+		{"Speculative", entities.RatingTierUnknown},
+		{"", entities.RatingTierUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rating, func(t *testing.T) {
+			assert.Equal(t, tc.expected, vocabulary.RatingTier("Goldman Sachs", tc.rating))
+		})
+	}
+}
+
+func TestVocabulary_StoredRulesOverrideDefaults(t *testing.T) {
+	vocabulary := entities.NewVocabulary([]*entities.NormalizationRule{
+		entities.NewNormalizationRule(entities.NormalizationKindRating, "", "Speculative", string(entities.RatingTierBuy)),
+		entities.NewNormalizationRule(entities.NormalizationKindRating, "Odd Broker", "Buy", string(entities.RatingTierStrongBuy)),
+		entities.NewNormalizationRule(entities.NormalizationKindAction, "", "target set", string(entities.ActionTypeReiterate)),
+		entities.NewNormalizationRule(entities.NormalizationKindAction, "Odd Broker", "upgraded", string(entities.ActionTypeTargetRaise)),
+	})
+
+	// A rule for every broker extends the built-in vocabulary
+	assert.Equal(t, entities.RatingTierBuy, vocabulary.RatingTier("Goldman Sachs", "speculative"))
+	assert.Equal(t, entities.ActionTypeReiterate, vocabulary.ActionType("Goldman Sachs", "target set by"))
+
+	// A broker's rules only apply to its own events
+	assert.Equal(t, entities.RatingTierStrongBuy, vocabulary.RatingTier("odd broker", "Buy"))
+	assert.Equal(t, entities.RatingTierBuy, vocabulary.RatingTier("Goldman Sachs", "Buy"))
+	assert.Equal(t, entities.ActionTypeTargetRaise, vocabulary.ActionType("Odd Broker", "upgraded by"))
+	assert.Equal(t, entities.ActionTypeUpgrade, vocabulary.ActionType("Goldman Sachs", "upgraded by"))
+}
+
+func TestVocabulary_Normalize(t *testing.T) {
+	stock := &entities.Stock{Brokerage: "Goldman Sachs", Action: "upgraded by", RatingFrom: "Equal Weight", RatingTo: "Overweight"}
+
+	entities.DefaultVocabulary().Normalize(stock)
+
+	assert.Equal(t, entities.ActionTypeUpgrade, stock.ActionType)
+	assert.Equal(t, entities.RatingTierHold, stock.RatingFromTier)
+	assert.Equal(t, entities.RatingTierBuy, stock.RatingToTier)
+}
+
+func TestNormalizationRule_Validate(t *testing.T) {
+	valid := entities.NewNormalizationRule(entities.NormalizationKindAction, "", " Target  Set ", string(entities.ActionTypeReiterate))
+	assert.NoError(t, valid.Validate())
+	assert.Equal(t, "target set", valid.Pattern)
+
+	assert.Error(t, entities.NewNormalizationRule(entities.NormalizationKindRating, "", "Speculative", "maybe").Validate())
+	assert.Error(t, entities.NewNormalizationRule(entities.NormalizationKindAction, "", "target set", string(entities.RatingTierBuy)).Validate())
+	assert.Error(t, entities.NewNormalizationRule(entities.NormalizationKindRating, "", "   ", string(entities.RatingTierBuy)).Validate())
+	assert.Error(t, entities.NewNormalizationRule("sentiment", "", "bullish", string(entities.RatingTierBuy)).Validate())
+}
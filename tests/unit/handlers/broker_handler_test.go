@@ -0,0 +1,139 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/internal/presentation/handlers"
+	"stock-tracker/tests/mocks"
+)
+
+type mockBrokerUseCase struct {
+	mock.Mock
+}
+
+func (m *mockBrokerUseCase) ListAliases(ctx context.Context) ([]*entities.BrokerAlias, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entities.BrokerAlias), args.Error(1)
+}
+
+func (m *mockBrokerUseCase) CreateAlias(ctx context.Context, brokerID uuid.UUID, alias string) (*entities.BrokerAlias, error) {
+	args := m.Called(ctx, brokerID, alias)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BrokerAlias), args.Error(1)
+}
+
+func (m *mockBrokerUseCase) DeleteAlias(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockBrokerUseCase) SuggestDuplicates(ctx context.Context, minSimilarity float64) ([]usecases.BrokerDuplicate, error) {
+	args := m.Called(ctx, minSimilarity)
+	return args.Get(0).([]usecases.BrokerDuplicate), args.Error(1)
+}
+
+func (m *mockBrokerUseCase) MergeBrokers(ctx context.Context, survivorID, mergedID uuid.UUID, reason string) (*entities.BrokerMerge, error) {
+	args := m.Called(ctx, survivorID, mergedID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BrokerMerge), args.Error(1)
+}
+
+func (m *mockBrokerUseCase) ListMerges(ctx context.Context, limit int) ([]*entities.BrokerMerge, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*entities.BrokerMerge), args.Error(1)
+}
+
+func newBrokerRouter(handler *handlers.BrokerHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Route("/admin/brokers", func(r chi.Router) {
+		r.Get("/duplicates", handler.SuggestDuplicates)
+		r.Post("/aliases", handler.CreateAlias)
+		r.Post("/merges", handler.MergeBrokers)
+	})
+	return r
+}
+
+func TestBrokerHandler_SuggestDuplicates_RejectsInvalidSimilarity(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockBrokerUseCase{}
+	handler := handlers.NewBrokerHandler(mockUseCase, &mocks.MockLogger{})
+	req := httptest.NewRequest(http.MethodGet, "/admin/brokers/duplicates?min_similarity=2", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newBrokerRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUseCase.AssertNotCalled(t, "SuggestDuplicates", mock.Anything, mock.Anything)
+}
+
+func TestBrokerHandler_MergeBrokers(t *testing.T) {
+	survivor := entities.NewBroker("JPMorgan Chase & Co.", 0.90)
+	merged := entities.NewBroker("JP Morgan", 0.60)
+
+	testCases := []struct {
+		name           string
+		result         *entities.BrokerMerge
+		err            error
+		expectedStatus int
+	}{
+		{name: "Merged", result: entities.NewBrokerMerge(survivor, merged, "duplicate"), expectedStatus: http.StatusCreated},
+		{name: "Into itself", err: fmt.Errorf("%w: a broker cannot be merged into itself", usecases.ErrBrokerMergeInvalid), expectedStatus: http.StatusBadRequest},
+		{name: "Unknown broker", err: fmt.Errorf("failed to retrieve merged broker: %w", repositories.ErrNotFound), expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mockUseCase := &mockBrokerUseCase{}
+			handler := handlers.NewBrokerHandler(mockUseCase, &mocks.MockLogger{})
+			mockUseCase.On("MergeBrokers", mock.Anything, survivor.ID, merged.ID, "duplicate").Return(tc.result, tc.err)
+
+			body := fmt.Sprintf(`{"survivor_id":%q,"merged_id":%q,"reason":"duplicate"}`, survivor.ID, merged.ID)
+			req := httptest.NewRequest(http.MethodPost, "/admin/brokers/merges", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			newBrokerRouter(handler).ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBrokerHandler_CreateAlias_Duplicate(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockBrokerUseCase{}
+	handler := handlers.NewBrokerHandler(mockUseCase, &mocks.MockLogger{})
+	brokerID := uuid.New()
+	mockUseCase.On("CreateAlias", mock.Anything, brokerID, "JP Morgan").Return(nil, fmt.Errorf("failed to create broker alias: %w", repositories.ErrDuplicate))
+
+	body := fmt.Sprintf(`{"broker_id":%q,"alias":"JP Morgan"}`, brokerID)
+	req := httptest.NewRequest(http.MethodPost, "/admin/brokers/aliases", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	// Act
+	newBrokerRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
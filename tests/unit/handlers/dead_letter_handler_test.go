@@ -0,0 +1,188 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/internal/domain/valueObjects"
+	"stock-tracker/internal/presentation/handlers"
+	"stock-tracker/tests/mocks"
+)
+
+type mockDeadLetterUseCase struct {
+	mock.Mock
+}
+
+func (m *mockDeadLetterUseCase) ListDeadLetters(ctx context.Context, status entities.DeadLetterStatus, limit, offset int) ([]*entities.DeadLetterRecord, *valueObjects.Pagination, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*entities.DeadLetterRecord), args.Get(1).(*valueObjects.Pagination), args.Error(2)
+}
+
+func (m *mockDeadLetterUseCase) GetDeadLetter(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error) {
+	return m.record(m.Called(ctx, id))
+}
+
+func (m *mockDeadLetterUseCase) FixDeadLetter(ctx context.Context, id uuid.UUID, payload json.RawMessage) (*entities.DeadLetterRecord, error) {
+	return m.record(m.Called(ctx, id, payload))
+}
+
+func (m *mockDeadLetterUseCase) ResubmitDeadLetter(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error) {
+	return m.record(m.Called(ctx, id))
+}
+
+func (m *mockDeadLetterUseCase) DiscardDeadLetter(ctx context.Context, id uuid.UUID) (*entities.DeadLetterRecord, error) {
+	return m.record(m.Called(ctx, id))
+}
+
+func (m *mockDeadLetterUseCase) record(args mock.Arguments) (*entities.DeadLetterRecord, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.DeadLetterRecord), args.Error(1)
+}
+
+func newDeadLetterRouter(handler *handlers.DeadLetterHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Route("/admin/dead-letters", func(r chi.Router) {
+		r.Get("/", handler.ListDeadLetters)
+		r.Get("/{id}", handler.GetDeadLetter)
+		r.Put("/{id}", handler.FixDeadLetter)
+		r.Post("/{id}/resubmit", handler.ResubmitDeadLetter)
+		r.Post("/{id}/discard", handler.DiscardDeadLetter)
+	})
+	return r
+}
+
+func TestDeadLetterHandler_ListDeadLetters_FiltersByStatus(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockDeadLetterUseCase{}
+	handler := handlers.NewDeadLetterHandler(mockUseCase, &mocks.MockLogger{})
+
+	record := entities.NewDeadLetterRecord("batch-1", "api", json.RawMessage(`{"ticker":"AAPL"}`), "invalid stock")
+	mockUseCase.On("ListDeadLetters", mock.Anything, entities.DeadLetterStatusPending, 0, 0).
+		Return([]*entities.DeadLetterRecord{record}, &valueObjects.Pagination{Page: 1, Limit: 20, TotalItems: 1, TotalPages: 1}, nil)
+
+	req := httptest.NewRequest("GET", "/admin/dead-letters?status=pending", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newDeadLetterRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"payload":{"ticker":"AAPL"}`)
+	assert.Contains(t, w.Body.String(), `"reason":"invalid stock"`)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestDeadLetterHandler_ListDeadLetters_InvalidStatus(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockDeadLetterUseCase{}
+	handler := handlers.NewDeadLetterHandler(mockUseCase, &mocks.MockLogger{})
+
+	req := httptest.NewRequest("GET", "/admin/dead-letters?status=lost", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newDeadLetterRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUseCase.AssertNotCalled(t, "ListDeadLetters", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeadLetterHandler_FixDeadLetter_PassesPayload(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockDeadLetterUseCase{}
+	handler := handlers.NewDeadLetterHandler(mockUseCase, &mocks.MockLogger{})
+
+	id := uuid.New()
+	body := `{"ticker":"AAPL","time":"2024-01-15T10:30:00Z"}`
+	record := entities.NewDeadLetterRecord("batch-1", "api", json.RawMessage(body), "invalid stock")
+	mockUseCase.On("FixDeadLetter", mock.Anything, id, json.RawMessage(body)).Return(record, nil)
+
+	req := httptest.NewRequest("PUT", "/admin/dead-letters/"+id.String(), strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	// Act
+	newDeadLetterRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestDeadLetterHandler_ResubmitDeadLetter_StillInvalid(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockDeadLetterUseCase{}
+	handler := handlers.NewDeadLetterHandler(mockUseCase, &mocks.MockLogger{})
+
+	id := uuid.New()
+	mockUseCase.On("ResubmitDeadLetter", mock.Anything, id).
+		Return(nil, fmt.Errorf("%w: invalid stock: company is required", usecases.ErrDeadLetterInvalid))
+
+	req := httptest.NewRequest("POST", "/admin/dead-letters/"+id.String()+"/resubmit", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newDeadLetterRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var errorResponse map[string]string
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+	assert.Contains(t, errorResponse["error"], "company is required")
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestDeadLetterHandler_DiscardDeadLetter_AlreadyResolved(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockDeadLetterUseCase{}
+	handler := handlers.NewDeadLetterHandler(mockUseCase, &mocks.MockLogger{})
+
+	id := uuid.New()
+	mockUseCase.On("DiscardDeadLetter", mock.Anything, id).
+		Return(nil, fmt.Errorf("%w: resubmitted", usecases.ErrDeadLetterResolved))
+
+	req := httptest.NewRequest("POST", "/admin/dead-letters/"+id.String()+"/discard", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newDeadLetterRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestDeadLetterHandler_GetDeadLetter_InvalidID(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockDeadLetterUseCase{}
+	handler := handlers.NewDeadLetterHandler(mockUseCase, &mocks.MockLogger{})
+
+	req := httptest.NewRequest("GET", "/admin/dead-letters/not-a-uuid", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newDeadLetterRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
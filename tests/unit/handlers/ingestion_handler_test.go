@@ -0,0 +1,290 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/valueObjects"
+	"stock-tracker/internal/presentation/handlers"
+	"stock-tracker/tests/mocks"
+)
+
+type mockIngestionRunUseCase struct {
+	mock.Mock
+}
+
+func (m *mockIngestionRunUseCase) ListRuns(ctx context.Context, limit, offset int) ([]*entities.IngestionLog, *valueObjects.Pagination, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*entities.IngestionLog), args.Get(1).(*valueObjects.Pagination), args.Error(2)
+}
+
+func (m *mockIngestionRunUseCase) GetRun(ctx context.Context, batchID string) (*entities.IngestionLog, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.IngestionLog), args.Error(1)
+}
+
+func (m *mockIngestionRunUseCase) GetLastSuccessfulRun(ctx context.Context) (*entities.IngestionLog, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.IngestionLog), args.Error(1)
+}
+
+func (m *mockIngestionRunUseCase) ListQualitySummaries(ctx context.Context, limit, offset int) ([]*entities.QualitySummary, *valueObjects.Pagination, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*entities.QualitySummary), args.Get(1).(*valueObjects.Pagination), args.Error(2)
+}
+
+func (m *mockIngestionRunUseCase) GetQualitySummary(ctx context.Context, batchID string) (*entities.QualitySummary, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.QualitySummary), args.Error(1)
+}
+
+func newIngestionRouter(handler *handlers.IngestionHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Route("/admin/ingestion/runs", func(r chi.Router) {
+		r.Get("/", handler.ListRuns)
+		r.Get("/last-successful", handler.GetLastSuccessfulRun)
+		r.Get("/{batchID}", handler.GetRun)
+		r.Get("/{batchID}/quality", handler.GetQualitySummary)
+	})
+	r.Get("/admin/ingestion/quality", handler.ListQualitySummaries)
+	return r
+}
+
+func TestIngestionHandler_ListRuns_Success(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockIngestionRunUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewIngestionHandler(mockUseCase, mockLogger)
+
+	run := entities.NewIngestionLog("batch-1", 0)
+	run.RecordBatch(95, 5)
+	run.Complete()
+
+	mockUseCase.On("ListRuns", mock.Anything, 10, 20).
+		Return([]*entities.IngestionLog{run}, &valueObjects.Pagination{Page: 3, Limit: 10, TotalItems: 21, TotalPages: 3}, nil)
+
+	req := httptest.NewRequest("GET", "/admin/ingestion/runs?limit=10&offset=20", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newIngestionRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data       []entities.IngestionLog  `json:"data"`
+		Pagination *valueObjects.Pagination `json:"pagination"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "batch-1", response.Data[0].BatchID)
+	assert.Equal(t, 95, response.Data[0].SuccessfulRecords)
+	assert.Equal(t, 5, response.Data[0].FailedRecords)
+	assert.Equal(t, 3, response.Pagination.Page)
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestIngestionHandler_GetRun_Success(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockIngestionRunUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewIngestionHandler(mockUseCase, mockLogger)
+
+	run := entities.NewIngestionLog("batch-42", 0)
+	mockUseCase.On("GetRun", mock.Anything, "batch-42").Return(run, nil)
+
+	req := httptest.NewRequest("GET", "/admin/ingestion/runs/batch-42", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newIngestionRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"batch_id":"batch-42"`)
+	assert.Contains(t, w.Body.String(), `"status":"running"`)
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestIngestionHandler_GetRun_NotFound(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockIngestionRunUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewIngestionHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetRun", mock.Anything, "missing").
+		Return(nil, fmt.Errorf("failed to retrieve ingestion run missing: %w", repositories.ErrNotFound))
+
+	req := httptest.NewRequest("GET", "/admin/ingestion/runs/missing", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newIngestionRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var errorResponse map[string]string
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+	assert.Equal(t, "Ingestion run not found", errorResponse["error"])
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestIngestionHandler_GetLastSuccessfulRun(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockIngestionRunUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewIngestionHandler(mockUseCase, mockLogger)
+
+	run := entities.NewIngestionLog("batch-7", 0)
+	run.Complete()
+	mockUseCase.On("GetLastSuccessfulRun", mock.Anything).Return(run, nil)
+
+	req := httptest.NewRequest("GET", "/admin/ingestion/runs/last-successful", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newIngestionRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"completed"`)
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestIngestionHandler_GetLastSuccessfulRun_UseCaseError(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockIngestionRunUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewIngestionHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetLastSuccessfulRun", mock.Anything).Return(nil, errors.New("database connection failed"))
+	mockLogger.On("Error", "Failed to get ingestion run", "error", mock.Anything).Return()
+
+	req := httptest.NewRequest("GET", "/admin/ingestion/runs/last-successful", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newIngestionRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	mockUseCase.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
+
+func TestIngestionHandler_GetQualitySummary_Success(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockIngestionRunUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewIngestionHandler(mockUseCase, mockLogger)
+
+	summary := entities.NewQualitySummary("batch-42", "api")
+	summary.Record("AAPL", []entities.QualityViolation{
+		{Rule: "target_to_positive", Severity: entities.QualitySeverityWarn, Reason: "target_to 0 is below 0.01"},
+	})
+	mockUseCase.On("GetQualitySummary", mock.Anything, "batch-42").Return(summary, nil)
+
+	req := httptest.NewRequest("GET", "/admin/ingestion/runs/batch-42/quality", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newIngestionRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data entities.QualitySummary `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 1, response.Data.Warned)
+	require.Contains(t, response.Data.Rules, "target_to_positive")
+	assert.Equal(t, "AAPL: target_to 0 is below 0.01", response.Data.Rules["target_to_positive"].Example)
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestIngestionHandler_GetQualitySummary_NotFound(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockIngestionRunUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewIngestionHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetQualitySummary", mock.Anything, "missing").
+		Return(nil, fmt.Errorf("failed to retrieve quality summary of missing: %w", repositories.ErrNotFound))
+
+	req := httptest.NewRequest("GET", "/admin/ingestion/runs/missing/quality", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newIngestionRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Quality summary not found")
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestIngestionHandler_ListQualitySummaries(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockIngestionRunUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewIngestionHandler(mockUseCase, mockLogger)
+
+	summaries := []*entities.QualitySummary{entities.NewQualitySummary("batch-2", "api"), entities.NewQualitySummary("batch-1", "api")}
+	mockUseCase.On("ListQualitySummaries", mock.Anything, 0, 0).
+		Return(summaries, &valueObjects.Pagination{Page: 1, Limit: 20, TotalItems: 2, TotalPages: 1}, nil)
+
+	req := httptest.NewRequest("GET", "/admin/ingestion/quality", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newIngestionRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data []entities.QualitySummary `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, "batch-2", response.Data[0].BatchID)
+
+	mockUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,143 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/internal/presentation/handlers"
+	"stock-tracker/tests/mocks"
+)
+
+type mockNormalizationUseCase struct {
+	mock.Mock
+}
+
+func (m *mockNormalizationUseCase) ListRules(ctx context.Context, kind entities.NormalizationKind) ([]*entities.NormalizationRule, error) {
+	args := m.Called(ctx, kind)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.NormalizationRule), args.Error(1)
+}
+
+func (m *mockNormalizationUseCase) CreateRule(ctx context.Context, kind entities.NormalizationKind, brokerage, pattern, value string) (*entities.NormalizationRule, error) {
+	return m.rule(m.Called(ctx, kind, brokerage, pattern, value))
+}
+
+func (m *mockNormalizationUseCase) UpdateRule(ctx context.Context, id uuid.UUID, brokerage, pattern, value string) (*entities.NormalizationRule, error) {
+	return m.rule(m.Called(ctx, id, brokerage, pattern, value))
+}
+
+func (m *mockNormalizationUseCase) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockNormalizationUseCase) ListLabels(ctx context.Context, unmappedOnly bool) ([]repositories.StockLabels, error) {
+	args := m.Called(ctx, unmappedOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repositories.StockLabels), args.Error(1)
+}
+
+func (m *mockNormalizationUseCase) Reapply(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockNormalizationUseCase) rule(args mock.Arguments) (*entities.NormalizationRule, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.NormalizationRule), args.Error(1)
+}
+
+func newNormalizationRouter(handler *handlers.NormalizationHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Route("/admin/normalization", func(r chi.Router) {
+		r.Get("/rules", handler.ListRules)
+		r.Post("/rules", handler.CreateRule)
+		r.Put("/rules/{id}", handler.UpdateRule)
+		r.Delete("/rules/{id}", handler.DeleteRule)
+	})
+	return r
+}
+
+func TestNormalizationHandler_CreateRule(t *testing.T) {
+	rule := entities.NewNormalizationRule(entities.NormalizationKindRating, "", "speculative", "buy")
+
+	testCases := []struct {
+		name           string
+		result         *entities.NormalizationRule
+		err            error
+		expectedStatus int
+	}{
+		{name: "Created", result: rule, expectedStatus: http.StatusCreated},
+		{name: "Invalid", err: fmt.Errorf("%w: unknown rating tier", usecases.ErrNormalizationRuleInvalid), expectedStatus: http.StatusBadRequest},
+		{name: "Duplicate", err: fmt.Errorf("failed to create normalization rule: %w", repositories.ErrDuplicate), expectedStatus: http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mockUseCase := &mockNormalizationUseCase{}
+			handler := handlers.NewNormalizationHandler(mockUseCase, &mocks.MockLogger{})
+			mockUseCase.On("CreateRule", mock.Anything, entities.NormalizationKindRating, "", "Speculative", "buy").Return(tc.result, tc.err)
+
+			body := `{"kind":"rating","pattern":"Speculative","value":"buy"}`
+			req := httptest.NewRequest(http.MethodPost, "/admin/normalization/rules", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			newNormalizationRouter(handler).ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNormalizationHandler_ListRules_RejectsUnknownKind(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockNormalizationUseCase{}
+	handler := handlers.NewNormalizationHandler(mockUseCase, &mocks.MockLogger{})
+	req := httptest.NewRequest(http.MethodGet, "/admin/normalization/rules?kind=sentiment", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newNormalizationRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUseCase.AssertNotCalled(t, "ListRules", mock.Anything, mock.Anything)
+}
+
+func TestNormalizationHandler_DeleteRule_NotFound(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockNormalizationUseCase{}
+	handler := handlers.NewNormalizationHandler(mockUseCase, &mocks.MockLogger{})
+	id := uuid.New()
+	mockUseCase.On("DeleteRule", mock.Anything, id).Return(fmt.Errorf("failed to delete normalization rule: %w", repositories.ErrNotFound))
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/normalization/rules/"+id.String(), nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newNormalizationRouter(handler).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,161 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/internal/infrastructure/middleware"
+	"stock-tracker/internal/presentation/handlers"
+	"stock-tracker/tests/mocks"
+)
+
+type mockStockEditUseCase struct {
+	mock.Mock
+}
+
+func (m *mockStockEditUseCase) CreateStock(ctx context.Context, actor uuid.UUID, input usecases.StockInput) (*entities.Stock, error) {
+	return m.stock(m.Called(ctx, actor, input))
+}
+
+func (m *mockStockEditUseCase) UpdateStock(ctx context.Context, actor, id uuid.UUID, input usecases.StockInput) (*entities.Stock, error) {
+	return m.stock(m.Called(ctx, actor, id, input))
+}
+
+func (m *mockStockEditUseCase) DeleteStock(ctx context.Context, actor, id uuid.UUID) error {
+	return m.Called(ctx, actor, id).Error(0)
+}
+
+func (m *mockStockEditUseCase) ListStockChanges(ctx context.Context, id uuid.UUID) ([]*entities.StockChange, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]*entities.StockChange), args.Error(1)
+}
+
+func (m *mockStockEditUseCase) stock(args mock.Arguments) (*entities.Stock, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Stock), args.Error(1)
+}
+
+// newStockEditRouter serves the handler as the admin actor would reach it, past RequireAdmin
+func newStockEditRouter(handler *handlers.StockEditHandler, actor uuid.UUID) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, actor)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.Route("/stocks/events", func(r chi.Router) {
+		r.Post("/", handler.CreateStock)
+		r.Put("/{id}", handler.UpdateStock)
+		r.Delete("/{id}", handler.DeleteStock)
+	})
+	return r
+}
+
+func TestStockEditHandler_CreateStock(t *testing.T) {
+	actor := uuid.New()
+	eventTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	input := usecases.StockInput{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: eventTime}
+	stock := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "upgraded by", eventTime)
+
+	testCases := []struct {
+		name           string
+		result         *entities.Stock
+		err            error
+		expectedStatus int
+	}{
+		{name: "Created", result: stock, expectedStatus: http.StatusCreated},
+		{name: "Invalid", err: fmt.Errorf("%w: brokerage is required", usecases.ErrStockInvalid), expectedStatus: http.StatusBadRequest},
+		{name: "Duplicate", err: fmt.Errorf("failed to create stock: %w", repositories.ErrDuplicate), expectedStatus: http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mockUseCase := &mockStockEditUseCase{}
+			handler := handlers.NewStockEditHandler(mockUseCase, &mocks.MockLogger{})
+			mockUseCase.On("CreateStock", mock.Anything, actor, input).Return(tc.result, tc.err)
+
+			body := `{"ticker":"AAPL","company":"Apple Inc.","brokerage":"Goldman Sachs","action":"upgraded by","event_time":"2024-01-15T10:30:00Z"}`
+			req := httptest.NewRequest(http.MethodPost, "/stocks/events", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			newStockEditRouter(handler, actor).ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStockEditHandler_UpdateStock_NotFound(t *testing.T) {
+	// Arrange
+	actor, id := uuid.New(), uuid.New()
+	mockUseCase := &mockStockEditUseCase{}
+	handler := handlers.NewStockEditHandler(mockUseCase, &mocks.MockLogger{})
+	mockUseCase.On("UpdateStock", mock.Anything, actor, id, mock.AnythingOfType("usecases.StockInput")).
+		Return(nil, fmt.Errorf("stock %s: %w", id, repositories.ErrNotFound))
+
+	req := httptest.NewRequest(http.MethodPut, "/stocks/events/"+id.String(), strings.NewReader(`{"ticker":"AAPL"}`))
+	w := httptest.NewRecorder()
+
+	// Act
+	newStockEditRouter(handler, actor).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestStockEditHandler_DeleteStock(t *testing.T) {
+	// Arrange
+	actor, id := uuid.New(), uuid.New()
+	mockUseCase := &mockStockEditUseCase{}
+	handler := handlers.NewStockEditHandler(mockUseCase, &mocks.MockLogger{})
+	mockUseCase.On("DeleteStock", mock.Anything, actor, id).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/stocks/events/"+id.String(), nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	newStockEditRouter(handler, actor).ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestStockEditHandler_RequiresActor(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockStockEditUseCase{}
+	handler := handlers.NewStockEditHandler(mockUseCase, &mocks.MockLogger{})
+	req := httptest.NewRequest(http.MethodDelete, "/stocks/events/"+uuid.New().String(), nil)
+	w := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Delete("/stocks/events/{id}", handler.DeleteStock)
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockUseCase.AssertNotCalled(t, "DeleteStock", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -4,16 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
 	"stock-tracker/internal/domain/valueObjects"
 	"stock-tracker/internal/presentation/handlers"
 	"stock-tracker/tests/mocks"
@@ -28,11 +33,27 @@ func (m *mockStockUseCase) GetStocks(ctx context.Context, filters valueObjects.S
 	return args.Get(0), args.Get(1).(*valueObjects.Pagination), args.Error(2)
 }
 
-func (m *mockStockUseCase) GetStocksByTicker(ctx context.Context, ticker string) (interface{}, error) {
-	args := m.Called(ctx, ticker)
+func (m *mockStockUseCase) GetStocksByTicker(ctx context.Context, ticker string, asOf *time.Time, currency string) (interface{}, error) {
+	args := m.Called(ctx, ticker, asOf, currency)
 	return args.Get(0), args.Error(1)
 }
 
+func (m *mockStockUseCase) GetStockByID(ctx context.Context, id uuid.UUID, currency string) (*entities.Stock, error) {
+	args := m.Called(ctx, id, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Stock), args.Error(1)
+}
+
+func (m *mockStockUseCase) GetTickerConsensus(ctx context.Context, ticker string, currency string) (*entities.TickerConsensus, error) {
+	args := m.Called(ctx, ticker, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TickerConsensus), args.Error(1)
+}
+
 func (m *mockStockUseCase) GetStats(ctx context.Context) (interface{}, error) {
 	args := m.Called(ctx)
 	return args.Get(0), args.Error(1)
@@ -182,7 +203,7 @@ func TestStockHandler_GetStockByTicker_Success(t *testing.T) {
 		},
 	}
 
-	mockUseCase.On("GetStocksByTicker", mock.Anything, "AAPL").
+	mockUseCase.On("GetStocksByTicker", mock.Anything, "AAPL", (*time.Time)(nil), "").
 		Return(testStocks, nil)
 
 	// Create router to test URL parameters
@@ -236,7 +257,7 @@ func TestStockHandler_GetStockByTicker_UseCaseError(t *testing.T) {
 	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
 
 	expectedError := errors.New("ticker not found")
-	mockUseCase.On("GetStocksByTicker", mock.Anything, "INVALID").
+	mockUseCase.On("GetStocksByTicker", mock.Anything, "INVALID", (*time.Time)(nil), "").
 		Return(nil, expectedError)
 
 	mockLogger.On("Error", "Failed to get stocks by ticker", "ticker", "INVALID", "error", mock.Anything).Return()
@@ -264,6 +285,84 @@ func TestStockHandler_GetStockByTicker_UseCaseError(t *testing.T) {
 	mockLogger.AssertExpectations(t)
 }
 
+func TestStockHandler_GetStockByTicker_AsOf(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockStockUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+	expectedAsOf := time.Date(2024, 3, 1, 23, 59, 59, 999999999, time.UTC)
+	mockUseCase.On("GetStocksByTicker", mock.Anything, "AAPL", &expectedAsOf, "").
+		Return([]entities.Stock{{Ticker: "AAPL"}}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/stocks/{ticker}", handler.GetStockByTicker)
+
+	req := httptest.NewRequest("GET", "/stocks/AAPL?as_of=2024-03-01", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestStockHandler_GetStockByTicker_UnsupportedCurrency(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockStockUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetStocksByTicker", mock.Anything, "AAPL", (*time.Time)(nil), "XYZ").
+		Return(nil, fmt.Errorf("%w: XYZ", usecases.ErrUnsupportedCurrency))
+
+	r := chi.NewRouter()
+	r.Get("/stocks/{ticker}", handler.GetStockByTicker)
+
+	req := httptest.NewRequest("GET", "/stocks/AAPL?currency=XYZ", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResponse map[string]string
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+	assert.Equal(t, "unsupported display currency: XYZ", errorResponse["error"])
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestStockHandler_InvalidAsOf(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockStockUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+	r := chi.NewRouter()
+	r.Get("/stocks", handler.GetStocks)
+	r.Get("/stocks/{ticker}", handler.GetStockByTicker)
+
+	for _, path := range []string{"/stocks?as_of=yesterday", "/stocks/AAPL?as_of=01-03-2024"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		r.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+
+	mockUseCase.AssertNotCalled(t, "GetStocks", mock.Anything, mock.Anything)
+	mockUseCase.AssertNotCalled(t, "GetStocksByTicker", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestStockHandler_GetStats_Success(t *testing.T) {
 	// Arrange
 	mockUseCase := &mockStockUseCase{}
@@ -353,16 +452,6 @@ func TestStockHandler_ParseFilters(t *testing.T) {
 				Offset:    50,
 			},
 		},
-		{
-			name:  "Invalid limit and offset",
-			query: "limit=invalid&offset=invalid",
-			expected: valueObjects.StockFilters{
-				Limit:     50,           // Default
-				Offset:    0,            // Default (invalid value ignored)
-				SortBy:    "event_time", // Default
-				SortOrder: "desc",       // Default
-			},
-		},
 		{
 			name:  "Empty query",
 			query: "",
@@ -406,59 +495,320 @@ func TestStockHandler_ParseFilters(t *testing.T) {
 	}
 }
 
-func TestStockHandler_NotImplementedMethods(t *testing.T) {
+func TestStockHandler_GetStocks_ParsesNewFilters(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockStockUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetStocks", mock.Anything, mock.MatchedBy(func(filters valueObjects.StockFilters) bool {
+		return filters.Ticker == "AAPL" && filters.TickerMatch == valueObjects.TickerMatchExact &&
+			assert.ObjectsAreEqual([]string{"MSFT", "NVDA", "TSLA"}, filters.Tickers) &&
+			assert.ObjectsAreEqual([]string{"Goldman Sachs", "Barclays"}, filters.Brokerages) &&
+			filters.ActionType == entities.ActionTypeUpgrade &&
+			filters.RatingFrom == "Hold" && filters.RatingTo == "Buy" &&
+			filters.RatingDirection == valueObjects.RatingDirectionUpgrade &&
+			*filters.MinTargetChange == 5 && *filters.MaxTargetChange == 20.5 &&
+			filters.DateFrom.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+			filters.DateTo.Equal(time.Date(2024, 1, 31, 23, 59, 59, 999999999, time.UTC))
+	})).Return([]entities.Stock{}, &valueObjects.Pagination{}, nil)
+
+	query := "ticker=AAPL&ticker_match=exact&tickers=MSFT,NVDA&tickers=TSLA&brokerages=Goldman%20Sachs,%20Barclays" +
+		"&action_type=upgrade&rating_from=Hold&rating_to=Buy&rating_direction=upgrade" +
+		"&min_target_change=5&max_target_change=20.5&date_from=2024-01-01&date_to=2024-01-31"
+	req := httptest.NewRequest("GET", "/stocks?"+query, nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetStocks(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestStockHandler_GetStocks_ParsesSortSpec(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockStockUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetStocks", mock.Anything, mock.MatchedBy(func(filters valueObjects.StockFilters) bool {
+		return assert.ObjectsAreEqual([]valueObjects.SortKey{
+			{Field: valueObjects.SortTargetChange, Descending: true},
+			{Field: valueObjects.SortTicker},
+		}, filters.SortKeys())
+	})).Return([]entities.Stock{}, &valueObjects.Pagination{}, nil)
+
+	req := httptest.NewRequest("GET", "/stocks?sort=-target_change,ticker", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetStocks(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestStockHandler_GetStocks_CursorPagination(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockStockUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+	pagination := &valueObjects.Pagination{
+		Limit:      2,
+		TotalItems: valueObjects.TotalUnknown,
+		TotalPages: valueObjects.TotalUnknown,
+		HasNext:    true,
+		HasPrev:    true,
+		NextCursor: "bmV4dA",
+		PrevCursor: "cHJldg",
+	}
+	mockUseCase.On("GetStocks", mock.Anything, mock.MatchedBy(func(filters valueObjects.StockFilters) bool {
+		return filters.Cursor == "c3RhcnQ" && filters.IncludeTotal && filters.Offset == 0 && filters.Limit == 2
+	})).Return([]entities.Stock{}, pagination, nil)
+
+	req := httptest.NewRequest("GET", "/stocks?ticker=AAPL&limit=2&cursor=c3RhcnQ&include_total=true", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetStocks(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t,
+		`</stocks?cursor=bmV4dA&include_total=true&limit=2&ticker=AAPL>; rel="next", `+
+			`</stocks?cursor=cHJldg&include_total=true&limit=2&ticker=AAPL>; rel="prev"`,
+		w.Header().Get("Link"))
+
+	var response handlers.StockResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.NotNil(t, response.Pagination)
+	assert.Equal(t, "bmV4dA", response.Pagination.NextCursor)
+	assert.Equal(t, "cHJldg", response.Pagination.PrevCursor)
+	assert.Equal(t, valueObjects.TotalUnknown, response.Pagination.TotalItems)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestStockHandler_GetStocks_OffsetPageLinksDropOffset(t *testing.T) {
 	// Arrange
 	mockUseCase := &mockStockUseCase{}
 	mockLogger := &mocks.MockLogger{}
 	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
 
+	pagination := &valueObjects.Pagination{Page: 1, Limit: 20, TotalItems: 25, TotalPages: 2, HasNext: true, NextCursor: "bmV4dA"}
+	mockUseCase.On("GetStocks", mock.Anything, mock.Anything).Return([]entities.Stock{}, pagination, nil)
+
+	req := httptest.NewRequest("GET", "/stocks?offset=0&sort=-event_time", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetStocks(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `</stocks?cursor=bmV4dA&sort=-event_time>; rel="next"`, w.Header().Get("Link"))
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestStockHandler_GetStocks_InvalidCursor(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockStockUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+	mockUseCase.On("GetStocks", mock.Anything, mock.Anything).
+		Return(nil, (*valueObjects.Pagination)(nil), fmt.Errorf("failed to retrieve stocks: %w", repositories.ErrInvalidCursor))
+
+	req := httptest.NewRequest("GET", "/stocks?cursor=garbage", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetStocks(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Get("Link"))
+	var errorResponse map[string]string
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+	assert.Equal(t, "Invalid pagination cursor", errorResponse["error"])
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestStockHandler_GetStocks_InvalidFilters(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		expectedError string
+	}{
+		{name: "Invalid limit", query: "limit=invalid", expectedError: "limit must be an integer"},
+		{name: "Invalid offset", query: "offset=invalid", expectedError: "offset must be an integer"},
+		{name: "Zero limit", query: "limit=0", expectedError: "limit must be greater than 0"},
+		{name: "Negative limit", query: "limit=-3", expectedError: "limit must be greater than 0"},
+		{name: "Limit too large", query: "limit=5000", expectedError: "limit must be at most 1000"},
+		{name: "Negative offset", query: "offset=-1", expectedError: "offset must not be negative"},
+		{name: "Invalid date", query: "date_from=yesterday", expectedError: "date_from must be an RFC 3339 timestamp or a YYYY-MM-DD date"},
+		{name: "Dates out of order", query: "date_from=2024-02-01&date_to=2024-01-01", expectedError: "date_from must be before date_to"},
+		{name: "Invalid target change", query: "min_target_change=lots", expectedError: "min_target_change must be a number"},
+		{name: "Target changes out of order", query: "min_target_change=10&max_target_change=5", expectedError: "min_target_change must not be greater than max_target_change"},
+		{name: "Unknown ticker match", query: "ticker=AAPL&ticker_match=prefix", expectedError: `ticker_match must be "contains" or "exact"`},
+		{name: "Exact match without ticker", query: "ticker_match=exact", expectedError: "ticker_match=exact requires a ticker"},
+		{name: "Unknown action type", query: "action_type=sideways", expectedError: "action_type must be one of [upgrade downgrade initiate reiterate target_raise target_lower]"},
+		{name: "Unknown rating direction", query: "rating_direction=up", expectedError: `rating_direction must be "upgrade", "downgrade" or "unchanged"`},
+		{name: "Unsafe sort_by", query: "sort_by=ticker%3BDROP%20TABLE%20stocks", expectedError: `cannot sort by "ticker;DROP TABLE stocks", sortable fields are event_time, ticker, company, brokerage, action, target_from, target_to, target_change, broker_credibility, created_at`},
+		{name: "Unknown sort_order", query: "sort_order=sideways", expectedError: `sort_order must be "asc" or "desc"`},
+		{name: "Invalid include_total", query: "cursor=abc&include_total=maybe", expectedError: "include_total must be true or false"},
+		{name: "Offset with cursor", query: "cursor=abc&offset=20", expectedError: "offset cannot be combined with cursor"},
+		{name: "Unknown sort field", query: "sort=-price", expectedError: `cannot sort by "price", sortable fields are event_time, ticker, company, brokerage, action, target_from, target_to, target_change, broker_credibility, created_at`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mockUseCase := &mockStockUseCase{}
+			mockLogger := &mocks.MockLogger{}
+			handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+			req := httptest.NewRequest("GET", "/stocks?"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetStocks(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var errorResponse map[string]string
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+			assert.Equal(t, tc.expectedError, errorResponse["error"])
+			mockUseCase.AssertNotCalled(t, "GetStocks", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestStockHandler_GetStockByID(t *testing.T) {
+	stock := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "upgraded by", time.Now())
+
 	testCases := []struct {
-		name    string
-		method  string
-		path    string
-		handler func(w http.ResponseWriter, r *http.Request)
+		name           string
+		path           string
+		stock          *entities.Stock
+		err            error
+		expectedStatus int
+		expectedError  string
 	}{
 		{
-			name:    "GetStockByID",
-			method:  "GET",
-			path:    "/stocks/123",
-			handler: handler.GetStockByID,
+			name:           "found",
+			path:           "/stocks/events/" + stock.ID.String(),
+			stock:          stock,
+			expectedStatus: http.StatusOK,
 		},
 		{
-			name:    "CreateStock",
-			method:  "POST",
-			path:    "/stocks",
-			handler: handler.CreateStock,
+			name:           "not found",
+			path:           "/stocks/events/" + stock.ID.String(),
+			err:            fmt.Errorf("failed to retrieve stock: %w", repositories.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Stock not found",
 		},
 		{
-			name:    "UpdateStock",
-			method:  "PUT",
-			path:    "/stocks/123",
-			handler: handler.UpdateStock,
+			name:           "invalid ID",
+			path:           "/stocks/events/123",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid stock ID",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mockUseCase := &mockStockUseCase{}
+			mockLogger := &mocks.MockLogger{}
+			handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+			r := chi.NewRouter()
+			r.Get("/stocks/events/{id}", handler.GetStockByID)
+
+			if tc.stock != nil || tc.err != nil {
+				mockUseCase.On("GetStockByID", mock.Anything, stock.ID, "").Return(tc.stock, tc.err)
+			}
+
+			req := httptest.NewRequest("GET", tc.path, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedError != "" {
+				var errorResponse map[string]string
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+				assert.Equal(t, tc.expectedError, errorResponse["error"])
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStockHandler_GetTickerConsensus(t *testing.T) {
+	consensus := &entities.TickerConsensus{Ticker: "AAPL", Rating: entities.RatingTierBuy, CoveringBrokers: 2, Currency: "EUR"}
+
+	testCases := []struct {
+		name           string
+		consensus      *entities.TickerConsensus
+		err            error
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "found", consensus: consensus, expectedStatus: http.StatusOK},
+		{
+			name:           "unknown ticker",
+			err:            fmt.Errorf("ticker AAPL: %w", repositories.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "No stocks found for ticker",
 		},
 		{
-			name:    "DeleteStock",
-			method:  "DELETE",
-			path:    "/stocks/123",
-			handler: handler.DeleteStock,
+			name:           "unsupported currency",
+			err:            fmt.Errorf("%w: EUR", usecases.ErrUnsupportedCurrency),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "unsupported display currency: EUR",
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			req := httptest.NewRequest(tc.method, tc.path, nil)
+			// Arrange
+			mockUseCase := &mockStockUseCase{}
+			mockLogger := &mocks.MockLogger{}
+			handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+			r := chi.NewRouter()
+			r.Get("/tickers/{ticker}", handler.GetStockByTicker)
+			r.Get("/tickers/{ticker}/consensus", handler.GetTickerConsensus)
+
+			mockUseCase.On("GetTickerConsensus", mock.Anything, "AAPL", "EUR").Return(tc.consensus, tc.err)
+
+			req := httptest.NewRequest("GET", "/tickers/AAPL/consensus?currency=EUR", nil)
 			w := httptest.NewRecorder()
 
 			// Act
-			tc.handler(w, req)
+			r.ServeHTTP(w, req)
 
 			// Assert
-			assert.Equal(t, http.StatusNotImplemented, w.Code)
-
-			var errorResponse map[string]string
-			err := json.NewDecoder(w.Body).Decode(&errorResponse)
-			require.NoError(t, err)
-			assert.Equal(t, "Not implemented", errorResponse["error"])
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedError != "" {
+				var errorResponse map[string]string
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&errorResponse))
+				assert.Equal(t, tc.expectedError, errorResponse["error"])
+			} else {
+				var response struct {
+					Data entities.TickerConsensus `json:"data"`
+				}
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, *consensus, response.Data)
+			}
+			mockUseCase.AssertExpectations(t)
 		})
 	}
 }
@@ -471,10 +821,11 @@ func TestStockHandler_Integration(t *testing.T) {
 	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
 
 	r := chi.NewRouter()
-	r.Route("/api/v1/stocks", func(r chi.Router) {
-		r.Get("/", handler.GetStocks)
-		r.Get("/{ticker}", handler.GetStockByTicker)
-		r.Get("/stats", handler.GetStats)
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/stocks", handler.GetStocks)
+		r.Get("/stocks/stats", handler.GetStats)
+		r.Get("/stocks/events/{id}", handler.GetStockByID)
+		r.Get("/tickers/{ticker}", handler.GetStockByTicker)
 	})
 
 	testStocks := []entities.Stock{
@@ -501,3 +852,29 @@ func TestStockHandler_Integration(t *testing.T) {
 
 	mockUseCase.AssertExpectations(t)
 }
+
+func TestStockHandler_Integration_StatsIsNotShadowed(t *testing.T) {
+	// Arrange
+	mockUseCase := &mockStockUseCase{}
+	mockLogger := &mocks.MockLogger{}
+	handler := handlers.NewStockHandler(mockUseCase, mockLogger)
+
+	r := chi.NewRouter()
+	r.Get("/stocks/stats", handler.GetStats)
+	r.Get("/stocks/events/{id}", handler.GetStockByID)
+	r.Get("/tickers/{ticker}", handler.GetStockByTicker)
+
+	mockUseCase.On("GetStats", mock.Anything).Return(map[string]interface{}{"total_stocks": 1}, nil)
+	mockUseCase.On("GetStocksByTicker", mock.Anything, "STATS", (*time.Time)(nil), "").Return([]entities.Stock{}, nil)
+
+	// Act
+	statsW := httptest.NewRecorder()
+	r.ServeHTTP(statsW, httptest.NewRequest("GET", "/stocks/stats", nil))
+	tickerW := httptest.NewRecorder()
+	r.ServeHTTP(tickerW, httptest.NewRequest("GET", "/tickers/STATS", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, statsW.Code)
+	assert.Equal(t, http.StatusOK, tickerW.Code)
+	mockUseCase.AssertExpectations(t)
+}
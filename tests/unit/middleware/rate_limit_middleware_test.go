@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/infrastructure/middleware"
+	"stock-tracker/tests/mocks"
+)
+
+func rateLimitedRequest(handler http.Handler, userID uuid.UUID, tier entities.UserTier) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stocks", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+	ctx = context.WithValue(ctx, middleware.UserTierContextKey, tier)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req.WithContext(ctx))
+	return w.Code
+}
+
+func TestRateLimiter_Forget_StartsAFreshLimiter(t *testing.T) {
+	// Arrange
+	logger := &mocks.MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	rateLimiter := middleware.NewRateLimiter(logger)
+	handler := rateLimiter.RateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	userID := uuid.New()
+	for i := 0; i < 10; i++ {
+		rateLimitedRequest(handler, userID, entities.TIER_BASIC)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, userID, entities.TIER_PREMIUM))
+
+	// Act
+	rateLimiter.Forget(userID.String())
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, userID, entities.TIER_PREMIUM))
+}
@@ -0,0 +1,114 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/tests/mocks"
+)
+
+func newBrokerAdminUseCase() (usecases.BrokerUseCase, *mocks.MockBrokerRepository) {
+	brokerRepo := &mocks.MockBrokerRepository{}
+	logger := &mocks.MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	return usecases.NewBrokerAdminUseCase(brokerRepo, logger), brokerRepo
+}
+
+func TestBrokerAdmin_SuggestDuplicates(t *testing.T) {
+	// Arrange
+	useCase, brokerRepo := newBrokerAdminUseCase()
+	jpMorgan := entities.NewBroker("JPMorgan Chase & Co.", 0.90)
+	jpMorganShort := entities.NewBroker("JP Morgan", 0.60)
+	brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{
+		entities.NewBroker("Goldman Sachs", 0.95), jpMorgan, jpMorganShort, entities.NewBroker("Morgan Stanley", 0.90),
+	}, nil)
+
+	// Act
+	duplicates, err := useCase.SuggestDuplicates(context.Background(), 0)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, jpMorgan, duplicates[0].Broker)
+	assert.Equal(t, jpMorganShort, duplicates[0].Duplicate)
+}
+
+func TestBrokerAdmin_MergeBrokers(t *testing.T) {
+	// Arrange
+	useCase, brokerRepo := newBrokerAdminUseCase()
+	survivor := entities.NewBroker("JPMorgan Chase & Co.", 0.90)
+	merged := entities.NewBroker("JP Morgan", 0.60)
+	brokerRepo.On("GetByID", mock.Anything, survivor.ID).Return(survivor, nil)
+	brokerRepo.On("GetByID", mock.Anything, merged.ID).Return(merged, nil)
+	brokerRepo.On("Merge", mock.Anything, mock.MatchedBy(func(merge *entities.BrokerMerge) bool {
+		return merge.SurvivorID == survivor.ID && merge.MergedID == merged.ID &&
+			merge.MergedName == "JP Morgan" && merge.MergedCredibilityScore == 0.60
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.BrokerMerge).StocksMoved = 12
+	}).Return(nil)
+
+	// Act
+	merge, err := useCase.MergeBrokers(context.Background(), survivor.ID, merged.ID, "same broker")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 12, merge.StocksMoved)
+	assert.Equal(t, "same broker", merge.Reason)
+	brokerRepo.AssertExpectations(t)
+}
+
+func TestBrokerAdmin_MergeBrokers_IntoItself(t *testing.T) {
+	// Arrange
+	useCase, brokerRepo := newBrokerAdminUseCase()
+	broker := entities.NewBroker("JP Morgan", 0.60)
+
+	// Act
+	_, err := useCase.MergeBrokers(context.Background(), broker.ID, broker.ID, "")
+
+	// Assert
+	assert.ErrorIs(t, err, usecases.ErrBrokerMergeInvalid)
+	brokerRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything)
+}
+
+func TestBrokerAdmin_CreateAlias_RejectsNameOfAnotherBroker(t *testing.T) {
+	// Arrange
+	useCase, brokerRepo := newBrokerAdminUseCase()
+	jpMorgan := entities.NewBroker("JPMorgan Chase & Co.", 0.90)
+	jpMorganShort := entities.NewBroker("JP Morgan", 0.60)
+	brokerRepo.On("GetByID", mock.Anything, jpMorgan.ID).Return(jpMorgan, nil)
+	brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{jpMorgan, jpMorganShort}, nil)
+
+	// Act
+	_, err := useCase.CreateAlias(context.Background(), jpMorgan.ID, "J.P. Morgan")
+
+	// Assert
+	assert.ErrorIs(t, err, usecases.ErrBrokerAliasInvalid)
+	assert.Contains(t, err.Error(), "merge the brokers instead")
+	brokerRepo.AssertNotCalled(t, "CreateAlias", mock.Anything, mock.Anything)
+}
+
+func TestBrokerAdmin_CreateAlias(t *testing.T) {
+	// Arrange
+	useCase, brokerRepo := newBrokerAdminUseCase()
+	jpMorgan := entities.NewBroker("JPMorgan Chase & Co.", 0.90)
+	brokerRepo.On("GetByID", mock.Anything, jpMorgan.ID).Return(jpMorgan, nil)
+	brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{jpMorgan}, nil)
+	brokerRepo.On("CreateAlias", mock.Anything, mock.MatchedBy(func(alias *entities.BrokerAlias) bool {
+		return alias.BrokerID == jpMorgan.ID && alias.Alias == "JP Morgan"
+	})).Return(nil)
+
+	// Act
+	alias, err := useCase.CreateAlias(context.Background(), jpMorgan.ID, " JP Morgan ")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "JP Morgan", alias.Alias)
+	brokerRepo.AssertExpectations(t)
+}
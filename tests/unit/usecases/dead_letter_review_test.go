@@ -0,0 +1,266 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/tests/mocks"
+)
+
+func newDeadLetterReviewUseCase() (usecases.DeadLetterUseCase, *mocks.MockDeadLetterRepository, *mocks.MockStockRepository, *mocks.MockBrokerRepository, *mocks.MockLogger) {
+	return newDeadLetterReviewUseCaseWithRules(nil)
+}
+
+func newDeadLetterReviewUseCaseWithRules(rules *entities.QualityRuleSet) (usecases.DeadLetterUseCase, *mocks.MockDeadLetterRepository, *mocks.MockStockRepository, *mocks.MockBrokerRepository, *mocks.MockLogger) {
+	deadLetterRepo := &mocks.MockDeadLetterRepository{}
+	stockRepo := &mocks.MockStockRepository{}
+	brokerRepo := &mocks.MockBrokerRepository{}
+	normalizationRepo := &mocks.MockNormalizationRepository{}
+	normalizationRepo.On("ListRules", mock.Anything, entities.NormalizationKind("")).Return([]*entities.NormalizationRule{}, nil).Maybe()
+	logger := &mocks.MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	return usecases.NewDeadLetterReviewUseCase(deadLetterRepo, stockRepo, brokerRepo, normalizationRepo, rules, logger), deadLetterRepo, stockRepo, brokerRepo, logger
+}
+
+func TestDeadLetterReview_Resubmit_FixedRecordIsStored(t *testing.T) {
+	// Arrange
+	useCase, deadLetterRepo, stockRepo, brokerRepo, _ := newDeadLetterReviewUseCase()
+	record := entities.NewDeadLetterRecord("batch-1", "api", json.RawMessage(`{"ticker":"AAPL","time":"bad"}`), "failed to parse event time")
+	broker := entities.NewBroker("Goldman Sachs", 0.95)
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+	deadLetterRepo.On("Update", mock.Anything, record).Return(nil)
+	brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{broker}, nil)
+	brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{}, nil)
+	stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Ticker == "AAPL" && stocks[0].BrokerID == broker.ID &&
+			stocks[0].ActionType == entities.ActionTypeUpgrade && stocks[0].Source == "api"
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	fixed := json.RawMessage(`{"ticker":"AAPL","company":"Apple Inc.","brokerage":"Goldman Sachs","action":"upgraded by","time":"2024-01-15T10:30:00Z"}`)
+
+	// Act
+	_, err := useCase.FixDeadLetter(context.Background(), record.ID, fixed)
+	require.NoError(t, err)
+	resolved, err := useCase.ResubmitDeadLetter(context.Background(), record.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entities.DeadLetterStatusResubmitted, resolved.Status)
+	assert.Equal(t, 1, resolved.Attempts)
+	assert.NotNil(t, resolved.ResolvedAt)
+	deadLetterRepo.AssertExpectations(t)
+	stockRepo.AssertExpectations(t)
+	brokerRepo.AssertExpectations(t)
+}
+
+func TestDeadLetterReview_Resubmit_CreatesUnknownBroker(t *testing.T) {
+	// Arrange
+	useCase, deadLetterRepo, stockRepo, brokerRepo, _ := newDeadLetterReviewUseCase()
+	record := entities.NewDeadLetterRecord("batch-1", "api",
+		json.RawMessage(`{"ticker":"AAPL","company":"Apple Inc.","brokerage":"New Brokerage","action":"upgraded by","time":"2024-01-15T10:30:00Z"}`),
+		"failed to create stock")
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+	deadLetterRepo.On("Update", mock.Anything, record).Return(nil)
+	brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{}, nil)
+	brokerRepo.On("Create", mock.Anything, mock.MatchedBy(func(broker *entities.Broker) bool {
+		return broker.Name == "New Brokerage" && broker.CredibilityScore == 0.60
+	})).Return(nil)
+	stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{}, nil)
+
+	// Act
+	_, err := useCase.ResubmitDeadLetter(context.Background(), record.ID)
+
+	// Assert
+	require.NoError(t, err)
+	brokerRepo.AssertExpectations(t)
+}
+
+func TestDeadLetterReview_Resubmit_ResolvesBrokerAlias(t *testing.T) {
+	// Arrange
+	useCase, deadLetterRepo, stockRepo, brokerRepo, _ := newDeadLetterReviewUseCase()
+	record := entities.NewDeadLetterRecord("batch-1", "api",
+		json.RawMessage(`{"ticker":"AAPL","company":"Apple Inc.","brokerage":"JP Morgan","action":"upgraded by","time":"2024-01-15T10:30:00Z"}`),
+		"failed to create stock")
+	broker := entities.NewBroker("JPMorgan Chase & Co.", 0.90)
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+	deadLetterRepo.On("Update", mock.Anything, record).Return(nil)
+	brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{broker}, nil)
+	brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{entities.NewBrokerAlias(broker.ID, "J.P. Morgan")}, nil)
+	stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].BrokerID == broker.ID
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	_, err := useCase.ResubmitDeadLetter(context.Background(), record.ID)
+
+	// Assert
+	require.NoError(t, err)
+	brokerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	stockRepo.AssertExpectations(t)
+}
+
+func TestDeadLetterReview_Resubmit_StoreRejected(t *testing.T) {
+	// Arrange
+	useCase, deadLetterRepo, stockRepo, brokerRepo, logger := newDeadLetterReviewUseCase()
+	record := entities.NewDeadLetterRecord("batch-1", "api",
+		json.RawMessage(`{"ticker":"AAPL","company":"Apple Inc.","brokerage":"Goldman Sachs","action":"upgraded by","time":"2024-01-15T10:30:00Z"}`),
+		"failed to create stock")
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+	brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{}, nil)
+	stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{
+		Failed:   1,
+		Failures: []repositories.UpsertFailure{{Err: errors.New("value too long")}},
+	}, nil)
+	logger.On("Error", "Failed to store re-submitted stock", "id", record.ID, "error", mock.Anything).Return()
+
+	// Act
+	_, err := useCase.ResubmitDeadLetter(context.Background(), record.ID)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "value too long")
+	assert.True(t, record.IsPending())
+	deadLetterRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDeadLetterReview_Resubmit_StillInvalid(t *testing.T) {
+	// Arrange
+	useCase, deadLetterRepo, stockRepo, _, _ := newDeadLetterReviewUseCase()
+	record := entities.NewDeadLetterRecord("batch-1", "api",
+		json.RawMessage(`{"ticker":"AAPL","brokerage":"Goldman Sachs","action":"upgraded by","time":"2024-01-15T10:30:00Z"}`),
+		"invalid stock")
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+	deadLetterRepo.On("Update", mock.Anything, record).Return(nil)
+
+	// Act
+	result, err := useCase.ResubmitDeadLetter(context.Background(), record.ID)
+
+	// Assert
+	require.Error(t, err)
+	assert.ErrorIs(t, err, usecases.ErrDeadLetterInvalid)
+	assert.True(t, result.IsPending())
+	assert.Equal(t, 1, result.Attempts)
+	assert.Contains(t, result.Reason, "Company")
+	stockRepo.AssertNotCalled(t, "BulkUpsert", mock.Anything, mock.Anything)
+}
+
+func TestDeadLetterReview_Resubmit_KeepsDeadLetterSourceWhenUnrecorded(t *testing.T) {
+	// Arrange
+	useCase, deadLetterRepo, stockRepo, brokerRepo, _ := newDeadLetterReviewUseCase()
+	record := entities.NewDeadLetterRecord("batch-1", "",
+		json.RawMessage(`{"ticker":"AAPL","company":"Apple Inc.","brokerage":"Goldman Sachs","action":"upgraded by","time":"2024-01-15T10:30:00Z"}`),
+		"failed to create stock")
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+	deadLetterRepo.On("Update", mock.Anything, record).Return(nil)
+	brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{}, nil)
+	stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Source == "dead_letter"
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	_, err := useCase.ResubmitDeadLetter(context.Background(), record.ID)
+
+	// Assert
+	require.NoError(t, err)
+	stockRepo.AssertExpectations(t)
+}
+
+func TestDeadLetterReview_Resubmit_BreaksQualityRule(t *testing.T) {
+	// Arrange
+	rules, err := entities.ParseQualityRules([]byte(`[
+		{"name": "target_to_positive", "severity": "warn", "field": "target_to", "min": 0.01},
+		{"name": "brokerage_known", "severity": "quarantine", "field": "brokerage", "one_of": ["Goldman Sachs"]}
+	]`))
+	require.NoError(t, err)
+	useCase, deadLetterRepo, stockRepo, _, logger := newDeadLetterReviewUseCaseWithRules(rules)
+	record := entities.NewDeadLetterRecord("batch-1", "api",
+		json.RawMessage(`{"ticker":"AAPL","company":"Apple Inc.","brokerage":"Shady Research","action":"upgraded by","time":"2024-01-15T10:30:00Z"}`),
+		"quality rule brokerage_known: brokerage is not one of the allowed values")
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+	deadLetterRepo.On("Update", mock.Anything, record).Return(nil)
+	logger.On("Warn", "Re-submitted stock broke a data-quality rule", "id", record.ID, "rule", "target_to_positive", "reason", mock.Anything).Return()
+
+	// Act
+	result, err := useCase.ResubmitDeadLetter(context.Background(), record.ID)
+
+	// Assert
+	assert.ErrorIs(t, err, usecases.ErrDeadLetterInvalid)
+	assert.True(t, result.IsPending())
+	assert.Equal(t, 1, result.Attempts)
+	assert.Contains(t, result.Reason, "brokerage_known")
+	stockRepo.AssertNotCalled(t, "BulkUpsert", mock.Anything, mock.Anything)
+	logger.AssertExpectations(t)
+}
+
+func TestDeadLetterReview_Resubmit_ResolvedConcurrently(t *testing.T) {
+	// Arrange
+	useCase, deadLetterRepo, stockRepo, brokerRepo, logger := newDeadLetterReviewUseCase()
+	record := entities.NewDeadLetterRecord("batch-1", "api",
+		json.RawMessage(`{"ticker":"AAPL","company":"Apple Inc.","brokerage":"Goldman Sachs","action":"upgraded by","time":"2024-01-15T10:30:00Z"}`),
+		"failed to create stock")
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+	deadLetterRepo.On("Update", mock.Anything, record).Return(fmt.Errorf("dead letter record %s: %w", record.ID, repositories.ErrNotFound))
+	brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{}, nil)
+	stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{Duplicates: 1}, nil)
+	logger.On("Error", mock.Anything, "id", record.ID, "error", mock.Anything).Return()
+
+	// Act
+	_, err := useCase.ResubmitDeadLetter(context.Background(), record.ID)
+
+	// Assert
+	assert.ErrorIs(t, err, usecases.ErrDeadLetterResolved)
+}
+
+func TestDeadLetterReview_Discard_AlreadyResolved(t *testing.T) {
+	// Arrange
+	useCase, deadLetterRepo, _, _, _ := newDeadLetterReviewUseCase()
+	record := entities.NewDeadLetterRecord("batch-1", "api", json.RawMessage(`{}`), "invalid stock")
+	record.Discard()
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+
+	// Act
+	_, err := useCase.DiscardDeadLetter(context.Background(), record.ID)
+
+	// Assert
+	assert.ErrorIs(t, err, usecases.ErrDeadLetterResolved)
+	deadLetterRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDeadLetterReview_Fix_RejectsNonItemPayload(t *testing.T) {
+	// Arrange
+	useCase, deadLetterRepo, _, _, _ := newDeadLetterReviewUseCase()
+	record := entities.NewDeadLetterRecord("batch-1", "api", json.RawMessage(`{}`), "invalid stock")
+
+	deadLetterRepo.On("GetByID", mock.Anything, record.ID).Return(record, nil)
+
+	// Act
+	_, err := useCase.FixDeadLetter(context.Background(), record.ID, json.RawMessage(`["not","an","item"]`))
+
+	// Assert
+	assert.ErrorIs(t, err, usecases.ErrDeadLetterInvalid)
+	deadLetterRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
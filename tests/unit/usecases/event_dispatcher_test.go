@@ -0,0 +1,129 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/tests/mocks"
+)
+
+// outboxEvent wraps the event of registering a user as a claimed outbox event
+func outboxEvent(attempts int) *entities.OutboxEvent {
+	user := &entities.User{Email: "jane@example.com", Tier: entities.TIER_BASIC}
+	return &entities.OutboxEvent{
+		DomainEvent: *entities.UserRegisteredEvent(user),
+		Status:      entities.OutboxStatusPending,
+		Attempts:    attempts,
+	}
+}
+
+func anyArgs(n int) []interface{} {
+	args := make([]interface{}, n)
+	for i := range args {
+		args[i] = mock.Anything
+	}
+	return args
+}
+
+func newEventDispatcher(maxAttempts int) (*usecases.EventDispatcher, *mocks.MockOutboxRepository, *mocks.MockLogger) {
+	outboxRepo := &mocks.MockOutboxRepository{}
+	logger := &mocks.MockLogger{}
+	return usecases.NewEventDispatcher(outboxRepo, maxAttempts, logger), outboxRepo, logger
+}
+
+func TestEventDispatcher_DeliversToSubscribers(t *testing.T) {
+	// Arrange
+	dispatcher, outboxRepo, _ := newEventDispatcher(3)
+	event := outboxEvent(1)
+	outboxRepo.On("Claim", mock.Anything, 100, time.Minute).Return([]*entities.OutboxEvent{event}, nil)
+	outboxRepo.On("MarkDelivered", mock.Anything, event.ID).Return(nil)
+
+	var registered []string
+	dispatcher.Subscribe("welcome-mail", func(ctx context.Context, event *entities.DomainEvent) error {
+		var payload entities.UserRegisteredPayload
+		require.NoError(t, event.Decode(&payload))
+		registered = append(registered, payload.Email)
+		return nil
+	}, entities.EventUserRegistered)
+	dispatcher.Subscribe("rating-alerts", func(ctx context.Context, event *entities.DomainEvent) error {
+		t.Fatalf("rating-alerts received %s", event.Type)
+		return nil
+	}, entities.EventRatingChanged)
+
+	// Act
+	claimed, err := dispatcher.DispatchPending(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, []string{"jane@example.com"}, registered)
+	outboxRepo.AssertExpectations(t)
+}
+
+func TestEventDispatcher_RetriesFailedDelivery(t *testing.T) {
+	// Arrange
+	dispatcher, outboxRepo, logger := newEventDispatcher(3)
+	event := outboxEvent(2)
+	outboxRepo.On("Claim", mock.Anything, 100, time.Minute).Return([]*entities.OutboxEvent{event}, nil)
+	outboxRepo.On("Reschedule", mock.Anything, event.ID, "crm: crm unavailable", mock.MatchedBy(func(at time.Time) bool {
+		// The second failure waits twice the first backoff
+		return at.After(time.Now().Add(9*time.Second)) && at.Before(time.Now().Add(11*time.Second))
+	})).Return(nil)
+	logger.On("Warn", append([]interface{}{"Failed to deliver domain event"}, anyArgs(10)...)...).Once()
+
+	dispatcher.Subscribe("audit", func(ctx context.Context, event *entities.DomainEvent) error { return nil })
+	dispatcher.Subscribe("crm", func(ctx context.Context, event *entities.DomainEvent) error {
+		return errors.New("crm unavailable")
+	})
+
+	// Act
+	_, err := dispatcher.DispatchPending(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	outboxRepo.AssertExpectations(t)
+	outboxRepo.AssertNotCalled(t, "MarkDelivered", mock.Anything, mock.Anything)
+	logger.AssertExpectations(t)
+}
+
+func TestEventDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	// Arrange
+	dispatcher, outboxRepo, logger := newEventDispatcher(3)
+	event := outboxEvent(3)
+	outboxRepo.On("Claim", mock.Anything, 100, time.Minute).Return([]*entities.OutboxEvent{event}, nil)
+	outboxRepo.On("MarkFailed", mock.Anything, event.ID, "crm: subscriber panicked: boom").Return(nil)
+	logger.On("Error", append([]interface{}{"Gave up delivering domain event"}, anyArgs(8)...)...).Once()
+
+	dispatcher.Subscribe("crm", func(ctx context.Context, event *entities.DomainEvent) error {
+		panic("boom")
+	})
+
+	// Act
+	_, err := dispatcher.DispatchPending(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	outboxRepo.AssertExpectations(t)
+	logger.AssertExpectations(t)
+}
+
+func TestEventDispatcher_ClaimFailure(t *testing.T) {
+	// Arrange
+	dispatcher, outboxRepo, _ := newEventDispatcher(3)
+	outboxRepo.On("Claim", mock.Anything, 100, time.Minute).Return(nil, errors.New("connection refused"))
+
+	// Act
+	claimed, err := dispatcher.DispatchPending(context.Background())
+
+	// Assert
+	require.Error(t, err)
+	assert.Zero(t, claimed)
+}
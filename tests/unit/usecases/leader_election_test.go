@@ -0,0 +1,129 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/internal/infrastructure/memory"
+	"stock-tracker/tests/mocks"
+)
+
+// fakeClock lets tests expire leases in the in-memory repository without waiting
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func electorLogger() *mocks.MockLogger {
+	logger := &mocks.MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	logger.On("Warn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return logger
+}
+
+func TestLeaderElector_OnlyOneHolderRunsAJob(t *testing.T) {
+	leaseRepo := memory.NewLeaseRepository()
+	first := usecases.NewLeaderElector(leaseRepo, "replica-1", time.Minute, electorLogger())
+	second := usecases.NewLeaderElector(leaseRepo, "replica-2", time.Minute, electorLogger())
+	ctx := context.Background()
+
+	err := first.RunExclusive(ctx, "ingest", func(ctx context.Context, fence usecases.Fence) error {
+		ran := false
+		err := second.RunExclusive(ctx, "ingest", func(context.Context, usecases.Fence) error {
+			ran = true
+			return nil
+		})
+		assert.ErrorIs(t, err, repositories.ErrLeaseHeld)
+		assert.False(t, ran)
+		return fence.Check(ctx)
+	})
+	require.NoError(t, err)
+
+	// Released leases are free at once
+	err = second.RunExclusive(ctx, "ingest", func(context.Context, usecases.Fence) error { return nil })
+	assert.NoError(t, err)
+}
+
+func TestLeaderElector_TakesOverExpiredLease(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	leaseRepo := memory.NewLeaseRepositoryWithClock(clock.Now)
+	ctx := context.Background()
+
+	// The first holder dies without releasing its lease
+	stale, err := leaseRepo.TryAcquire(ctx, "ingest", "replica-1", time.Minute)
+	require.NoError(t, err)
+
+	clock.Advance(2 * time.Minute)
+
+	successor, err := leaseRepo.TryAcquire(ctx, "ingest", "replica-2", time.Minute)
+	require.NoError(t, err)
+	assert.Greater(t, successor.Token, stale.Token)
+
+	// The stale holder is fenced off and cannot hand back the successor's lease
+	assert.ErrorIs(t, leaseRepo.Renew(ctx, stale, time.Minute), repositories.ErrLeaseLost)
+	require.NoError(t, leaseRepo.Release(ctx, stale))
+	_, err = leaseRepo.TryAcquire(ctx, "ingest", "replica-3", time.Minute)
+	assert.ErrorIs(t, err, repositories.ErrLeaseHeld)
+}
+
+func TestLeaderElector_RenewalKeepsLeaseAlive(t *testing.T) {
+	leaseRepo := memory.NewLeaseRepository()
+	holder := usecases.NewLeaderElector(leaseRepo, "replica-1", 60*time.Millisecond, electorLogger())
+	ctx := context.Background()
+
+	err := holder.RunExclusive(ctx, "ingest", func(ctx context.Context, fence usecases.Fence) error {
+		// Outlive the ttl several times over; the renewal loop keeps the lease
+		time.Sleep(200 * time.Millisecond)
+		_, err := leaseRepo.TryAcquire(ctx, "ingest", "replica-2", time.Minute)
+		assert.ErrorIs(t, err, repositories.ErrLeaseHeld)
+		return fence.Check(ctx)
+	})
+	assert.NoError(t, err)
+}
+
+func TestLeaderElector_LostLeaseCancelsJob(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	leaseRepo := memory.NewLeaseRepositoryWithClock(clock.Now)
+	holder := usecases.NewLeaderElector(leaseRepo, "replica-1", 90*time.Millisecond, electorLogger())
+	ctx := context.Background()
+
+	err := holder.RunExclusive(ctx, "ingest", func(ctx context.Context, fence usecases.Fence) error {
+		// The holder stalls past its lease and another replica takes over
+		clock.Advance(time.Second)
+		_, err := leaseRepo.TryAcquire(ctx, "ingest", "replica-2", time.Minute)
+		require.NoError(t, err)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(2 * time.Second):
+			t.Error("job context was not cancelled after losing the lease")
+		}
+		assert.ErrorIs(t, fence.Check(ctx), repositories.ErrLeaseLost)
+		return ctx.Err()
+	})
+
+	assert.ErrorIs(t, err, repositories.ErrLeaseLost)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,99 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/tests/mocks"
+)
+
+func newNormalizationRulesUseCase() (usecases.NormalizationUseCase, *mocks.MockNormalizationRepository) {
+	normalizationRepo := &mocks.MockNormalizationRepository{}
+	logger := &mocks.MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	return usecases.NewNormalizationRulesUseCase(normalizationRepo, logger), normalizationRepo
+}
+
+func TestNormalizationRules_CreateRule_StoresNormalizedPattern(t *testing.T) {
+	// Arrange
+	useCase, normalizationRepo := newNormalizationRulesUseCase()
+	normalizationRepo.On("CreateRule", mock.Anything, mock.MatchedBy(func(rule *entities.NormalizationRule) bool {
+		return rule.Pattern == "speculative buy" && rule.Value == string(entities.RatingTierBuy)
+	})).Return(nil)
+
+	// Act
+	rule, err := useCase.CreateRule(context.Background(), entities.NormalizationKindRating, "", "Speculative-Buy", string(entities.RatingTierBuy))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entities.NormalizationKindRating, rule.Kind)
+	normalizationRepo.AssertExpectations(t)
+}
+
+func TestNormalizationRules_CreateRule_RejectsUnknownValue(t *testing.T) {
+	// Arrange
+	useCase, normalizationRepo := newNormalizationRulesUseCase()
+
+	// Act
+	_, err := useCase.CreateRule(context.Background(), entities.NormalizationKindAction, "", "target set", "sideways")
+
+	// Assert
+	assert.ErrorIs(t, err, usecases.ErrNormalizationRuleInvalid)
+	normalizationRepo.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+}
+
+func TestNormalizationRules_ListLabels_UnmappedOnly(t *testing.T) {
+	// Arrange
+	useCase, normalizationRepo := newNormalizationRulesUseCase()
+	mapped := repositories.StockLabels{
+		Brokerage: "Goldman Sachs", Action: "upgraded by", RatingTo: "Buy",
+		ActionType: entities.ActionTypeUpgrade, RatingToTier: entities.RatingTierBuy, Stocks: 10,
+	}
+	unmapped := repositories.StockLabels{
+		Brokerage: "Goldman Sachs", Action: "upgraded by", RatingTo: "Speculative",
+		ActionType: entities.ActionTypeUpgrade, Stocks: 2,
+	}
+	normalizationRepo.On("ListStockLabels", mock.Anything).Return([]repositories.StockLabels{mapped, unmapped}, nil)
+
+	// Act
+	labels, err := useCase.ListLabels(context.Background(), true)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []repositories.StockLabels{unmapped}, labels)
+}
+
+func TestNormalizationRules_Reapply_UpdatesOnlyChangedLabels(t *testing.T) {
+	// Arrange
+	useCase, normalizationRepo := newNormalizationRulesUseCase()
+	rule := entities.NewNormalizationRule(entities.NormalizationKindRating, "", "Speculative", string(entities.RatingTierBuy))
+	unchanged := repositories.StockLabels{
+		Brokerage: "Goldman Sachs", Action: "upgraded by", RatingTo: "Buy",
+		ActionType: entities.ActionTypeUpgrade, RatingToTier: entities.RatingTierBuy, Stocks: 10,
+	}
+	stale := repositories.StockLabels{
+		Brokerage: "Goldman Sachs", Action: "upgraded by", RatingTo: "Speculative",
+		ActionType: entities.ActionTypeUpgrade, Stocks: 2,
+	}
+	normalizationRepo.On("ListRules", mock.Anything, entities.NormalizationKind("")).Return([]*entities.NormalizationRule{rule}, nil)
+	normalizationRepo.On("ListStockLabels", mock.Anything).Return([]repositories.StockLabels{unchanged, stale}, nil)
+	normalizationRepo.On("UpdateStockLabels", mock.Anything, mock.MatchedBy(func(labels []repositories.StockLabels) bool {
+		return len(labels) == 1 && labels[0].RatingTo == "Speculative" && labels[0].RatingToTier == entities.RatingTierBuy
+	})).Return(2, nil)
+
+	// Act
+	updated, err := useCase.Reapply(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated)
+	normalizationRepo.AssertExpectations(t)
+}
@@ -0,0 +1,133 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/internal/infrastructure/clients"
+	"stock-tracker/tests/mocks"
+)
+
+// fakePriceProvider serves fixed closes per ticker
+type fakePriceProvider struct {
+	closes map[string][]clients.DailyClose
+	err    error
+}
+
+func (p *fakePriceProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakePriceProvider) DailyCloses(ctx context.Context, ticker string, from, to time.Time) ([]clients.DailyClose, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.closes[ticker], nil
+}
+
+func day(value string) time.Time {
+	parsed, _ := time.Parse(time.DateOnly, value)
+	return parsed
+}
+
+// newYork is US Eastern standard time, fixed so tests do not depend on the time zone database
+var newYork = time.FixedZone("EST", -5*60*60)
+
+func newPriceEnrichmentUseCase(provider clients.PriceProvider) (*usecases.PriceEnrichmentUseCase, *mocks.MockStockRepository) {
+	stockRepo := &mocks.MockStockRepository{}
+	logger := &mocks.MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	logger.On("Warn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	return usecases.NewPriceEnrichmentUseCase(stockRepo, provider, newYork, logger), stockRepo
+}
+
+func TestPriceEnrichment_UsesPreviousSessionOnWeekendsAndHolidays(t *testing.T) {
+	// Arrange
+	provider := &fakePriceProvider{closes: map[string][]clients.DailyClose{
+		"AAPL": {
+			{Date: day("2024-01-11"), Close: 185.59},
+			{Date: day("2024-01-12"), Close: 185.92},
+			{Date: day("2024-01-16"), Close: 183.63},
+		},
+	}}
+	useCase, stockRepo := newPriceEnrichmentUseCase(provider)
+
+	trading := repositories.PricingCandidate{ID: uuid.New(), EventTime: time.Date(2024, 1, 16, 15, 0, 0, 0, time.UTC)}
+	saturday := repositories.PricingCandidate{ID: uuid.New(), EventTime: time.Date(2024, 1, 13, 15, 0, 0, 0, time.UTC)}
+	holiday := repositories.PricingCandidate{ID: uuid.New(), EventTime: time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)}
+	// 20:00 in New York on the holiday, already the next day in UTC
+	holidayEvening := repositories.PricingCandidate{ID: uuid.New(), EventTime: time.Date(2024, 1, 16, 1, 0, 0, 0, time.UTC)}
+	today := repositories.PricingCandidate{ID: uuid.New(), EventTime: time.Now()}
+
+	filter := repositories.PricingFilter{}
+	stockRepo.On("ListPricingTickers", mock.Anything, filter).Return([]string{"AAPL"}, nil)
+	stockRepo.On("ListPricingCandidates", mock.Anything, "AAPL", filter).
+		Return([]repositories.PricingCandidate{saturday, holiday, holidayEvening, trading, today}, nil)
+	stockRepo.On("UpdateClosePrices", mock.Anything, []repositories.ClosePrice{
+		{StockID: saturday.ID, Close: 185.92, Date: day("2024-01-12")},
+		{StockID: holiday.ID, Close: 185.92, Date: day("2024-01-12")},
+		{StockID: holidayEvening.ID, Close: 185.92, Date: day("2024-01-12")},
+		{StockID: trading.ID, Close: 183.63, Date: day("2024-01-16")},
+	}).Return(4, nil)
+
+	// Act
+	report, err := useCase.Enrich(context.Background(), filter)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 5, report.Stocks)
+	assert.Equal(t, 4, report.Priced)
+	assert.Equal(t, 1, report.Pending)
+	assert.Equal(t, 0, report.Missing)
+	stockRepo.AssertExpectations(t)
+}
+
+func TestPriceEnrichment_MissingCloses(t *testing.T) {
+	// Arrange
+	provider := &fakePriceProvider{closes: map[string][]clients.DailyClose{
+		"AAPL": {{Date: day("2024-01-02"), Close: 185.64}},
+	}}
+	useCase, stockRepo := newPriceEnrichmentUseCase(provider)
+
+	stale := repositories.PricingCandidate{ID: uuid.New(), EventTime: time.Date(2024, 1, 16, 15, 0, 0, 0, time.UTC)}
+	filter := repositories.PricingFilter{Tickers: []string{"AAPL"}, Overwrite: true}
+	stockRepo.On("ListPricingTickers", mock.Anything, filter).Return([]string{"AAPL"}, nil)
+	stockRepo.On("ListPricingCandidates", mock.Anything, "AAPL", filter).Return([]repositories.PricingCandidate{stale}, nil)
+
+	// Act
+	report, err := useCase.Enrich(context.Background(), filter)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Missing)
+	assert.Equal(t, 0, report.Priced)
+	stockRepo.AssertNotCalled(t, "UpdateClosePrices", mock.Anything, mock.Anything)
+}
+
+func TestPriceEnrichment_ProviderFailureSkipsTicker(t *testing.T) {
+	// Arrange
+	useCase, stockRepo := newPriceEnrichmentUseCase(&fakePriceProvider{err: errors.New("vendor down")})
+
+	candidate := repositories.PricingCandidate{ID: uuid.New(), EventTime: time.Date(2024, 1, 16, 15, 0, 0, 0, time.UTC)}
+	filter := repositories.PricingFilter{}
+	stockRepo.On("ListPricingTickers", mock.Anything, filter).Return([]string{"AAPL", "MSFT"}, nil)
+	stockRepo.On("ListPricingCandidates", mock.Anything, mock.Anything, filter).Return([]repositories.PricingCandidate{candidate}, nil)
+
+	// Act
+	report, err := useCase.Enrich(context.Background(), filter)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AAPL", "MSFT"}, report.FailedTickers)
+	stockRepo.AssertNotCalled(t, "UpdateClosePrices", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,177 @@
+package usecases_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/tests/mocks"
+)
+
+func newStockEditingUseCase(brokers ...*entities.Broker) (usecases.StockEditUseCase, *mocks.MockStockRepository, *mocks.MockBrokerRepository, *mocks.MockStockChangeRepository) {
+	stockRepo := &mocks.MockStockRepository{}
+	brokerRepo := &mocks.MockBrokerRepository{}
+	brokerRepo.On("GetAll", mock.Anything).Return(brokers, nil).Maybe()
+	brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{}, nil).Maybe()
+	normalizationRepo := &mocks.MockNormalizationRepository{}
+	normalizationRepo.On("ListRules", mock.Anything, entities.NormalizationKind("")).Return([]*entities.NormalizationRule{}, nil).Maybe()
+	changeRepo := &mocks.MockStockChangeRepository{}
+	logger := &mocks.MockLogger{}
+	logger.On("Info", anyArgs(7)...).Maybe()
+
+	return usecases.NewStockEditingUseCase(stockRepo, brokerRepo, normalizationRepo, changeRepo, logger), stockRepo, brokerRepo, changeRepo
+}
+
+func TestStockEditing_CreateStock(t *testing.T) {
+	// Arrange
+	broker := entities.NewBroker("Goldman Sachs", 0.95)
+	useCase, stockRepo, _, _ := newStockEditingUseCase(broker)
+	actor := uuid.New()
+	eventTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	stockRepo.On("ApplyChange", mock.Anything, mock.MatchedBy(func(change *entities.StockChange) bool {
+		stock := change.After
+		return change.Kind == entities.StockChangeCreated && change.ChangedBy == actor && change.Before == nil &&
+			stock != nil && change.StockID == stock.ID &&
+			stock.Ticker == "AAPL" && stock.BrokerID == broker.ID && stock.Currency == "EUR" &&
+			stock.ActionType == entities.ActionTypeUpgrade && stock.Source == "manual"
+	})).Return(nil)
+
+	// Act
+	stock, err := useCase.CreateStock(context.Background(), actor, usecases.StockInput{
+		Ticker: " aapl ", Company: "Apple Inc.", Brokerage: "goldman sachs", Action: "upgraded by",
+		TargetFrom: 150, TargetTo: 180, Currency: "eur", EventTime: eventTime,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL", stock.Ticker)
+	stockRepo.AssertExpectations(t)
+}
+
+func TestStockEditing_CreateStock_RejectsInvalidInput(t *testing.T) {
+	eventTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		input usecases.StockInput
+	}{
+		{name: "Missing company", input: usecases.StockInput{Ticker: "AAPL", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: eventTime}},
+		{name: "Missing brokerage", input: usecases.StockInput{Ticker: "AAPL", Company: "Apple Inc.", Action: "upgraded by", EventTime: eventTime}},
+		{name: "Missing event time", input: usecases.StockInput{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by"}},
+		{name: "Invalid currency", input: usecases.StockInput{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", Currency: "dollars", EventTime: eventTime}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			useCase, stockRepo, _, _ := newStockEditingUseCase()
+
+			// Act
+			_, err := useCase.CreateStock(context.Background(), uuid.New(), tc.input)
+
+			// Assert
+			assert.ErrorIs(t, err, usecases.ErrStockInvalid)
+			stockRepo.AssertNotCalled(t, "ApplyChange", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestStockEditing_UpdateStock_RecordsChangedFields(t *testing.T) {
+	// Arrange
+	broker := entities.NewBroker("Goldman Sachs", 0.95)
+	useCase, stockRepo, _, _ := newStockEditingUseCase(broker)
+	actor := uuid.New()
+	price := 182.5
+	existing := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "upgraded by", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	existing.BrokerID = broker.ID
+	existing.TargetTo = 180
+	existing.PriceClose = &price
+
+	stockRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	stockRepo.On("ApplyChange", mock.Anything, mock.MatchedBy(func(change *entities.StockChange) bool {
+		stock := change.After
+		return change.Kind == entities.StockChangeUpdated && change.ChangedBy == actor &&
+			change.Before == existing && change.Before.TargetTo == 180 &&
+			stock.ID == existing.ID && stock.TargetTo == 200 && stock.PriceClose == nil
+	})).Return(nil).Run(func(args mock.Arguments) {
+		assert.Equal(t, []string{"event_time", "target_to"}, args.Get(1).(*entities.StockChange).Fields)
+	})
+
+	// Act
+	stock, err := useCase.UpdateStock(context.Background(), actor, existing.ID, usecases.StockInput{
+		Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by",
+		TargetTo: 200, EventTime: time.Date(2024, 1, 16, 10, 30, 0, 0, time.UTC),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 200.0, stock.TargetTo)
+	assert.Equal(t, &price, existing.PriceClose, "the stored stock is left untouched")
+	stockRepo.AssertExpectations(t)
+}
+
+func TestStockEditing_UpdateStock_FailsWhenChangeIsNotRecorded(t *testing.T) {
+	// Arrange
+	broker := entities.NewBroker("Goldman Sachs", 0.95)
+	useCase, stockRepo, _, _ := newStockEditingUseCase(broker)
+	existing := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "upgraded by", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	existing.BrokerID = broker.ID
+
+	stockRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	stockRepo.On("ApplyChange", mock.Anything, mock.AnythingOfType("*entities.StockChange")).Return(fmt.Errorf("failed to save stock change: %w", context.DeadlineExceeded))
+
+	// Act
+	stock, err := useCase.UpdateStock(context.Background(), uuid.New(), existing.ID, usecases.StockInput{
+		Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by",
+		TargetTo: 200, EventTime: existing.EventTime,
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to save stock change")
+	assert.Nil(t, stock)
+}
+
+func TestStockEditing_DeleteStock(t *testing.T) {
+	// Arrange
+	useCase, stockRepo, _, _ := newStockEditingUseCase()
+	actor := uuid.New()
+	existing := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "upgraded by", time.Now())
+
+	stockRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil)
+	stockRepo.On("ApplyChange", mock.Anything, mock.MatchedBy(func(change *entities.StockChange) bool {
+		return change.Kind == entities.StockChangeDeleted && change.StockID == existing.ID &&
+			change.Before == existing && change.After == nil
+	})).Return(nil)
+
+	// Act
+	err := useCase.DeleteStock(context.Background(), actor, existing.ID)
+
+	// Assert
+	require.NoError(t, err)
+	stockRepo.AssertExpectations(t)
+}
+
+func TestStockEditing_DeleteStock_NotFound(t *testing.T) {
+	// Arrange
+	useCase, stockRepo, _, _ := newStockEditingUseCase()
+	id := uuid.New()
+	stockRepo.On("GetByID", mock.Anything, id).Return(nil, fmt.Errorf("stock %s: %w", id, repositories.ErrNotFound))
+
+	// Act
+	err := useCase.DeleteStock(context.Background(), uuid.New(), id)
+
+	// Assert
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	stockRepo.AssertNotCalled(t, "ApplyChange", mock.Anything, mock.Anything)
+}
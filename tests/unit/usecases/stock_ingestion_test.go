@@ -2,39 +2,71 @@ package usecases_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
 	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
 	"stock-tracker/internal/domain/usecases"
 	"stock-tracker/internal/domain/valueObjects"
+	"stock-tracker/internal/infrastructure/clients"
+	"stock-tracker/internal/infrastructure/memory"
 	"stock-tracker/tests/mocks"
 )
 
 type StockIngestionUseCaseSuite struct {
 	suite.Suite
-	stockRepo  *mocks.MockStockRepository
-	brokerRepo *mocks.MockBrokerRepository
-	apiClient  *mocks.MockStockAPIClient
-	logger     *mocks.MockLogger
-	useCase    *usecases.StockIngestionUseCase
+	stockRepo        *mocks.MockStockRepository
+	brokerRepo       *mocks.MockBrokerRepository
+	ingestionLogRepo *mocks.MockIngestionLogRepository
+	checkpointRepo   *mocks.MockIngestionCheckpointRepository
+	deadLetterRepo   *mocks.MockDeadLetterRepository
+	apiClient        *mocks.MockStockAPIClient
+	logger           *mocks.MockLogger
+	useCase          *usecases.StockIngestionUseCase
+
+	// noCheckpoint makes every run a first run; tests exercising resumption unset it
+	noCheckpoint *mock.Call
+	// newestFirst makes the source behave like the API feed; tests of unordered sources unset it
+	newestFirst *mock.Call
+	// noAliases leaves brokers without aliases; tests of alias resolution unset it
+	noAliases *mock.Call
 }
 
 func (suite *StockIngestionUseCaseSuite) SetupTest() {
 	suite.stockRepo = &mocks.MockStockRepository{}
 	suite.brokerRepo = &mocks.MockBrokerRepository{}
+	suite.ingestionLogRepo = &mocks.MockIngestionLogRepository{}
+	suite.checkpointRepo = &mocks.MockIngestionCheckpointRepository{}
+	suite.deadLetterRepo = &mocks.MockDeadLetterRepository{}
 	suite.apiClient = &mocks.MockStockAPIClient{}
 	suite.logger = &mocks.MockLogger{}
 
+	// Every run is recorded; individual tests inspect the recorded run when relevant
+	suite.ingestionLogRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.IngestionLog")).Return(nil).Maybe()
+	suite.ingestionLogRepo.On("Update", mock.Anything, mock.AnythingOfType("*entities.IngestionLog")).Return(nil).Maybe()
+	suite.checkpointRepo.On("Save", mock.Anything, mock.AnythingOfType("*entities.IngestionCheckpoint")).Return(nil).Maybe()
+	suite.noCheckpoint = suite.checkpointRepo.On("GetLatest", mock.Anything, "api").Return(nil, repositories.ErrNotFound).Maybe()
+	suite.noAliases = suite.brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{}, nil).Maybe()
+	suite.apiClient.On("Name").Return("api").Maybe()
+	suite.newestFirst = suite.apiClient.On("NewestFirst").Return(true).Maybe()
+
 	suite.useCase = usecases.NewStockIngestionUseCase(
 		suite.stockRepo,
 		suite.brokerRepo,
+		suite.ingestionLogRepo,
+		suite.checkpointRepo,
+		suite.deadLetterRepo,
 		suite.apiClient,
 		suite.logger,
 	)
@@ -43,6 +75,9 @@ func (suite *StockIngestionUseCaseSuite) SetupTest() {
 func (suite *StockIngestionUseCaseSuite) TearDownTest() {
 	suite.stockRepo.AssertExpectations(suite.T())
 	suite.brokerRepo.AssertExpectations(suite.T())
+	suite.ingestionLogRepo.AssertExpectations(suite.T())
+	suite.checkpointRepo.AssertExpectations(suite.T())
+	suite.deadLetterRepo.AssertExpectations(suite.T())
 	suite.apiClient.AssertExpectations(suite.T())
 	suite.logger.AssertExpectations(suite.T())
 }
@@ -51,6 +86,17 @@ func TestStockIngestionUseCaseSuite(t *testing.T) {
 	suite.Run(t, new(StockIngestionUseCaseSuite))
 }
 
+// recordedRun returns the ingestion run passed to the last Update call
+func (suite *StockIngestionUseCaseSuite) recordedRun() *entities.IngestionLog {
+	var run *entities.IngestionLog
+	for _, call := range suite.ingestionLogRepo.Calls {
+		if call.Method == "Update" {
+			run = call.Arguments.Get(1).(*entities.IngestionLog)
+		}
+	}
+	return run
+}
+
 func (suite *StockIngestionUseCaseSuite) TestIngestStocks_Success() {
 	// Arrange
 	ctx := context.Background()
@@ -77,10 +123,10 @@ func (suite *StockIngestionUseCaseSuite) TestIngestStocks_Success() {
 
 	// Setup expectations
 	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-	suite.apiClient.On("FetchAllStocks", ctx).Return(testStocks, nil)
-	suite.brokerRepo.On("GetAll", ctx).Return(existingBrokers, nil)
-	suite.brokerRepo.On("Create", ctx, mock.AnythingOfType("*entities.Broker")).Return(nil)
-	suite.stockRepo.On("BulkCreate", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(nil)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return(existingBrokers, nil)
+	suite.brokerRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Broker")).Return(nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{}, nil)
 
 	// Act
 	err := suite.useCase.IngestStocks(ctx)
@@ -97,7 +143,7 @@ func (suite *StockIngestionUseCaseSuite) TestIngestStocks_APIClientError() {
 	// Setup expectations
 	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	suite.logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-	suite.apiClient.On("FetchAllStocks", ctx).Return([]*entities.Stock{}, expectedError)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(nil, expectedError)
 
 	// Act
 	err := suite.useCase.IngestStocks(ctx)
@@ -107,6 +153,28 @@ func (suite *StockIngestionUseCaseSuite) TestIngestStocks_APIClientError() {
 	assert.Contains(suite.T(), err.Error(), "failed to fetch stocks")
 }
 
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_ClassifiesUpstreamFailure() {
+	// Arrange
+	ctx := context.Background()
+	throttled := fmt.Errorf("API returned status 429: %w", clients.ErrRateLimited)
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(nil, throttled)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, clients.ErrRateLimited)
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), entities.IngestionStatusFailed, run.Status)
+		assert.Equal(suite.T(), "rate_limited", run.ErrorDetails["reason"])
+		assert.Equal(suite.T(), true, run.ErrorDetails["retryable"])
+	}
+}
+
 func (suite *StockIngestionUseCaseSuite) TestIngestStocks_EmptyStocks() {
 	// Arrange
 	ctx := context.Background()
@@ -114,7 +182,7 @@ func (suite *StockIngestionUseCaseSuite) TestIngestStocks_EmptyStocks() {
 
 	// Setup expectations
 	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-	suite.apiClient.On("FetchAllStocks", ctx).Return(emptyStocks, nil)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(emptyStocks, ""), nil)
 
 	// Act
 	err := suite.useCase.IngestStocks(ctx)
@@ -140,8 +208,8 @@ func (suite *StockIngestionUseCaseSuite) TestIngestStocks_BrokerRepositoryError(
 	// Setup expectations
 	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	suite.logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-	suite.apiClient.On("FetchAllStocks", ctx).Return(testStocks, nil)
-	suite.brokerRepo.On("GetAll", ctx).Return([]*entities.Broker{}, expectedError)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{}, expectedError)
 
 	// Act
 	err := suite.useCase.IngestStocks(ctx)
@@ -171,9 +239,9 @@ func (suite *StockIngestionUseCaseSuite) TestIngestStocks_StockRepositoryError()
 	// Setup expectations
 	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	suite.logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-	suite.apiClient.On("FetchAllStocks", ctx).Return(testStocks, nil)
-	suite.brokerRepo.On("GetAll", ctx).Return(existingBrokers, nil)
-	suite.stockRepo.On("BulkCreate", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(expectedError)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return(existingBrokers, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(nil, expectedError)
 
 	// Act
 	err := suite.useCase.IngestStocks(ctx)
@@ -199,12 +267,12 @@ func (suite *StockIngestionUseCaseSuite) TestEnrichWithBrokerInfo_NewBroker() {
 
 	// Setup expectations - test through IngestStocks which calls the private method
 	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-	suite.apiClient.On("FetchAllStocks", ctx).Return(testStocks, nil)
-	suite.brokerRepo.On("GetAll", ctx).Return(existingBrokers, nil)
-	suite.brokerRepo.On("Create", ctx, mock.MatchedBy(func(broker *entities.Broker) bool {
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return(existingBrokers, nil)
+	suite.brokerRepo.On("Create", mock.Anything, mock.MatchedBy(func(broker *entities.Broker) bool {
 		return broker.Name == "New Brokerage" && broker.CredibilityScore == 0.60
 	})).Return(nil)
-	suite.stockRepo.On("BulkCreate", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{}, nil)
 
 	// Act - test the private method through the public interface
 	err := suite.useCase.IngestStocks(ctx)
@@ -230,9 +298,9 @@ func (suite *StockIngestionUseCaseSuite) TestEnrichWithBrokerInfo_ExistingBroker
 
 	// Setup expectations - test through IngestStocks which calls the private method
 	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-	suite.apiClient.On("FetchAllStocks", ctx).Return(testStocks, nil)
-	suite.brokerRepo.On("GetAll", ctx).Return(existingBrokers, nil)
-	suite.stockRepo.On("BulkCreate", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(nil)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return(existingBrokers, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{}, nil)
 
 	// Act - test the private method through the public interface
 	err := suite.useCase.IngestStocks(ctx)
@@ -241,6 +309,40 @@ func (suite *StockIngestionUseCaseSuite) TestEnrichWithBrokerInfo_ExistingBroker
 	assert.NoError(suite.T(), err)
 }
 
+func (suite *StockIngestionUseCaseSuite) TestEnrichWithBrokerInfo_ResolvesAliasesAndNameVariants() {
+	// Arrange
+	ctx := context.Background()
+	jpMorgan := entities.NewBroker("JPMorgan Chase & Co.", 0.90)
+	goldman := entities.NewBroker("Goldman Sachs", 0.95)
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "JP Morgan", Action: "upgraded by", EventTime: time.Now()},
+		{Ticker: "MSFT", Company: "Microsoft", Brokerage: "The Goldman Sachs Group, Inc.", Action: "upgraded by", EventTime: time.Now()},
+		{Ticker: "NVDA", Company: "Nvidia", Brokerage: "goldman sachs", Action: "upgraded by", EventTime: time.Now()},
+	}
+
+	suite.noAliases.Unset()
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{goldman, jpMorgan}, nil)
+	suite.brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{
+		entities.NewBrokerAlias(jpMorgan.ID, "JP Morgan"),
+		entities.NewBrokerAlias(goldman.ID, "The Goldman Sachs Group"),
+	}, nil)
+	suite.brokerRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Broker")).Return(nil).Maybe()
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 3 && stocks[0].BrokerID == jpMorgan.ID &&
+			stocks[1].BrokerID == goldman.ID && stocks[2].BrokerID == goldman.ID
+	})).Return(&repositories.UpsertResult{Inserted: 3}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.brokerRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.stockRepo.AssertExpectations(suite.T())
+}
+
 func (suite *StockIngestionUseCaseSuite) TestGetStats() {
 	// Arrange
 	ctx := context.Background()
@@ -325,11 +427,11 @@ func (suite *StockIngestionUseCaseSuite) TestIngestStocks_ConcurrentProcessing()
 
 	// Setup expectations
 	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
-	suite.apiClient.On("FetchAllStocks", ctx).Return(testStocks, nil)
-	suite.brokerRepo.On("GetAll", ctx).Return(existingBrokers, nil)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return(existingBrokers, nil)
 
-	// Expect multiple BulkCreate calls for different batches
-	suite.stockRepo.On("BulkCreate", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(nil).Times(3) // 250 stocks / 100 batch size = 3 batches
+	// Expect multiple BulkUpsert calls for different batches
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{}, nil).Times(3) // 250 stocks / 100 batch size = 3 batches
 
 	// Act
 	err := suite.useCase.IngestStocks(ctx)
@@ -345,10 +447,10 @@ func (suite *StockIngestionUseCaseSuite) TestIngestStocks_ContextCancellation()
 	cancel() // Cancel immediately
 
 	// Setup expectations with context that gets cancelled
-	suite.apiClient.On("FetchAllStocks", mock.MatchedBy(func(ctx context.Context) bool {
+	suite.apiClient.On("FetchRawPage", mock.MatchedBy(func(ctx context.Context) bool {
 		// Check if context is cancelled
 		return ctx.Err() != nil
-	})).Return([]*entities.Stock{}, context.Canceled)
+	}), "").Return(nil, context.Canceled)
 
 	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	suite.logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
@@ -360,3 +462,1017 @@ func (suite *StockIngestionUseCaseSuite) TestIngestStocks_ContextCancellation()
 	assert.Error(suite.T(), err)
 	assert.Contains(suite.T(), err.Error(), "failed to fetch stocks")
 }
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_RecordsCompletedRun() {
+	// Arrange
+	ctx := context.Background()
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+		{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{Inserted: 1, Duplicates: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), entities.IngestionStatusCompleted, run.Status)
+		assert.Equal(suite.T(), 2, run.TotalRecords)
+		assert.Equal(suite.T(), 2, run.SuccessfulRecords)
+		assert.Equal(suite.T(), 0, run.FailedRecords)
+		assert.Equal(suite.T(), 1, run.InsertedRecords)
+		assert.Equal(suite.T(), 1, run.DuplicateRecords)
+		assert.NotNil(suite.T(), run.CompletedAt)
+	}
+	suite.ingestionLogRepo.AssertCalled(suite.T(), "Create", mock.Anything, run)
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_RecordsFailedRun() {
+	// Arrange
+	ctx := context.Background()
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(nil, errors.New("stock repository error"))
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), entities.IngestionStatusFailed, run.Status)
+		assert.Equal(suite.T(), 1, run.FailedRecords)
+		assert.Contains(suite.T(), run.ErrorDetails["error"], "stock repository error")
+		assert.NotNil(suite.T(), run.CompletedAt)
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_PartialBatchFailure() {
+	// Arrange
+	ctx := context.Background()
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+		{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+		{Ticker: "GOOG", Company: "Alphabet", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Warn", "Failed to store stock", "batch_num", 0, "ticker", "GOOG", "error", mock.Anything).Once()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{
+		Inserted: 1,
+		Updated:  1,
+		Failed:   1,
+		Failures: []repositories.UpsertFailure{{Stock: testStocks[2], Err: errors.New("value too long")}},
+	}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), entities.IngestionStatusCompleted, run.Status)
+		assert.Equal(suite.T(), 3, run.TotalRecords)
+		assert.Equal(suite.T(), 2, run.SuccessfulRecords)
+		assert.Equal(suite.T(), 1, run.FailedRecords)
+		assert.Equal(suite.T(), 1, run.InsertedRecords)
+		assert.Equal(suite.T(), 1, run.UpdatedRecords)
+	}
+	suite.logger.AssertExpectations(suite.T())
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_LimitsConcurrentBatches() {
+	// Arrange
+	ctx := context.Background()
+	testStocks := make([]*entities.Stock, 1000)
+	for i := range testStocks {
+		testStocks[i] = &entities.Stock{
+			Ticker:    fmt.Sprintf("STOCK%d", i),
+			Company:   fmt.Sprintf("Company %d", i),
+			Brokerage: "Test Brokerage",
+			Action:    "upgraded by",
+			EventTime: time.Now(),
+		}
+	}
+
+	var inFlight, maxInFlight int32
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Test Brokerage", 0.80)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).
+		Run(func(args mock.Arguments) {
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				seen := atomic.LoadInt32(&maxInFlight)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+		}).
+		Return(&repositories.UpsertResult{Inserted: 100}, nil).Times(10)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.LessOrEqual(suite.T(), atomic.LoadInt32(&maxInFlight), int32(5))
+	suite.stockRepo.AssertExpectations(suite.T())
+}
+
+// apiPage wraps stocks into the raw upstream page they would have been converted from
+func apiPage(stocks []*entities.Stock, nextPage string) *clients.StockAPIResponse {
+	items := make([]clients.StockAPIItem, 0, len(stocks))
+	for _, stock := range stocks {
+		items = append(items, clients.StockAPIItem{
+			Ticker:    stock.Ticker,
+			Company:   stock.Company,
+			Brokerage: stock.Brokerage,
+			Action:    stock.Action,
+			Time:      stock.EventTime.Format(time.RFC3339),
+		})
+	}
+	return &clients.StockAPIResponse{Items: items, NextPage: nextPage}
+}
+
+// savedCheckpoint returns the checkpoint passed to the last Save call
+func (suite *StockIngestionUseCaseSuite) savedCheckpoint() *entities.IngestionCheckpoint {
+	var checkpoint *entities.IngestionCheckpoint
+	for _, call := range suite.checkpointRepo.Calls {
+		if call.Method == "Save" {
+			checkpoint = call.Arguments.Get(1).(*entities.IngestionCheckpoint)
+		}
+	}
+	return checkpoint
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_CheckpointsEachPage() {
+	// Arrange
+	ctx := context.Background()
+	firstPage := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}
+	secondPage := []*entities.Stock{
+		{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now().Add(-time.Hour)},
+	}
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(firstPage, "page-2"), nil)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "page-2").Return(apiPage(secondPage, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil).Once()
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{}, nil).Times(2)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.checkpointRepo.AssertNumberOfCalls(suite.T(), "Save", 3) // opened, then once per page
+	checkpoint := suite.savedCheckpoint()
+	if assert.NotNil(suite.T(), checkpoint) {
+		assert.True(suite.T(), checkpoint.IsCompleted())
+		assert.Equal(suite.T(), 2, checkpoint.PagesFetched)
+		assert.Equal(suite.T(), 2, checkpoint.RecordsFetched)
+		assert.Nil(suite.T(), checkpoint.Watermark)
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_ResumesFromCheckpoint() {
+	// Arrange
+	ctx := context.Background()
+	watermark := time.Now().Add(-24 * time.Hour)
+	interrupted := entities.NewIngestionCheckpoint("crashed-batch", "api", &watermark)
+	interrupted.Advance("page-2", 100)
+	interrupted.Advance("page-3", 100)
+
+	suite.noCheckpoint.Unset()
+	suite.checkpointRepo.On("GetLatest", ctx, "api").Return(interrupted, nil)
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "page-3").Return(apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.apiClient.AssertNotCalled(suite.T(), "FetchRawPage", mock.Anything, "")
+	checkpoint := suite.savedCheckpoint()
+	if assert.NotNil(suite.T(), checkpoint) {
+		assert.Equal(suite.T(), interrupted.ID, checkpoint.ID)
+		assert.NotEqual(suite.T(), "crashed-batch", checkpoint.BatchID)
+		assert.Equal(suite.T(), 3, checkpoint.PagesFetched)
+		assert.Equal(suite.T(), 201, checkpoint.RecordsFetched)
+		assert.True(suite.T(), checkpoint.IsCompleted())
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_StopsAtWatermark() {
+	// Arrange
+	ctx := context.Background()
+	latestStored := time.Now().Add(-time.Hour)
+	previous := entities.NewIngestionCheckpoint("previous-batch", "api", nil)
+	previous.Complete()
+
+	suite.noCheckpoint.Unset()
+	suite.checkpointRepo.On("GetLatest", ctx, "api").Return(previous, nil)
+	suite.stockRepo.On("GetLatestEventTime", ctx, "api").Return(&latestStored, nil)
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+		{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: latestStored.Add(-time.Minute)},
+	}, "page-2"), nil)
+	// The fetch stage may read ahead one page before the watermark is seen; it is never persisted
+	suite.apiClient.On("FetchRawPage", mock.Anything, "page-2").Return(apiPage([]*entities.Stock{
+		{Ticker: "NFLX", Company: "Netflix", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: latestStored.Add(-2 * time.Minute)},
+	}, ""), nil).Maybe()
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Ticker == "AAPL"
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	checkpoint := suite.savedCheckpoint()
+	if assert.NotNil(suite.T(), checkpoint) {
+		assert.NotEqual(suite.T(), previous.ID, checkpoint.ID)
+		assert.Equal(suite.T(), latestStored, *checkpoint.Watermark)
+		assert.True(suite.T(), checkpoint.IsCompleted())
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_FetchesNothingPastWatermark() {
+	// Arrange
+	ctx := context.Background()
+	latestStored := time.Now().Add(-time.Hour)
+	previous := entities.NewIngestionCheckpoint("previous-batch", "api", nil)
+	previous.Complete()
+	stored := make(chan struct{})
+
+	suite.noCheckpoint.Unset()
+	suite.checkpointRepo.On("GetLatest", ctx, "api").Return(previous, nil)
+	suite.stockRepo.On("GetLatestEventTime", ctx, "api").Return(&latestStored, nil)
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+		{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: latestStored.Add(-time.Minute)},
+	}, "page-2"), nil)
+	// A read-ahead of page 2 only returns once the first page is stored, by when the watermark has been seen
+	suite.apiClient.On("FetchRawPage", mock.Anything, "page-2").Run(func(mock.Arguments) { <-stored }).Return(apiPage([]*entities.Stock{
+		{Ticker: "NFLX", Company: "Netflix", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: latestStored.Add(-2 * time.Minute)},
+	}, "page-3"), nil).Maybe()
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Run(func(mock.Arguments) {
+		close(stored)
+	}).Return(&repositories.UpsertResult{Inserted: 1}, nil).Once()
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.apiClient.AssertNotCalled(suite.T(), "FetchRawPage", mock.Anything, "page-3")
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_WatermarksAtNewestEventOfOwnSource() {
+	// Arrange
+	ctx := context.Background()
+	// Another vendor has stored newer events than this one; only this vendor's own newest event counts
+	otherVendorLatest := time.Now().Add(-time.Hour)
+	ownLatest := otherVendorLatest.Add(-24 * time.Hour)
+	previous := entities.NewIngestionCheckpoint("previous-batch", "api", nil)
+	previous.Complete()
+
+	suite.noCheckpoint.Unset()
+	suite.checkpointRepo.On("GetLatest", ctx, "api").Return(previous, nil)
+	suite.stockRepo.On("GetLatestEventTime", ctx, "api").Return(&ownLatest, nil)
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: otherVendorLatest.Add(-time.Hour)},
+	}, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Ticker == "AAPL"
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	checkpoint := suite.savedCheckpoint()
+	if assert.NotNil(suite.T(), checkpoint) {
+		assert.Equal(suite.T(), ownLatest, *checkpoint.Watermark)
+	}
+	suite.stockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_UnorderedSourceIgnoresWatermark() {
+	// Arrange
+	ctx := context.Background()
+	previous := entities.NewIngestionCheckpoint("previous-batch", "api", nil)
+	previous.Complete()
+
+	suite.noCheckpoint.Unset()
+	suite.newestFirst.Unset()
+	suite.apiClient.On("NewestFirst").Return(false)
+	suite.checkpointRepo.On("GetLatest", ctx, "api").Return(previous, nil)
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now().AddDate(-3, 0, 0)},
+	}, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.stockRepo.AssertNotCalled(suite.T(), "GetLatestEventTime", mock.Anything, mock.Anything)
+	checkpoint := suite.savedCheckpoint()
+	if assert.NotNil(suite.T(), checkpoint) {
+		assert.Nil(suite.T(), checkpoint.Watermark)
+		assert.Equal(suite.T(), "api", checkpoint.Source)
+		assert.True(suite.T(), checkpoint.IsCompleted())
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_PageFailureKeepsLastCommittedPage() {
+	// Arrange
+	ctx := context.Background()
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}, "page-2"), nil)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "page-2").Return(nil, errors.New("upstream unavailable"))
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "page 2")
+	checkpoint := suite.savedCheckpoint()
+	if assert.NotNil(suite.T(), checkpoint) {
+		assert.False(suite.T(), checkpoint.IsCompleted())
+		assert.Equal(suite.T(), 1, checkpoint.PagesFetched)
+		assert.Equal(suite.T(), "page-2", checkpoint.NextPage)
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_QuarantinesInvalidItems() {
+	// Arrange
+	ctx := context.Background()
+	page := apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}, "")
+	page.Items = append(page.Items,
+		clients.StockAPIItem{Ticker: "BAD", Company: "Bad Time", Brokerage: "Goldman Sachs", Action: "upgraded by", Time: "not-a-time"},
+		clients.StockAPIItem{Ticker: "NOCO", Brokerage: "Goldman Sachs", Action: "upgraded by", Time: time.Now().Format(time.RFC3339)},
+	)
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Warn", "Quarantined upstream record", "ticker", mock.Anything, "reason", mock.Anything).Times(2)
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(page, nil)
+	suite.deadLetterRepo.On("Create", mock.Anything, mock.MatchedBy(func(record *entities.DeadLetterRecord) bool {
+		return record.IsPending() && record.Source == "api" && strings.Contains(string(record.Payload), `"ticker":"BAD"`) && strings.Contains(record.Reason, "failed to parse event time")
+	})).Return(nil).Once()
+	suite.deadLetterRepo.On("Create", mock.Anything, mock.MatchedBy(func(record *entities.DeadLetterRecord) bool {
+		return strings.Contains(string(record.Payload), `"ticker":"NOCO"`) && strings.Contains(record.Reason, "invalid stock")
+	})).Return(nil).Once()
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Ticker == "AAPL"
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), 3, run.TotalRecords)
+		assert.Equal(suite.T(), 1, run.SuccessfulRecords)
+		assert.Equal(suite.T(), 2, run.FailedRecords)
+	}
+	for _, call := range suite.deadLetterRepo.Calls {
+		assert.Equal(suite.T(), run.BatchID, call.Arguments.Get(1).(*entities.DeadLetterRecord).BatchID)
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_DropsEventCoveredByPreferredSource() {
+	// Arrange
+	ctx := context.Background()
+	eventTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	broker := entities.NewBroker("Goldman Sachs", 0.95)
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: eventTime},
+	}
+	stored := &entities.Stock{ID: uuid.New(), Ticker: "AAPL", BrokerID: broker.ID, Source: "vendor_a", EventTime: eventTime.Add(-30 * time.Minute)}
+
+	suite.useCase.SetSourcePriority([]string{"vendor_a", "api"}, time.Hour)
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{broker}, nil)
+	suite.stockRepo.On("GetNearbyEvents", mock.Anything, []string{"AAPL"}, mock.Anything, mock.Anything).Return([]*entities.Stock{stored}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.stockRepo.AssertNotCalled(suite.T(), "BulkUpsert", mock.Anything, mock.Anything)
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), 1, run.DuplicateRecords)
+		assert.Equal(suite.T(), 0, run.InsertedRecords)
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_ReplacesEventFromLowerPrioritySource() {
+	// Arrange
+	ctx := context.Background()
+	eventTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	broker := entities.NewBroker("Goldman Sachs", 0.95)
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: eventTime},
+	}
+	stored := &entities.Stock{ID: uuid.New(), Ticker: "AAPL", BrokerID: broker.ID, Source: "vendor_b", EventTime: eventTime.Add(20 * time.Minute)}
+
+	suite.useCase.SetSourcePriority([]string{"api", "vendor_b"}, time.Hour)
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{broker}, nil)
+	suite.stockRepo.On("GetNearbyEvents", mock.Anything, []string{"AAPL"}, mock.Anything, mock.Anything).Return([]*entities.Stock{stored}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Source == "api"
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+	suite.stockRepo.On("Delete", mock.Anything, stored.ID).Return(nil).Once()
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), 1, run.InsertedRecords)
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_KeepsRowOfPreferredSourceAtSameEventTime() {
+	// Arrange
+	ctx := context.Background()
+	eventTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	goldman, barclays := entities.NewBroker("Goldman Sachs", 0.95), entities.NewBroker("Barclays", 0.85)
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: eventTime},
+	}
+	// Another broker and rating, but the row of the same ticker and event time the upsert would overwrite
+	stored := &entities.Stock{ID: uuid.New(), Ticker: "AAPL", BrokerID: barclays.ID, Brokerage: "Barclays", RatingTo: "Sell", Source: "vendor_a", EventTime: eventTime}
+
+	suite.useCase.SetSourcePriority([]string{"vendor_a", "api"}, time.Hour)
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{goldman, barclays}, nil)
+	suite.stockRepo.On("GetNearbyEvents", mock.Anything, []string{"AAPL"}, mock.Anything, mock.Anything).Return([]*entities.Stock{stored}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.stockRepo.AssertNotCalled(suite.T(), "BulkUpsert", mock.Anything, mock.Anything)
+	suite.stockRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), 1, run.DuplicateRecords)
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_MatchesBrokerSpellingsAcrossSources() {
+	// Arrange
+	ctx := context.Background()
+	eventTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	jpMorgan := entities.NewBroker("J.P. Morgan", 0.9)
+	// Brokers created by each vendor's spelling before the aliases tied them to J.P. Morgan
+	jpmChase, jpmSecurities := entities.NewBroker("JPMorgan Chase & Co.", 0.6), entities.NewBroker("JPM Securities", 0.6)
+	goldman := entities.NewBroker("Goldman Sachs", 0.95)
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "JP Morgan", Action: "upgraded by", EventTime: eventTime},
+		{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: eventTime},
+	}
+	stored := []*entities.Stock{
+		{ID: uuid.New(), Ticker: "AAPL", BrokerID: jpmChase.ID, Brokerage: jpmChase.Name, Source: "vendor_a", EventTime: eventTime.Add(-time.Minute)},
+		{ID: uuid.New(), Ticker: "MSFT", BrokerID: jpmSecurities.ID, Brokerage: jpmSecurities.Name, Source: "vendor_a", EventTime: eventTime.Add(-time.Minute)},
+	}
+
+	suite.noAliases.Unset()
+	suite.useCase.SetSourcePriority([]string{"vendor_a", "api"}, time.Hour)
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{jpMorgan, jpmChase, jpmSecurities, goldman}, nil)
+	suite.brokerRepo.On("ListAliases", mock.Anything).Return([]*entities.BrokerAlias{
+		entities.NewBrokerAlias(jpMorgan.ID, "JPMorgan Chase"),
+	}, nil)
+	suite.stockRepo.On("GetNearbyEvents", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(stored, nil)
+	// Goldman Sachs is another broker than JPM Securities, so only the Microsoft event is stored
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Ticker == "MSFT"
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.stockRepo.AssertExpectations(suite.T())
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), 1, run.DuplicateRecords)
+		assert.Equal(suite.T(), 1, run.InsertedRecords)
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestBackfill_ReloadsWindowOnly() {
+	// Arrange
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	from, to := now.Add(-10*24*time.Hour), now.Add(-2*24*time.Hour)
+	testStocks := []*entities.Stock{
+		{Ticker: "NEWER", Company: "After Window", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: now.Add(-24 * time.Hour)},
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: now.Add(-5 * 24 * time.Hour)},
+		{Ticker: "OLDER", Company: "Before Window", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: now.Add(-20 * 24 * time.Hour)},
+	}
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	// Pages after the one crossing the start of the window are never requested
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, "page-2"), nil).Once()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "page-2").Return(apiPage(nil, ""), nil).Maybe()
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Ticker == "AAPL"
+	})).Return(&repositories.UpsertResult{Updated: 1}, nil)
+
+	// Act
+	run, err := suite.useCase.Backfill(ctx, from, to)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), entities.IngestionStatusCompleted, run.Status)
+		assert.Equal(suite.T(), 1, run.UpdatedRecords)
+	}
+	checkpoint := suite.savedCheckpoint()
+	if assert.NotNil(suite.T(), checkpoint) {
+		// Backfills never disturb the checkpoint the regular runs resume from
+		assert.Equal(suite.T(), "api:backfill", checkpoint.Source)
+		assert.True(suite.T(), checkpoint.Watermark.Equal(from))
+		assert.True(suite.T(), checkpoint.IsCompleted())
+	}
+	suite.checkpointRepo.AssertNotCalled(suite.T(), "GetLatest", mock.Anything, "api")
+}
+
+func (suite *StockIngestionUseCaseSuite) TestBackfill_InvalidWindow() {
+	// Arrange
+	now := time.Now()
+
+	// Act
+	run, err := suite.useCase.Backfill(context.Background(), now, now.Add(-time.Hour))
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), run)
+	suite.ingestionLogRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *StockIngestionUseCaseSuite) TestDryRun_ReportsChangesWithoutWriting() {
+	// Arrange
+	ctx := context.Background()
+	eventTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	goldman := entities.NewBroker("Goldman Sachs", 0.95)
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: eventTime},
+		{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: eventTime},
+		{Ticker: "GOOG", Company: "Alphabet", Brokerage: "New Research", Action: "upgraded by", EventTime: eventTime},
+	}
+	page := apiPage(testStocks, "")
+	page.Items = append(page.Items, clients.StockAPIItem{Ticker: "BAD", Company: "Bad Time", Brokerage: "Goldman Sachs", Action: "upgraded by", Time: "not-a-time"})
+	stored := []*entities.Stock{
+		{Ticker: "MSFT", Company: "Microsoft", BrokerID: goldman.ID, Action: "upgraded by", Currency: "USD", EventTime: eventTime},
+		{Ticker: "GOOG", Company: "Alphabet", BrokerID: goldman.ID, Action: "upgraded by", RatingTo: "Buy", Currency: "USD", EventTime: eventTime},
+	}
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(page, nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{goldman}, nil)
+	suite.stockRepo.On("GetNearbyEvents", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(stored, nil)
+
+	// Act
+	report, err := suite.useCase.DryRun(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	if assert.NotNil(suite.T(), report) {
+		assert.Equal(suite.T(), "api", report.Source)
+		assert.Equal(suite.T(), 1, report.Pages)
+		assert.Equal(suite.T(), 1, report.New)
+		assert.Equal(suite.T(), 1, report.Unchanged)
+		if assert.Len(suite.T(), report.Changed, 1) {
+			assert.Equal(suite.T(), "GOOG", report.Changed[0].Ticker)
+			assert.Equal(suite.T(), []string{"broker_id", "rating_to"}, report.Changed[0].Fields)
+		}
+		if assert.Len(suite.T(), report.Invalid, 1) {
+			assert.Equal(suite.T(), "BAD", report.Invalid[0].Ticker)
+		}
+		assert.Equal(suite.T(), []string{"New Research"}, report.NewBrokers)
+	}
+	suite.stockRepo.AssertNotCalled(suite.T(), "BulkUpsert", mock.Anything, mock.Anything)
+	suite.brokerRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.deadLetterRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.checkpointRepo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything)
+	suite.ingestionLogRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_SkipsWhileAnotherReplicaHoldsLease() {
+	// Arrange
+	ctx := context.Background()
+	leaseRepo := memory.NewLeaseRepository()
+	_, err := leaseRepo.TryAcquire(ctx, "stock-ingestion", "other-replica", time.Minute)
+	suite.Require().NoError(err)
+
+	suite.useCase.SetLeaderElection(usecases.NewLeaderElector(leaseRepo, "this-replica", time.Minute, suite.logger), "stock-ingestion")
+
+	// Act
+	run, err := suite.useCase.IngestRun(ctx)
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, repositories.ErrLeaseHeld)
+	assert.Nil(suite.T(), run)
+	suite.apiClient.AssertNotCalled(suite.T(), "FetchRawPage", mock.Anything, mock.Anything)
+	suite.ingestionLogRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_HoldsLeaseWhileWriting() {
+	// Arrange
+	ctx := context.Background()
+	leaseRepo := memory.NewLeaseRepository()
+	testStocks := []*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}
+
+	suite.useCase.SetLeaderElection(usecases.NewLeaderElector(leaseRepo, "this-replica", time.Minute, suite.logger), "stock-ingestion")
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(apiPage(testStocks, ""), nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Run(func(mock.Arguments) {
+		_, err := leaseRepo.TryAcquire(ctx, "stock-ingestion", "other-replica", time.Minute)
+		assert.ErrorIs(suite.T(), err, repositories.ErrLeaseHeld)
+	}).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	_, err = leaseRepo.TryAcquire(ctx, "stock-ingestion", "other-replica", time.Minute)
+	assert.NoError(suite.T(), err, "the lease is released once the run ends")
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_KeepsRawPayloads() {
+	// Arrange
+	ctx := context.Background()
+	page := apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}, "")
+	page.Items[0].TargetTo = "$1,200.00"
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(page, nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		payload := stocks[0].Payload
+		return len(stocks) == 1 && stocks[0].TargetTo == 1200 && payload != nil &&
+			payload.Source == "api" && !payload.FetchedAt.IsZero() &&
+			strings.Contains(string(payload.Payload), `"target_to":"$1,200.00"`)
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	upserted := suite.stockRepo.Calls[0].Arguments.Get(1).([]*entities.Stock)
+	assert.Equal(suite.T(), suite.recordedRun().BatchID, upserted[0].Payload.BatchID)
+}
+
+// storedPayload stores item as the raw payload of an event fetched from the API
+func storedPayload(item clients.StockAPIItem, ticker string, eventTime time.Time) repositories.StoredPayload {
+	raw, _ := json.Marshal(item)
+	payload := entities.NewStockPayload("batch-1", "api", eventTime, raw)
+	payload.StockID = uuid.New()
+	return repositories.StoredPayload{Payload: payload, Ticker: ticker, EventTime: eventTime}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestReprocess_RederivesStocksFromStoredPayloads() {
+	// Arrange
+	ctx := context.Background()
+	eventTime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	item := clients.StockAPIItem{
+		Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by",
+		TargetFrom: "$150.00", TargetTo: "$1,200.00", Time: eventTime.Format(time.RFC3339),
+	}
+	unparseable := clients.StockAPIItem{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Goldman Sachs", Action: "upgraded by", Time: "yesterday"}
+	payloads := []repositories.StoredPayload{
+		storedPayload(item, "AAPL", eventTime),
+		storedPayload(unparseable, "MSFT", eventTime),
+	}
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Warn", "Failed to reprocess raw payload", "stockID", payloads[1].Payload.StockID, "error", mock.Anything).Once()
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("ListPayloads", mock.Anything, repositories.PayloadFilter{Source: "api", BatchID: "batch-1", Limit: 500}).Return(payloads, nil).Once()
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Ticker == "AAPL" && stocks[0].EventTime.Equal(eventTime) &&
+			stocks[0].TargetFrom == 150 && stocks[0].TargetTo == 1200 && stocks[0].Source == "api" &&
+			stocks[0].ActionType == entities.ActionTypeUpgrade && stocks[0].Payload == nil
+	})).Return(&repositories.UpsertResult{Updated: 1}, nil)
+
+	// Act
+	run, err := suite.useCase.Reprocess(ctx, repositories.PayloadFilter{BatchID: "batch-1"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	if assert.NotNil(suite.T(), run) {
+		assert.True(suite.T(), run.IsCompleted())
+		assert.Equal(suite.T(), 2, run.TotalRecords)
+		assert.Equal(suite.T(), 1, run.UpdatedRecords)
+		assert.Equal(suite.T(), 1, run.FailedRecords)
+	}
+	suite.apiClient.AssertNotCalled(suite.T(), "FetchRawPage", mock.Anything, mock.Anything)
+}
+
+func (suite *StockIngestionUseCaseSuite) TestReprocess_SkipsPayloadsParsedToAnotherEvent() {
+	// Arrange
+	ctx := context.Background()
+	eventTime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	item := clients.StockAPIItem{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", Time: eventTime.Format(time.RFC3339)}
+	rekeyed := storedPayload(item, "AAPL", eventTime.Add(-time.Hour))
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Warn", "Failed to reprocess raw payload", "stockID", rekeyed.Payload.StockID, "error", mock.Anything).Once()
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{}, nil)
+	suite.stockRepo.On("ListPayloads", mock.Anything, mock.AnythingOfType("repositories.PayloadFilter")).Return([]repositories.StoredPayload{rekeyed}, nil).Once()
+
+	// Act
+	run, err := suite.useCase.Reprocess(ctx, repositories.PayloadFilter{})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, run.FailedRecords)
+	suite.stockRepo.AssertNotCalled(suite.T(), "BulkUpsert", mock.Anything, mock.Anything)
+}
+
+// withRawItems keeps the items of page as received, adding extra to each of them
+func withRawItems(page *clients.StockAPIResponse, extra map[string]string) *clients.StockAPIResponse {
+	for _, item := range page.Items {
+		encoded, _ := json.Marshal(item)
+		var fields map[string]interface{}
+		_ = json.Unmarshal(encoded, &fields)
+		for key, value := range extra {
+			fields[key] = value
+		}
+		raw, _ := json.Marshal(fields)
+		page.RawItems = append(page.RawItems, raw)
+	}
+	return page
+}
+
+// enableSchemaDrift turns on drift detection against baseline, or without a baseline when it is nil
+func (suite *StockIngestionUseCaseSuite) enableSchemaDrift(baseline *entities.SchemaProfile, fail bool) *mocks.MockSchemaProfileRepository {
+	profileRepo := &mocks.MockSchemaProfileRepository{}
+	if baseline != nil {
+		profileRepo.On("GetBaseline", mock.Anything, "api").Return(baseline, nil)
+	} else {
+		profileRepo.On("GetBaseline", mock.Anything, "api").Return(nil, repositories.ErrNotFound)
+	}
+
+	suite.useCase.SetSchemaDrift(profileRepo, usecases.SchemaDriftPolicy{
+		Fail:       fail,
+		MinRecords: 1,
+		Thresholds: entities.DriftThresholds{MissingRate: 0.2, ParseFailureRate: 0.05},
+	})
+	return profileRepo
+}
+
+// savedProfile returns the schema profile passed to the last Save call
+func savedProfile(profileRepo *mocks.MockSchemaProfileRepository) *entities.SchemaProfile {
+	var profile *entities.SchemaProfile
+	for _, call := range profileRepo.Calls {
+		if call.Method == "Save" {
+			profile = call.Arguments.Get(1).(*entities.SchemaProfile)
+		}
+	}
+	return profile
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_FirstSchemaProfileBecomesBaseline() {
+	// Arrange
+	ctx := context.Background()
+	page := withRawItems(apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}, ""), nil)
+
+	profileRepo := suite.enableSchemaDrift(nil, true)
+	profileRepo.On("Save", mock.Anything, mock.AnythingOfType("*entities.SchemaProfile")).Return(nil).Once()
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(page, nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	profile := savedProfile(profileRepo)
+	if assert.NotNil(suite.T(), profile) {
+		assert.True(suite.T(), profile.IsBaseline)
+		assert.Equal(suite.T(), suite.recordedRun().BatchID, profile.BatchID)
+		assert.Equal(suite.T(), 1, profile.Records)
+		assert.Empty(suite.T(), profile.Drift)
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_FlagsSchemaDrift() {
+	// Arrange
+	ctx := context.Background()
+	page := withRawItems(apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}, ""), map[string]string{"price_target": "$180"})
+	baseline := entities.NewSchemaProfile("baseline", "api")
+	baseline.Records = 100
+
+	profileRepo := suite.enableSchemaDrift(baseline, false)
+	profileRepo.On("Save", mock.Anything, mock.AnythingOfType("*entities.SchemaProfile")).Return(nil).Once()
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Warn", "Upstream schema drifted from baseline", "source", "api", "drift", mock.Anything).Once()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(page, nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]*entities.Stock")).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	profile := savedProfile(profileRepo)
+	if assert.NotNil(suite.T(), profile) {
+		assert.False(suite.T(), profile.IsBaseline)
+		assert.Contains(suite.T(), profile.Drift, entities.SchemaDrift{Kind: entities.SchemaDriftUnknownKey, Field: "price_target", Current: 1})
+	}
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_FailsOnSchemaDrift() {
+	// Arrange
+	ctx := context.Background()
+	page := withRawItems(apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+	}, ""), map[string]string{"price_target": "$180"})
+	baseline := entities.NewSchemaProfile("baseline", "api")
+	baseline.Records = 100
+
+	profileRepo := suite.enableSchemaDrift(baseline, true)
+	profileRepo.On("Save", mock.Anything, mock.AnythingOfType("*entities.SchemaProfile")).Return(nil).Once()
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Error", "Stopped ingestion on upstream schema drift", "page", 1, "error", mock.Anything).Once()
+	suite.logger.On("Warn", "Upstream schema drifted from baseline", "source", "api", "drift", mock.Anything).Once()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(page, nil)
+
+	// Act
+	err := suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, usecases.ErrSchemaDrift)
+	assert.Contains(suite.T(), err.Error(), "unknown_key price_target")
+	suite.stockRepo.AssertNotCalled(suite.T(), "BulkUpsert", mock.Anything, mock.Anything)
+	run := suite.recordedRun()
+	if assert.NotNil(suite.T(), run) {
+		assert.Equal(suite.T(), entities.IngestionStatusFailed, run.Status)
+		assert.Equal(suite.T(), "schema_drift", run.ErrorDetails["reason"])
+	}
+	assert.NotEmpty(suite.T(), savedProfile(profileRepo).Drift)
+}
+
+func (suite *StockIngestionUseCaseSuite) TestIngestStocks_AppliesQualityRules() {
+	// Arrange
+	ctx := context.Background()
+	rules, err := entities.ParseQualityRules([]byte(`[
+		{"name": "target_to_positive", "severity": "warn", "field": "target_to", "min": 0.01},
+		{"name": "event_time_not_future", "severity": "quarantine", "field": "event_time", "max_ahead": "1h"},
+		{"name": "brokerage_known", "severity": "reject", "field": "brokerage", "one_of": ["Goldman Sachs"]}
+	]`))
+	suite.Require().NoError(err)
+	summaryRepo := &mocks.MockQualitySummaryRepository{}
+	summaryRepo.On("Save", mock.Anything, mock.AnythingOfType("*entities.QualitySummary")).Return(nil).Once()
+	suite.useCase.SetQualityRules(rules, summaryRepo)
+
+	page := apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+		{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now().Add(3 * time.Hour)},
+		{Ticker: "TSLA", Company: "Tesla", Brokerage: "Shady Research", Action: "upgraded by", EventTime: time.Now()},
+	}, "")
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Warn", "Quarantined upstream record", "ticker", "MSFT", "reason", mock.Anything).Once()
+	suite.logger.On("Warn", "Rejected upstream record", "ticker", "TSLA", "reason", mock.Anything).Once()
+	suite.logger.On("Warn", "Upstream records broke data-quality rules", "batchID", mock.Anything,
+		"warned", 1, "quarantined", 1, "rejected", 1, "rules", "brokerage_known, event_time_not_future, target_to_positive").Once()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(page, nil)
+	suite.deadLetterRepo.On("Create", mock.Anything, mock.MatchedBy(func(record *entities.DeadLetterRecord) bool {
+		return strings.Contains(record.Reason, "quality rule event_time_not_future")
+	})).Return(nil).Once()
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("BulkUpsert", mock.Anything, mock.MatchedBy(func(stocks []*entities.Stock) bool {
+		return len(stocks) == 1 && stocks[0].Ticker == "AAPL"
+	})).Return(&repositories.UpsertResult{Inserted: 1}, nil)
+
+	// Act
+	err = suite.useCase.IngestStocks(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	summaryRepo.AssertExpectations(suite.T())
+	summary := summaryRepo.Calls[0].Arguments.Get(1).(*entities.QualitySummary)
+	assert.Equal(suite.T(), suite.recordedRun().BatchID, summary.BatchID)
+	assert.Equal(suite.T(), 3, summary.Checked)
+	assert.Equal(suite.T(), 0, summary.Passed)
+	assert.Equal(suite.T(), 3, summary.Rules["target_to_positive"].Violations)
+	assert.Equal(suite.T(), 2, suite.recordedRun().FailedRecords)
+}
+
+func (suite *StockIngestionUseCaseSuite) TestDryRun_ReportsQualityWithoutSavingSummary() {
+	// Arrange
+	ctx := context.Background()
+	rules, err := entities.ParseQualityRules([]byte(`[
+		{"name": "brokerage_known", "severity": "reject", "field": "brokerage", "one_of": ["Goldman Sachs"]}
+	]`))
+	suite.Require().NoError(err)
+	summaryRepo := &mocks.MockQualitySummaryRepository{}
+	suite.useCase.SetQualityRules(rules, summaryRepo)
+
+	page := apiPage([]*entities.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by", EventTime: time.Now()},
+		{Ticker: "TSLA", Company: "Tesla", Brokerage: "Shady Research", Action: "upgraded by", EventTime: time.Now()},
+	}, "")
+
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.apiClient.On("FetchRawPage", mock.Anything, "").Return(page, nil)
+	suite.brokerRepo.On("GetAll", mock.Anything).Return([]*entities.Broker{entities.NewBroker("Goldman Sachs", 0.95)}, nil)
+	suite.stockRepo.On("GetNearbyEvents", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*entities.Stock{}, nil)
+
+	// Act
+	report, err := suite.useCase.DryRun(ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	if assert.NotNil(suite.T(), report.Quality) {
+		assert.Equal(suite.T(), 2, report.Quality.Checked)
+		assert.Equal(suite.T(), 1, report.Quality.Rejected)
+	}
+	if assert.Len(suite.T(), report.Invalid, 1) {
+		assert.Equal(suite.T(), "TSLA", report.Invalid[0].Ticker)
+	}
+	summaryRepo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything)
+}
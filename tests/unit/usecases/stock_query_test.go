@@ -0,0 +1,132 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/entities"
+	"stock-tracker/internal/domain/repositories"
+	"stock-tracker/internal/domain/usecases"
+	"stock-tracker/tests/mocks"
+)
+
+func newStockQueryUseCase(t *testing.T) (usecases.StockUseCase, *mocks.MockStockRepository, *mocks.MockLogger) {
+	fxRates := entities.NewFXTable()
+	require.NoError(t, fxRates.Set("USD", "EUR", 0.9))
+	require.NoError(t, fxRates.Set("USD", "GBP", 0.8))
+
+	stockRepo := &mocks.MockStockRepository{}
+	logger := &mocks.MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	return usecases.NewStockQueryUseCase(stockRepo, &mocks.MockBrokerRepository{}, fxRates, logger), stockRepo, logger
+}
+
+func TestStockQuery_GetStocksByTicker_ConvertsTargets(t *testing.T) {
+	// Arrange
+	eventTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	useCase, stockRepo, logger := newStockQueryUseCase(t)
+	inDollars := entities.NewStock("SHEL", "Shell plc", "Goldman Sachs", "target raised by", eventTime)
+	inDollars.TargetFrom, inDollars.TargetTo = 30, 35
+	inPounds := entities.NewStock("SHEL", "Shell plc", "Barclays", "target raised by", eventTime)
+	inPounds.TargetFrom, inPounds.TargetTo, inPounds.Currency = 24, 28, "GBP"
+	inYen := entities.NewStock("SHEL", "Shell plc", "Nomura", "target raised by", eventTime)
+	inYen.TargetFrom, inYen.TargetTo, inYen.Currency = 4000, 4500, "JPY"
+	stockRepo.On("GetByTicker", mock.Anything, "SHEL").Return([]*entities.Stock{inDollars, inPounds, inYen}, nil)
+	logger.On("Warn", "No exchange rate for stock targets", "ticker", "SHEL", "from", "JPY", "to", "EUR").Once()
+
+	// Act
+	result, err := useCase.GetStocksByTicker(context.Background(), "SHEL", nil, "eur")
+
+	// Assert
+	require.NoError(t, err)
+	stocks := result.([]*entities.Stock)
+	assert.Equal(t, []float64{27, 31.5}, []float64{stocks[0].TargetFrom, stocks[0].TargetTo})
+	assert.Equal(t, "EUR", stocks[0].Currency)
+	assert.Equal(t, []float64{27, 31.5}, []float64{stocks[1].TargetFrom, stocks[1].TargetTo})
+	assert.Equal(t, "EUR", stocks[1].Currency)
+	assert.Equal(t, "JPY", stocks[2].Currency)
+	assert.Equal(t, 4500.0, stocks[2].TargetTo)
+	logger.AssertExpectations(t)
+}
+
+func TestStockQuery_GetStocksByTicker_UnsupportedCurrency(t *testing.T) {
+	// Arrange
+	useCase, stockRepo, _ := newStockQueryUseCase(t)
+
+	// Act
+	_, err := useCase.GetStocksByTicker(context.Background(), "SHEL", nil, "CHF")
+
+	// Assert
+	assert.ErrorIs(t, err, usecases.ErrUnsupportedCurrency)
+	stockRepo.AssertNotCalled(t, "GetByTicker", mock.Anything, mock.Anything)
+}
+
+func TestStockQuery_GetStockByID_ConvertsTargets(t *testing.T) {
+	// Arrange
+	useCase, stockRepo, _ := newStockQueryUseCase(t)
+	stock := entities.NewStock("AAPL", "Apple Inc.", "Goldman Sachs", "upgraded by", time.Now())
+	stock.TargetFrom, stock.TargetTo = 150, 200
+	stockRepo.On("GetByID", mock.Anything, stock.ID).Return(stock, nil)
+
+	// Act
+	result, err := useCase.GetStockByID(context.Background(), stock.ID, "GBP")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []float64{120, 160}, []float64{result.TargetFrom, result.TargetTo})
+	assert.Equal(t, "GBP", result.Currency)
+}
+
+func TestStockQuery_GetTickerConsensus(t *testing.T) {
+	// Arrange
+	fxRates := entities.NewFXTable()
+	require.NoError(t, fxRates.Set("USD", "GBP", 0.8))
+	stockRepo := &mocks.MockStockRepository{}
+	brokerRepo := &mocks.MockBrokerRepository{}
+	logger := &mocks.MockLogger{}
+	useCase := usecases.NewStockQueryUseCase(stockRepo, brokerRepo, fxRates, logger)
+
+	goldman := entities.NewBroker("Goldman Sachs", 0.9)
+	barclays := entities.NewBroker("Barclays", 0.3)
+	eventTime := time.Now().Add(-time.Hour)
+	inDollars := entities.NewStock("SHEL", "Shell plc", goldman.Name, "upgraded by", eventTime)
+	inDollars.BrokerID, inDollars.RatingTo, inDollars.TargetTo = goldman.ID, "Buy", 35
+	inPounds := entities.NewStock("SHEL", "Shell plc", barclays.Name, "downgraded by", eventTime)
+	inPounds.BrokerID, inPounds.RatingTo, inPounds.TargetTo, inPounds.Currency = barclays.ID, "Sell", 24, "GBP"
+	credibility := map[uuid.UUID]float64{goldman.ID: goldman.CredibilityScore, barclays.ID: barclays.CredibilityScore}
+	stockRepo.On("GetByTickerWithCredibility", mock.Anything, "SHEL").Return([]*entities.Stock{inDollars, inPounds}, credibility, nil)
+
+	// Act
+	consensus, err := useCase.GetTickerConsensus(context.Background(), "SHEL", "")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entities.DefaultCurrency, consensus.Currency)
+	assert.Equal(t, 2, consensus.CoveringBrokers)
+	// (0.8*0.9 + 0.2*0.3) / 1.2
+	require.NotNil(t, consensus.Score)
+	assert.InDelta(t, 0.65, *consensus.Score, 1e-9)
+	require.NotNil(t, consensus.Targets)
+	assert.Equal(t, 30.0, consensus.Targets.Low)
+	assert.Equal(t, 35.0, consensus.Targets.High)
+	brokerRepo.AssertNotCalled(t, "GetAll", mock.Anything)
+}
+
+func TestStockQuery_GetTickerConsensus_UnknownTicker(t *testing.T) {
+	// Arrange
+	useCase, stockRepo, _ := newStockQueryUseCase(t)
+	stockRepo.On("GetByTickerWithCredibility", mock.Anything, "NOPE").Return([]*entities.Stock{}, map[uuid.UUID]float64{}, nil)
+
+	// Act
+	_, err := useCase.GetTickerConsensus(context.Background(), "NOPE", "")
+
+	// Assert
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
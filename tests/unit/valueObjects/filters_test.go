@@ -0,0 +1,20 @@
+package valueObjects_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"stock-tracker/internal/domain/valueObjects"
+)
+
+func TestStockFilters_Validate_Paging(t *testing.T) {
+	filters := valueObjects.StockFilters{Limit: 1000, SortBy: "ticker", SortOrder: "asc"}
+	assert.NoError(t, filters.Validate())
+
+	filters.Limit = 1001
+	assert.EqualError(t, filters.Validate(), "limit must be at most 1000")
+
+	filters.Limit, filters.Offset = 1000, -1
+	assert.EqualError(t, filters.Validate(), "offset must not be negative")
+}
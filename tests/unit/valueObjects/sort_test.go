@@ -0,0 +1,58 @@
+package valueObjects_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"stock-tracker/internal/domain/valueObjects"
+)
+
+func TestParseSort(t *testing.T) {
+	keys, err := valueObjects.ParseSort("-target_change, ticker,+broker_credibility")
+
+	require.NoError(t, err)
+	assert.Equal(t, []valueObjects.SortKey{
+		{Field: valueObjects.SortTargetChange, Descending: true},
+		{Field: valueObjects.SortTicker},
+		{Field: valueObjects.SortBrokerCredibility},
+	}, keys)
+}
+
+func TestParseSort_Rejects(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          string
+		expectedError string
+	}{
+		{name: "Unknown field", spec: "ticker,price", expectedError: `cannot sort by "price"`},
+		{name: "SQL", spec: "ticker; DROP TABLE stocks", expectedError: `cannot sort by "ticker; DROP TABLE stocks"`},
+		{name: "Repeated field", spec: "ticker,-ticker", expectedError: `sort lists "ticker" more than once`},
+		{name: "Empty field", spec: "ticker,,company", expectedError: "sort must not contain empty fields"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := valueObjects.ParseSort(tc.spec)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestStockFilters_SortKeys(t *testing.T) {
+	filters := valueObjects.StockFilters{SortBy: "ticker", SortOrder: "DESC"}
+	assert.Equal(t, []valueObjects.SortKey{{Field: "ticker", Descending: true}}, filters.SortKeys())
+
+	filters.Sort = []valueObjects.SortKey{{Field: "company"}}
+	assert.Equal(t, []valueObjects.SortKey{{Field: "company"}}, filters.SortKeys())
+}
+
+func TestStockFilters_Validate_RejectsUnsafeSortBy(t *testing.T) {
+	filters := valueObjects.StockFilters{SortBy: "ticker; DROP TABLE stocks"}
+	filters.SetDefaults()
+
+	assert.Error(t, filters.Validate())
+}